@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"trainee/config"
 	"trainee/config/container"
+	"trainee/internal/app"
 	"trainee/internal/infra/database"
 	"trainee/internal/infra/http"
 )
@@ -28,8 +30,10 @@ func main() {
 
 	cont := container.New(conf)
 
+	go app.RunPostScheduler(context.Background(), cont.PostService, conf.PostSchedulerInterval)
+
 	// Echo Server
-	srv := http.NewServer()
+	srv := http.NewServer(conf.TrustedProxies)
 
 	http.EchoRouter(srv, cont)
 
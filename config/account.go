@@ -0,0 +1,40 @@
+package config
+
+import (
+	"log"
+	"os"
+)
+
+// DeletedContent is what happens to the posts or comments of a user who deletes their account.
+type DeletedContent string
+
+const (
+	DeletedContentAnonymize DeletedContent = "anonymize"
+	DeletedContentDelete    DeletedContent = "delete"
+)
+
+// AccountDeletion keeps the content of deleted accounts without an author by default.
+type AccountDeletion struct {
+	Posts    DeletedContent
+	Comments DeletedContent
+}
+
+func LoadAccountDeletionConfiguration() AccountDeletion {
+	return AccountDeletion{
+		Posts:    envDeletedContent("DELETED_USER_POSTS"),
+		Comments: envDeletedContent("DELETED_USER_COMMENTS"),
+	}
+}
+
+func envDeletedContent(name string) DeletedContent {
+	value, set := os.LookupEnv(name)
+	if !set {
+		return DeletedContentAnonymize
+	}
+	switch DeletedContent(value) {
+	case DeletedContentAnonymize, DeletedContentDelete:
+		return DeletedContent(value)
+	}
+	log.Printf("invalid %s %q, using %s", name, value, DeletedContentAnonymize)
+	return DeletedContentAnonymize
+}
@@ -2,10 +2,21 @@ package config
 
 import (
 	"github.com/joho/godotenv"
-	"golang.org/x/oauth2"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EmailVerification decides what an unverified user is kept from doing.
+type EmailVerification string
+
+const (
+	EmailVerificationOff     EmailVerification = ""
+	EmailVerificationLogin   EmailVerification = "login"
+	EmailVerificationContent EmailVerification = "content"
 )
 
 type Configuration struct {
@@ -17,12 +28,37 @@ type Configuration struct {
 	MigrationLocation string
 	AccessSecret      string
 	RefreshSecret     string
-	OAUTH             oauth2.Config
+	JWTKeysDir        string
+	OAuthProviders    map[string]OAuthProvider
 	RedisHost         string
 	RedisPort         string
+	SessionStore      string
+	Session           SessionLifetime
+	AppURL            string
+	// PasswordResetURL is the page reset links open with the token in the query, it asks for the new password
+	PasswordResetURL  string
+	EmailVerification EmailVerification
+	Mail              Mail
+	MFAKey            string
+	MFAIssuer         string
+	PasswordHash      PasswordHash
+	PasswordPolicy    PasswordPolicy
+	AccountDeletion   AccountDeletion
+	// SearchLanguage is the text search configuration new posts and comments are indexed and queries are parsed with
+	SearchLanguage string
+	// PostSchedulerInterval is how often scheduled posts that are due get published
+	PostSchedulerInterval time.Duration
+	// TrustedProxies are the proxies whose X-Forwarded-For is believed, without any the peer address is the client ip
+	TrustedProxies []*net.IPNet
 }
 
 func GetConfiguration() Configuration {
+	// the file goes first so every setting can come from it, variables already set in the environment win
+	err := godotenv.Load(filepath.Join(".env"))
+	if err != nil {
+		log.Print(err)
+	}
+
 	migrationLocation, set := os.LookupEnv("MIGRATION_LOCATION")
 	if !set {
 		migrationLocation = "migrations"
@@ -33,22 +69,77 @@ func GetConfiguration() Configuration {
 		migrateToVersion = "latest"
 	}
 
-	err := godotenv.Load(filepath.Join(".env"))
-	if err != nil {
-		log.Print(err)
+	appURL, set := os.LookupEnv("APP_URL")
+	if !set {
+		appURL = "http://localhost:8080"
+	}
+
+	mfaIssuer, set := os.LookupEnv("MFA_ISSUER")
+	if !set {
+		mfaIssuer = "trainee"
+	}
+
+	passwordResetURL, set := os.LookupEnv("PASSWORD_RESET_URL")
+	if !set {
+		passwordResetURL = appURL + "/password/reset"
+	}
+
+	searchLanguage, set := os.LookupEnv("SEARCH_LANGUAGE")
+	if !set {
+		searchLanguage = "english"
 	}
 
 	return Configuration{
-		DatabaseName:      os.Getenv("DB_NAME"),
-		DatabaseHost:      os.Getenv("DB_HOST"),
-		DatabaseUser:      os.Getenv("DB_USER"),
-		DatabasePassword:  os.Getenv("DB_PASSWORD"),
-		MigrateToVersion:  migrateToVersion,
-		MigrationLocation: migrationLocation,
-		AccessSecret:      os.Getenv("ACCESS_SECRET"),
-		RefreshSecret:     os.Getenv("REFRESH_SECRET"),
-		OAUTH:             LoadOAUTHConfiguration(),
-		RedisPort:         os.Getenv("REDIS_PORT"),
-		RedisHost:         os.Getenv("REDIS_URL"),
+		DatabaseName:          os.Getenv("DB_NAME"),
+		DatabaseHost:          os.Getenv("DB_HOST"),
+		DatabaseUser:          os.Getenv("DB_USER"),
+		DatabasePassword:      os.Getenv("DB_PASSWORD"),
+		MigrateToVersion:      migrateToVersion,
+		MigrationLocation:     migrationLocation,
+		AccessSecret:          os.Getenv("ACCESS_SECRET"),
+		RefreshSecret:         os.Getenv("REFRESH_SECRET"),
+		JWTKeysDir:            os.Getenv("JWT_KEYS_DIR"),
+		OAuthProviders:        LoadOAuthProviders(),
+		RedisPort:             os.Getenv("REDIS_PORT"),
+		RedisHost:             os.Getenv("REDIS_URL"),
+		SessionStore:          os.Getenv("SESSION_STORE"),
+		Session:               LoadSessionLifetimeConfiguration(),
+		AppURL:                appURL,
+		PasswordResetURL:      passwordResetURL,
+		EmailVerification:     EmailVerification(os.Getenv("EMAIL_VERIFICATION")),
+		Mail:                  LoadMailConfiguration(),
+		MFAKey:                os.Getenv("MFA_ENCRYPTION_KEY"),
+		MFAIssuer:             mfaIssuer,
+		PasswordHash:          LoadPasswordHashConfiguration(),
+		PasswordPolicy:        LoadPasswordPolicyConfiguration(),
+		AccountDeletion:       LoadAccountDeletionConfiguration(),
+		SearchLanguage:        searchLanguage,
+		PostSchedulerInterval: envDuration("POST_SCHEDULER_INTERVAL", time.Minute),
+		TrustedProxies:        loadTrustedProxies(),
+	}
+}
+
+// loadTrustedProxies reads the comma separated TRUSTED_PROXIES, each a CIDR range or a single ip.
+func loadTrustedProxies() []*net.IPNet {
+	var proxies []*net.IPNet
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			log.Printf("invalid trusted proxy %q, ignoring it", proxy)
+			continue
+		}
+		proxies = append(proxies, ipNet)
 	}
+	return proxies
 }
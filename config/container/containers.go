@@ -1,16 +1,20 @@
 package container
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-redis/redis/v7"
 	"github.com/upper/db/v4"
 	"github.com/upper/db/v4/adapter/postgresql"
-	"golang.org/x/crypto/bcrypt"
 	"log"
 	"trainee/config"
 	"trainee/internal/app"
 	"trainee/internal/infra/database"
 	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/mail"
+	"trainee/internal/infra/oauth"
+	"trainee/internal/infra/signing"
+	"trainee/internal/infra/store"
 	"trainee/middleware"
 )
 
@@ -25,6 +29,17 @@ type Services struct {
 	app.PostService
 	app.UserService
 	app.AuthService
+	app.OAuthService
+	app.PasswordService
+	app.VerificationService
+	app.MFAService
+	app.APITokenService
+	app.LockoutService
+	app.AuthEventService
+	app.AccountService
+	app.SearchService
+	app.TagService
+	app.PostRevisionService
 }
 
 type Handlers struct {
@@ -32,6 +47,18 @@ type Handlers struct {
 	handlers.PostHandler
 	handlers.RegisterHandler
 	handlers.OauthHandler
+	handlers.SessionHandler
+	handlers.UserHandler
+	handlers.PasswordHandler
+	handlers.VerificationHandler
+	handlers.MFAHandler
+	handlers.APITokenHandler
+	handlers.JWKSHandler
+	handlers.AuthEventHandler
+	handlers.AccountHandler
+	handlers.SearchHandler
+	handlers.TagHandler
+	handlers.PostRevisionHandler
 }
 
 type Middleware struct {
@@ -40,25 +67,67 @@ type Middleware struct {
 
 func New(conf config.Configuration) Container {
 	sess := getDbSess(conf)
-	newRedis := getRedis(conf)
+	sessionStore := getSessionStore(conf)
 
 	userRepository := database.NewUSerRepo(sess)
-	passwordGenerator := app.NewGeneratePasswordHash(bcrypt.DefaultCost)
-	userService := app.NewUserService(userRepository, passwordGenerator)
-	authService := app.NewAuthService(userService, conf, newRedis)
+	passwordGenerator := app.NewPasswordGenerator(conf.PasswordHash)
+	userService := app.NewUserService(userRepository, passwordGenerator, getPasswordPolicy(conf))
+	userTokenRepository := database.NewUserTokenRepo(sess)
+	mailer := mail.NewMailer(conf.Mail)
+	verificationService := app.NewVerificationService(userTokenRepository, userService, mailer, conf)
+	verificationHandler := handlers.NewVerificationHandler(verificationService)
+	recoveryCodeRepository := database.NewRecoveryCodeRepo(sess)
+	lockoutService := app.NewLockoutService(sessionStore)
+	mfaService := app.NewMFAService(userService, recoveryCodeRepository, lockoutService, conf)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+	keySet := getKeySet(conf)
+	jwksHandler := handlers.NewJWKSHandler(keySet)
+	authEventRepository := database.NewAuthEventRepo(sess)
+	authEventService := app.NewAuthEventService(authEventRepository)
+	authEventHandler := handlers.NewAuthEventHandler(authEventService)
+	authService := app.NewAuthService(userService, verificationService, mfaService, lockoutService, authEventService, conf, keySet, sessionStore)
 	registerController := handlers.NewRegisterHandler(authService)
-	oauthController := handlers.NewOauthHandler(userService, authService)
+	identityRepository := database.NewIdentityRepo(sess)
+	oauthService := app.NewOAuthService(identityRepository, userService, authService, authEventService, getOAuthProviders(conf), sessionStore)
+	oauthController := handlers.NewOauthHandler(oauthService)
+	passwordService := app.NewPasswordService(userTokenRepository, userService, authService, authEventService, lockoutService, mailer, conf)
+	passwordHandler := handlers.NewPasswordHandler(passwordService)
 
-	postRepository := database.NewPostRepository(sess)
-	postService := app.NewPostService(postRepository)
+	postRepository := database.NewPostRepository(sess, conf.SearchLanguage)
+	policy := app.NewPolicy()
+	postService := app.NewPostService(postRepository, policy)
 
-	commentRepository := database.NewCommentRepository(sess)
-	commentService := app.NewCommentService(commentRepository, userService, postService)
+	commentRepository := database.NewCommentRepository(sess, conf.SearchLanguage)
+	commentService := app.NewCommentService(commentRepository, userService, postService, policy)
 	commentHandler := handlers.NewCommentHandler(commentService)
 
 	postHandler := handlers.NewPostHandler(postService, commentService)
 
-	authMiddleware := middleware.NewMiddleware(authService, newRedis)
+	postRevisionRepository := database.NewPostRevisionRepo(sess)
+	postRevisionService := app.NewPostRevisionService(postRepository, postRevisionRepository, policy)
+	postRevisionHandler := handlers.NewPostRevisionHandler(postRevisionService)
+
+	sessionHandler := handlers.NewSessionHandler(authService)
+	userHandler := handlers.NewUserHandler(userService, lockoutService, authService)
+
+	apiTokenRepository := database.NewAPITokenRepo(sess)
+	apiTokenService := app.NewAPITokenService(apiTokenRepository, userService, authEventService)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenService)
+
+	accountRepository := database.NewAccountRepo(sess)
+	accountService := app.NewAccountService(accountRepository, identityRepository, postRevisionRepository, userService, authService,
+		postService, commentService, apiTokenService, authEventService, conf)
+	accountHandler := handlers.NewAccountHandler(accountService)
+
+	searchRepository := database.NewSearchRepo(sess, conf.SearchLanguage)
+	searchService := app.NewSearchService(searchRepository)
+	searchHandler := handlers.NewSearchHandler(searchService)
+
+	tagRepository := database.NewTagRepo(sess)
+	tagService := app.NewTagService(tagRepository)
+	tagHandler := handlers.NewTagHandler(tagService)
+
+	authMiddleware := middleware.NewMiddleware(authService, apiTokenService, keySet)
 
 	return Container{
 		Services: Services{
@@ -66,12 +135,35 @@ func New(conf config.Configuration) Container {
 			postService,
 			userService,
 			authService,
+			oauthService,
+			passwordService,
+			verificationService,
+			mfaService,
+			apiTokenService,
+			lockoutService,
+			authEventService,
+			accountService,
+			searchService,
+			tagService,
+			postRevisionService,
 		},
 		Handlers: Handlers{
 			commentHandler,
 			postHandler,
 			registerController,
 			oauthController,
+			sessionHandler,
+			userHandler,
+			passwordHandler,
+			verificationHandler,
+			mfaHandler,
+			apiTokenHandler,
+			jwksHandler,
+			authEventHandler,
+			accountHandler,
+			searchHandler,
+			tagHandler,
+			postRevisionHandler,
 		},
 		Middleware: Middleware{
 			authMiddleware,
@@ -99,3 +191,39 @@ func getRedis(conf config.Configuration) *redis.Client {
 		Addr: addr,
 	})
 }
+
+// getSessionStore keeps sessions in Redis, or with SESSION_STORE=memory in the process for a single instance.
+func getSessionStore(conf config.Configuration) store.SessionStore {
+	if conf.SessionStore == "memory" {
+		return store.NewMemorySessionStore(nil)
+	}
+	return store.NewRedisSessionStore(getRedis(conf))
+}
+
+func getOAuthProviders(conf config.Configuration) oauth.Registry {
+	providers, err := oauth.NewRegistry(context.Background(), conf.OAuthProviders)
+	if err != nil {
+		log.Fatalf("Unable to configure oauth providers: %q\n", err)
+	}
+	return providers
+}
+
+// getKeySet loads the access token keys, without a key directory tokens keep the shared ACCESS_SECRET.
+func getKeySet(conf config.Configuration) *signing.KeySet {
+	if conf.JWTKeysDir == "" {
+		return signing.NewHMACKeySet(conf.AccessSecret)
+	}
+	keys, err := signing.LoadKeySet(conf.JWTKeysDir)
+	if err != nil {
+		log.Fatalf("Unable to load jwt signing keys: %q\n", err)
+	}
+	return keys
+}
+
+func getPasswordPolicy(conf config.Configuration) app.PasswordPolicy {
+	policy, err := app.NewPasswordPolicy(conf.PasswordPolicy)
+	if err != nil {
+		log.Fatalf("Unable to configure password policy: %q\n", err)
+	}
+	return policy
+}
@@ -0,0 +1,28 @@
+package config
+
+import "os"
+
+// Mail holds the SMTP settings. Without a host mails are written to Outbox (or the log) instead of being sent.
+type Mail struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	From     string
+	Outbox   string
+}
+
+func LoadMailConfiguration() Mail {
+	from, set := os.LookupEnv("MAIL_FROM")
+	if !set {
+		from = "no-reply@localhost"
+	}
+	return Mail{
+		Host:     os.Getenv("MAIL_HOST"),
+		Port:     os.Getenv("MAIL_PORT"),
+		User:     os.Getenv("MAIL_USER"),
+		Password: os.Getenv("MAIL_PASSWORD"),
+		From:     from,
+		Outbox:   os.Getenv("MAIL_OUTBOX"),
+	}
+}
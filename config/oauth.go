@@ -1,20 +1,69 @@
 package config
 
 import (
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
+	"fmt"
 	"os"
+	"strings"
 )
 
-func LoadOAUTHConfiguration() oauth2.Config {
-	return oauth2.Config{
-		ClientID:     os.Getenv("CLIENT_ID"),
-		ClientSecret: os.Getenv("CLIENT_SECRET"),
-		Endpoint:     google.Endpoint,
-		RedirectURL:  os.Getenv("REDIRECT_URL"),
-		Scopes: []string{
-			"https://www.googleapis.com/auth/userinfo.profile",
-			"https://www.googleapis.com/auth/userinfo.email",
-		},
+// OAuthProvider holds the client settings of one OAuth/OIDC provider.
+// Endpoints left empty fall back to the provider defaults, or to OIDC discovery through Issuer.
+type OAuthProvider struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	Issuer       string
+	Scopes       []string
+}
+
+// LoadOAuthProviders reads OAUTH_PROVIDERS (comma separated names, "google" by default)
+// and OAUTH_<NAME>_* variables for each of them. Providers without a client id are skipped.
+func LoadOAuthProviders() map[string]OAuthProvider {
+	names, set := os.LookupEnv("OAUTH_PROVIDERS")
+	if !set {
+		names = "google"
+	}
+
+	providers := make(map[string]OAuthProvider)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		provider := loadOAuthProvider(name)
+		if provider.ClientID == "" {
+			continue
+		}
+		providers[name] = provider
+	}
+	return providers
+}
+
+func loadOAuthProvider(name string) OAuthProvider {
+	env := func(key string) string {
+		return os.Getenv(fmt.Sprintf("OAUTH_%s_%s", strings.ToUpper(name), key))
+	}
+	provider := OAuthProvider{
+		ClientID:     env("CLIENT_ID"),
+		ClientSecret: env("CLIENT_SECRET"),
+		RedirectURL:  env("REDIRECT_URL"),
+		AuthURL:      env("AUTH_URL"),
+		TokenURL:     env("TOKEN_URL"),
+		UserInfoURL:  env("USERINFO_URL"),
+		Issuer:       env("ISSUER"),
+	}
+	if scopes := env("SCOPES"); scopes != "" {
+		provider.Scopes = strings.Split(scopes, ",")
+	}
+
+	// the variables used before providers became pluggable still configure google
+	if name == "google" && provider.ClientID == "" {
+		provider.ClientID = os.Getenv("CLIENT_ID")
+		provider.ClientSecret = os.Getenv("CLIENT_SECRET")
+		provider.RedirectURL = os.Getenv("REDIRECT_URL")
 	}
+	return provider
 }
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"golang.org/x/crypto/bcrypt"
+	"log"
+	"os"
+	"strconv"
+)
+
+const (
+	PasswordHashBcrypt   = "bcrypt"
+	PasswordHashArgon2id = "argon2id"
+)
+
+// PasswordHash picks the algorithm new password hashes are made with. Raising a parameter rehashes
+// a user's password at their next login, older hashes keep verifying until then.
+type PasswordHash struct {
+	Algorithm     string
+	BcryptCost    int
+	Argon2Memory  uint32
+	Argon2Time    uint32
+	Argon2Threads uint8
+}
+
+func LoadPasswordHashConfiguration() PasswordHash {
+	algorithm, set := os.LookupEnv("PASSWORD_HASH")
+	if !set {
+		algorithm = PasswordHashArgon2id
+	}
+	return PasswordHash{
+		Algorithm:  algorithm,
+		BcryptCost: int(envUint("BCRYPT_COST", uint64(bcrypt.DefaultCost), uint64(bcrypt.MaxCost))),
+		// 19 MiB, 2 passes, 1 lane: the OWASP minimum for argon2id
+		Argon2Memory:  uint32(envUint("ARGON2_MEMORY", 19*1024, 1<<32-1)),
+		Argon2Time:    uint32(envUint("ARGON2_TIME", 2, 1<<32-1)),
+		Argon2Threads: uint8(envUint("ARGON2_THREADS", 1, 255)),
+	}
+}
+
+func envUint(name string, def, max uint64) uint64 {
+	value, set := os.LookupEnv(name)
+	if !set {
+		return def
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || n == 0 || n > max {
+		log.Printf("invalid %s %q, using %d", name, value, def)
+		return def
+	}
+	return n
+}
+
+// PasswordPolicy is what new passwords are checked against. BreachedDir holds the k-anonymity ranges of breached
+// SHA-1 hashes, a PREFIX.txt of SUFFIX:COUNT lines per 5 character prefix like the range API of Have I Been Pwned
+// answers, leave it empty to skip the check.
+type PasswordPolicy struct {
+	MinLength   int
+	MinClasses  int
+	BreachedDir string
+}
+
+func LoadPasswordPolicyConfiguration() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:   int(envUint("PASSWORD_MIN_LENGTH", 8, 128)),
+		MinClasses:  int(envUint("PASSWORD_MIN_CLASSES", 2, 4)),
+		BreachedDir: os.Getenv("PASSWORD_BREACHED_DIR"),
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// SessionLifetime bounds tokens and sessions. A session ends after IdleTimeout without a request or MaxAge
+// after the login, whichever comes first; refreshing the tokens doesn't push MaxAge back.
+type SessionLifetime struct {
+	AccessTTL   time.Duration
+	RefreshTTL  time.Duration
+	IdleTimeout time.Duration
+	MaxAge      time.Duration
+}
+
+func LoadSessionLifetimeConfiguration() SessionLifetime {
+	return SessionLifetime{
+		AccessTTL:   envDuration("ACCESS_TOKEN_TTL", 2*time.Hour),
+		RefreshTTL:  envDuration("REFRESH_TOKEN_TTL", 48*time.Hour),
+		IdleTimeout: envDuration("SESSION_IDLE_TIMEOUT", 10*time.Minute),
+		MaxAge:      envDuration("SESSION_MAX_AGE", 30*24*time.Hour),
+	}
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	value, set := os.LookupEnv(name)
+	if !set {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("invalid %s %q, using %s", name, value, def)
+		return def
+	}
+	return d
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"trainee/config"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+)
+
+//go:generate mockery --dir . --name AccountService --output ./mocks
+type AccountService interface {
+	Delete(userID int64, password string, device domain.Device) error
+	Export(userID int64) (domain.AccountExport, error)
+}
+
+type accountService struct {
+	accountRepo     database.AccountRepo
+	identityRepo    database.IdentityRepo
+	revisionRepo    database.PostRevisionRepo
+	userService     UserService
+	authService     AuthService
+	postService     PostService
+	commentService  CommentService
+	apiTokenService APITokenService
+	eventService    AuthEventService
+	config          config.AccountDeletion
+}
+
+func NewAccountService(ar database.AccountRepo, ir database.IdentityRepo, rr database.PostRevisionRepo, us UserService,
+	as AuthService, ps PostService, cs CommentService, ts APITokenService, es AuthEventService, conf config.Configuration) AccountService {
+	return accountService{
+		accountRepo:     ar,
+		identityRepo:    ir,
+		revisionRepo:    rr,
+		userService:     us,
+		authService:     as,
+		postService:     ps,
+		commentService:  cs,
+		apiTokenService: ts,
+		eventService:    es,
+		config:          conf.AccountDeletion,
+	}
+}
+
+// Delete removes the account of a user who confirmed it with their password, users who only sign in
+// with a provider have none to give. Their posts, with the revisions they wrote, and comments are deleted or anonymized
+// as configured.
+func (a accountService) Delete(userID int64, password string, device domain.Device) error {
+	user, err := a.userService.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("account service error delete: %w", err)
+	}
+	if user.Password != "" && !a.userService.VerifyPassword(user, password) {
+		return fmt.Errorf("account service error delete: %w", ErrWrongPassword)
+	}
+	err = a.accountRepo.Delete(userID, a.config.Posts == config.DeletedContentDelete, a.config.Comments == config.DeletedContentDelete)
+	if err != nil {
+		return fmt.Errorf("account service error delete: %w", err)
+	}
+	// the tokens stop validating with the user gone, dropping the sessions only tidies up
+	err = a.authService.LogoutAll(userID, device)
+	if err != nil {
+		log.Print(err)
+	}
+	return nil
+}
+
+func (a accountService) Export(userID int64) (domain.AccountExport, error) {
+	var export domain.AccountExport
+	var err error
+	export.User, err = a.userService.FindByID(userID)
+	if err != nil {
+		return domain.AccountExport{}, fmt.Errorf("account service error export: %w", err)
+	}
+	export.Posts, err = a.postService.GetPostsByUser(userID)
+	if err != nil {
+		return domain.AccountExport{}, fmt.Errorf("account service error export: %w", err)
+	}
+	export.PostRevisions, err = a.revisionRepo.FindRevisionsByEditor(userID)
+	if err != nil {
+		return domain.AccountExport{}, fmt.Errorf("account service error export: %w", err)
+	}
+	export.Comments, err = a.commentService.GetCommentsByUser(userID)
+	if err != nil {
+		return domain.AccountExport{}, fmt.Errorf("account service error export: %w", err)
+	}
+	export.Identities, err = a.identityRepo.FindByUser(userID)
+	if err != nil {
+		return domain.AccountExport{}, fmt.Errorf("account service error export: %w", err)
+	}
+	export.APITokens, err = a.apiTokenService.List(userID)
+	if err != nil {
+		return domain.AccountExport{}, fmt.Errorf("account service error export: %w", err)
+	}
+	export.Sessions, err = a.authService.GetSessions(userID)
+	if err != nil {
+		return domain.AccountExport{}, fmt.Errorf("account service error export: %w", err)
+	}
+	export.AuthEvents, err = a.authEvents(userID)
+	if err != nil {
+		return domain.AccountExport{}, fmt.Errorf("account service error export: %w", err)
+	}
+	return export, nil
+}
+
+// authEvents reads the whole audit log of the user page by page.
+func (a accountService) authEvents(userID int64) ([]domain.AuthEvent, error) {
+	var events []domain.AuthEvent
+	filter := domain.AuthEventFilter{UserID: userID, Limit: authEventsMaxPage}
+	for {
+		page, err := a.eventService.Find(filter)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
+		if len(page) < filter.Limit {
+			return events, nil
+		}
+		filter.Before = page[len(page)-1].ID
+	}
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"trainee/config"
+	smocks "trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	rmocks "trainee/internal/infra/database/mock"
+)
+
+func Test_accountService_Delete(t *testing.T) {
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	user := domain.User{ID: 2, Password: "hash"}
+
+	tests := []struct {
+		name        string
+		deletion    config.AccountDeletion
+		us          func() UserService
+		accountRepo func() database.AccountRepo
+		as          func() AuthService
+		wantErr     error
+	}{
+		{
+			"anonymize content",
+			config.AccountDeletion{Posts: config.DeletedContentAnonymize, Comments: config.DeletedContentAnonymize},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(user, nil).Times(1).
+					On("VerifyPassword", user, "password").Return(true).Times(1)
+				return mock
+			},
+			func() database.AccountRepo {
+				mock := rmocks.NewAccountRepo(t)
+				mock.On("Delete", int64(2), false, false).Return(nil).Times(1)
+				return mock
+			},
+			func() AuthService {
+				mock := smocks.NewAuthService(t)
+				mock.On("LogoutAll", int64(2), device).Return(nil).Times(1)
+				return mock
+			},
+			nil,
+		},
+		{
+			"delete posts keep comments",
+			config.AccountDeletion{Posts: config.DeletedContentDelete, Comments: config.DeletedContentAnonymize},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(user, nil).Times(1).
+					On("VerifyPassword", user, "password").Return(true).Times(1)
+				return mock
+			},
+			func() database.AccountRepo {
+				mock := rmocks.NewAccountRepo(t)
+				mock.On("Delete", int64(2), true, false).Return(nil).Times(1)
+				return mock
+			},
+			func() AuthService {
+				mock := smocks.NewAuthService(t)
+				mock.On("LogoutAll", int64(2), device).Return(nil).Times(1)
+				return mock
+			},
+			nil,
+		},
+		{
+			"user without password",
+			config.AccountDeletion{Posts: config.DeletedContentDelete, Comments: config.DeletedContentDelete},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("FindByID", int64(2)).Return(domain.User{ID: 2}, nil).Times(1)
+				return mock
+			},
+			func() database.AccountRepo {
+				mock := rmocks.NewAccountRepo(t)
+				mock.On("Delete", int64(2), true, true).Return(nil).Times(1)
+				return mock
+			},
+			func() AuthService {
+				mock := smocks.NewAuthService(t)
+				mock.On("LogoutAll", int64(2), device).Return(nil).Times(1)
+				return mock
+			},
+			nil,
+		},
+		{
+			"wrong password",
+			config.AccountDeletion{Posts: config.DeletedContentAnonymize, Comments: config.DeletedContentAnonymize},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(user, nil).Times(1).
+					On("VerifyPassword", user, "password").Return(false).Times(1)
+				return mock
+			},
+			func() database.AccountRepo { return rmocks.NewAccountRepo(t) },
+			func() AuthService { return smocks.NewAuthService(t) },
+			ErrWrongPassword,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAccountService(tt.accountRepo(), rmocks.NewIdentityRepo(t), rmocks.NewPostRevisionRepo(t), tt.us(), tt.as(),
+				smocks.NewPostService(t), smocks.NewCommentService(t), smocks.NewAPITokenService(t), smocks.NewAuthEventService(t), config.Configuration{AccountDeletion: tt.deletion})
+			err := a.Delete(2, "password", device)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_accountService_Export(t *testing.T) {
+	user := domain.User{ID: 2, Email: "user@example.com"}
+	us := smocks.NewUserService(t)
+	us.On("FindByID", int64(2)).Return(user, nil).Times(1)
+	ps := smocks.NewPostService(t)
+	ps.On("GetPostsByUser", int64(2)).Return([]domain.Post{{ID: 1, UserID: 2}}, nil).Times(1)
+	rr := rmocks.NewPostRevisionRepo(t)
+	rr.On("FindRevisionsByEditor", int64(2)).Return([]domain.PostRevision{{PostID: 1, Revision: 1, EditorID: 2}}, nil).Times(1)
+	cs := smocks.NewCommentService(t)
+	cs.On("GetCommentsByUser", int64(2)).Return([]domain.Comment{{ID: 3, UserID: 2}}, nil).Times(1)
+	ir := rmocks.NewIdentityRepo(t)
+	ir.On("FindByUser", int64(2)).Return([]domain.Identity{{Provider: "google"}}, nil).Times(1)
+	ts := smocks.NewAPITokenService(t)
+	ts.On("List", int64(2)).Return([]domain.APIToken{{ID: 4}}, nil).Times(1)
+	as := smocks.NewAuthService(t)
+	as.On("GetSessions", int64(2)).Return([]domain.Session{{ID: "current"}}, nil).Times(1)
+
+	// the audit log is read until a page comes back short
+	fullPage := make([]domain.AuthEvent, authEventsMaxPage)
+	for i := range fullPage {
+		fullPage[i].ID = int64(1000 - i)
+	}
+	es := smocks.NewAuthEventService(t)
+	es.
+		On("Find", domain.AuthEventFilter{UserID: 2, Limit: authEventsMaxPage}).Return(fullPage, nil).Times(1).
+		On("Find", domain.AuthEventFilter{UserID: 2, Limit: authEventsMaxPage, Before: 801}).Return([]domain.AuthEvent{{ID: 5}}, nil).Times(1)
+
+	a := NewAccountService(rmocks.NewAccountRepo(t), ir, rr, us, as, ps, cs, ts, es, config.Configuration{})
+	export, err := a.Export(2)
+	assert.NoError(t, err)
+	assert.Equal(t, user, export.User)
+	assert.Len(t, export.Posts, 1)
+	assert.Len(t, export.PostRevisions, 1)
+	assert.Len(t, export.Comments, 1)
+	assert.Len(t, export.Identities, 1)
+	assert.Len(t, export.APITokens, 1)
+	assert.Len(t, export.Sessions, 1)
+	assert.Len(t, export.AuthEvents, authEventsMaxPage+1)
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/upper/db/v4"
+	"strings"
+	"time"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+)
+
+const (
+	APITokenPrefix = "pat_"
+	// apiTokenTTL is the lifetime in days of a token created without one
+	apiTokenTTL = 30
+)
+
+var (
+	ErrAPITokenNotFound = errors.New("api token not found")
+	ErrScopeNotAllowed  = errors.New("api token scope not allowed")
+)
+
+//go:generate mockery --dir . --name APITokenService --output ./mocks
+type APITokenService interface {
+	Create(userID int64, name string, scopes []domain.Scope, ttlDays int, device domain.Device) (string, domain.APIToken, error)
+	List(userID int64) ([]domain.APIToken, error)
+	Delete(id, userID int64, device domain.Device) error
+	Authenticate(token string) (domain.User, domain.APIToken, error)
+	Authorize(token domain.APIToken, scope domain.Scope, device domain.Device) error
+	Touch(id int64) error
+}
+
+type apiTokenService struct {
+	tokenRepo    database.APITokenRepo
+	userService  UserService
+	eventService AuthEventService
+}
+
+func NewAPITokenService(tr database.APITokenRepo, us UserService, es AuthEventService) APITokenService {
+	return apiTokenService{
+		tokenRepo:    tr,
+		userService:  us,
+		eventService: es,
+	}
+}
+
+// Create returns the plain token with its record, the plain token can't be recovered later.
+func (a apiTokenService) Create(userID int64, name string, scopes []domain.Scope, ttlDays int, device domain.Device) (string, domain.APIToken, error) {
+	if ttlDays <= 0 {
+		ttlDays = apiTokenTTL
+	}
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", domain.APIToken{}, fmt.Errorf("api token service error create: %w", err)
+	}
+	plain := APITokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	token, err := a.tokenRepo.Save(domain.APIToken{
+		UserID:      userID,
+		Name:        name,
+		Prefix:      plain[:len(APITokenPrefix)+6],
+		Hash:        hashUserToken(plain),
+		Scopes:      scopes,
+		ExpiresDate: time.Now().AddDate(0, 0, ttlDays),
+	})
+	if err != nil {
+		return "", domain.APIToken{}, fmt.Errorf("api token service error create: %w", err)
+	}
+	a.recordToken(domain.AuthEventTokenCreate, domain.AuthSuccess, token, device, "")
+	return plain, token, nil
+}
+
+func (a apiTokenService) List(userID int64) ([]domain.APIToken, error) {
+	tokens, err := a.tokenRepo.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("api token service error list: %w", err)
+	}
+	return tokens, nil
+}
+
+func (a apiTokenService) Delete(id, userID int64, device domain.Device) error {
+	err := a.tokenRepo.Delete(id, userID)
+	if errors.Is(err, db.ErrNoMoreRows) {
+		return fmt.Errorf("api token service error delete: %w", ErrAPITokenNotFound)
+	} else if err != nil {
+		return fmt.Errorf("api token service error delete: %w", err)
+	}
+	a.recordToken(domain.AuthEventTokenRevoke, domain.AuthSuccess, domain.APIToken{ID: id, UserID: userID}, device, "")
+	return nil
+}
+
+func (a apiTokenService) Authenticate(plain string) (domain.User, domain.APIToken, error) {
+	if !strings.HasPrefix(plain, APITokenPrefix) {
+		return domain.User{}, domain.APIToken{}, fmt.Errorf("api token service error authenticate: %w", ErrInvalidToken)
+	}
+	token, err := a.tokenRepo.FindByHash(hashUserToken(plain))
+	if errors.Is(err, db.ErrNoMoreRows) {
+		return domain.User{}, domain.APIToken{}, fmt.Errorf("api token service error authenticate: %w", ErrInvalidToken)
+	} else if err != nil {
+		return domain.User{}, domain.APIToken{}, fmt.Errorf("api token service error authenticate: %w", err)
+	}
+	if !time.Now().Before(token.ExpiresDate) {
+		return domain.User{}, domain.APIToken{}, fmt.Errorf("api token service error authenticate: %w", ErrInvalidToken)
+	}
+	user, err := a.userService.FindByID(token.UserID)
+	if err != nil {
+		return domain.User{}, domain.APIToken{}, fmt.Errorf("api token service error authenticate: %w", ErrInvalidToken)
+	}
+	return user, token, nil
+}
+
+// Authorize checks the token grants the scope a request needs, a refusal goes to the audit log.
+func (a apiTokenService) Authorize(token domain.APIToken, scope domain.Scope, device domain.Device) error {
+	if !token.Allows(scope) {
+		a.recordToken(domain.AuthEventTokenScope, domain.AuthFailure, token, device, fmt.Sprintf("lacks %s scope", scope))
+		return fmt.Errorf("api token service error authorize: %w", ErrScopeNotAllowed)
+	}
+	return nil
+}
+
+// recordToken names the token in the event detail, as its owner may hold several.
+func (a apiTokenService) recordToken(t domain.AuthEventType, outcome domain.AuthOutcome, token domain.APIToken, device domain.Device, reason string) {
+	event := domain.NewAuthEvent(t, outcome, token.UserID, device)
+	event.Detail = fmt.Sprintf("token %d", token.ID)
+	if reason != "" {
+		event.Detail += " " + reason
+	}
+	a.eventService.Record(event)
+}
+
+func (a apiTokenService) Touch(id int64) error {
+	err := a.tokenRepo.Touch(id)
+	if err != nil {
+		return fmt.Errorf("api token service error touch: %w", err)
+	}
+	return nil
+}
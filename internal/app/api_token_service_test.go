@@ -0,0 +1,183 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/upper/db/v4"
+	"strings"
+	"testing"
+	"time"
+	smocks "trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	rmocks "trainee/internal/infra/database/mock"
+)
+
+func Test_apiTokenService_Create(t *testing.T) {
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	var saved domain.APIToken
+	tr := rmocks.NewAPITokenRepo(t)
+	tr.On("Save", mock.AnythingOfType("domain.APIToken")).
+		Run(func(args mock.Arguments) { saved = args.Get(0).(domain.APIToken) }).
+		Return(domain.APIToken{ID: 4, UserID: 1}, nil).Times(1)
+	event := domain.NewAuthEvent(domain.AuthEventTokenCreate, domain.AuthSuccess, 1, device)
+	event.Detail = "token 4"
+	a := NewAPITokenService(tr, smocks.NewUserService(t), recordsEvent(t, &event))
+
+	plain, _, err := a.Create(1, "ci", []domain.Scope{domain.ScopeRead}, 0, device)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plain, APITokenPrefix))
+	assert.Equal(t, hashUserToken(plain), saved.Hash)
+	assert.True(t, strings.HasPrefix(plain, saved.Prefix))
+	assert.NotEqual(t, plain, saved.Prefix)
+	assert.Equal(t, []domain.Scope{domain.ScopeRead}, saved.Scopes)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, apiTokenTTL), saved.ExpiresDate, time.Minute)
+}
+
+func Test_apiTokenService_Authenticate(t *testing.T) {
+	plain := APITokenPrefix + "token"
+	hash := hashUserToken(plain)
+	valid := domain.APIToken{ID: 1, UserID: 2, Hash: hash, ExpiresDate: time.Now().Add(time.Hour)}
+	user := domain.User{ID: 2}
+
+	tests := []struct {
+		name      string
+		plain     string
+		tokenRepo func() database.APITokenRepo
+		us        func() UserService
+		wantErr   error
+	}{
+		{
+			"valid token",
+			plain,
+			func() database.APITokenRepo {
+				mock := rmocks.NewAPITokenRepo(t)
+				mock.On("FindByHash", hash).Return(valid, nil).Times(1)
+				return mock
+			},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("FindByID", int64(2)).Return(user, nil).Times(1)
+				return mock
+			},
+			nil,
+		},
+		{
+			"not a personal token",
+			"eyJhbGciOiJIUzI1NiJ9",
+			func() database.APITokenRepo { return rmocks.NewAPITokenRepo(t) },
+			func() UserService { return smocks.NewUserService(t) },
+			ErrInvalidToken,
+		},
+		{
+			"unknown token",
+			plain,
+			func() database.APITokenRepo {
+				mock := rmocks.NewAPITokenRepo(t)
+				mock.On("FindByHash", hash).Return(domain.APIToken{}, db.ErrNoMoreRows).Times(1)
+				return mock
+			},
+			func() UserService { return smocks.NewUserService(t) },
+			ErrInvalidToken,
+		},
+		{
+			"expired token",
+			plain,
+			func() database.APITokenRepo {
+				expired := valid
+				expired.ExpiresDate = time.Now().Add(-time.Minute)
+				mock := rmocks.NewAPITokenRepo(t)
+				mock.On("FindByHash", hash).Return(expired, nil).Times(1)
+				return mock
+			},
+			func() UserService { return smocks.NewUserService(t) },
+			ErrInvalidToken,
+		},
+		{
+			"deleted user",
+			plain,
+			func() database.APITokenRepo {
+				mock := rmocks.NewAPITokenRepo(t)
+				mock.On("FindByHash", hash).Return(valid, nil).Times(1)
+				return mock
+			},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("FindByID", int64(2)).Return(domain.User{}, db.ErrNoMoreRows).Times(1)
+				return mock
+			},
+			ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAPITokenService(tt.tokenRepo(), tt.us(), recordsEvent(t, nil))
+			got, _, err := a.Authenticate(tt.plain)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, user, got)
+			}
+		})
+	}
+}
+
+func Test_apiTokenService_Delete(t *testing.T) {
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	revoked := domain.NewAuthEvent(domain.AuthEventTokenRevoke, domain.AuthSuccess, 1, device)
+	revoked.Detail = "token 5"
+
+	tests := []struct {
+		name    string
+		repoErr error
+		event   *domain.AuthEvent
+		wantErr error
+	}{
+		{"revoked", nil, &revoked, nil},
+		{"someone else's token", db.ErrNoMoreRows, nil, ErrAPITokenNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := rmocks.NewAPITokenRepo(t)
+			tr.On("Delete", int64(5), int64(1)).Return(tt.repoErr).Times(1)
+			a := NewAPITokenService(tr, smocks.NewUserService(t), recordsEvent(t, tt.event))
+
+			err := a.Delete(5, 1, device)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_apiTokenService_Authorize(t *testing.T) {
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	read := domain.APIToken{ID: 7, UserID: 2, Scopes: []domain.Scope{domain.ScopeRead}}
+	rejected := domain.NewAuthEvent(domain.AuthEventTokenScope, domain.AuthFailure, 2, device)
+	rejected.Detail = "token 7 lacks write scope"
+
+	tests := []struct {
+		name    string
+		scope   domain.Scope
+		event   *domain.AuthEvent
+		wantErr error
+	}{
+		{"read scope", domain.ScopeRead, nil, nil},
+		{"write scope", domain.ScopeWrite, &rejected, ErrScopeNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAPITokenService(rmocks.NewAPITokenRepo(t), smocks.NewUserService(t), recordsEvent(t, tt.event))
+
+			err := a.Authorize(read, tt.scope, device)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
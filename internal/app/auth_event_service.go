@@ -0,0 +1,50 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+)
+
+const (
+	authEventsPage    = 50
+	authEventsMaxPage = 200
+)
+
+//go:generate mockery --dir . --name AuthEventService --output ./mocks
+type AuthEventService interface {
+	Record(event domain.AuthEvent)
+	Find(filter domain.AuthEventFilter) ([]domain.AuthEvent, error)
+}
+
+type authEventService struct {
+	eventRepo database.AuthEventRepo
+}
+
+func NewAuthEventService(er database.AuthEventRepo) AuthEventService {
+	return authEventService{
+		eventRepo: er,
+	}
+}
+
+// Record writes the event to the audit log. A failed write is only logged, it must not fail the action it describes.
+func (s authEventService) Record(event domain.AuthEvent) {
+	_, err := s.eventRepo.Save(event)
+	if err != nil {
+		log.Printf("auth event service error record %s %s: %s", event.Type, event.Outcome, err)
+	}
+}
+
+func (s authEventService) Find(filter domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = authEventsPage
+	} else if filter.Limit > authEventsMaxPage {
+		filter.Limit = authEventsMaxPage
+	}
+	events, err := s.eventRepo.Find(filter)
+	if err != nil {
+		return nil, fmt.Errorf("auth event service error find: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"testing"
+	"trainee/internal/domain"
+	rmocks "trainee/internal/infra/database/mock"
+)
+
+func Test_authEventService_Find(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"default page", 0, authEventsPage},
+		{"page size kept", 10, 10},
+		{"page size capped", 1000, authEventsMaxPage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := rmocks.NewAuthEventRepo(t)
+			repo.On("Find", domain.AuthEventFilter{UserID: 1, Limit: tt.want}).Return([]domain.AuthEvent{{ID: 1}}, nil).Times(1)
+			events, err := NewAuthEventService(repo).Find(domain.AuthEventFilter{UserID: 1, Limit: tt.limit})
+			assert.NoError(t, err)
+			assert.Len(t, events, 1)
+		})
+	}
+}
+
+func Test_authEventService_Record(t *testing.T) {
+	event := domain.AuthEvent{UserID: 1, Type: domain.AuthEventLogin, Outcome: domain.AuthSuccess}
+	repo := rmocks.NewAuthEventRepo(t)
+	repo.On("Save", event).Return(domain.AuthEvent{}, db.ErrCollectionDoesNotExist).Times(1)
+	// a failed write must not reach the caller
+	NewAuthEventService(repo).Record(event)
+}
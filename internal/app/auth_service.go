@@ -4,42 +4,85 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/go-redis/redis/v7"
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"github.com/upper/db/v4"
-	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/sync/errgroup"
+	"log"
+	"sort"
 	"time"
 	"trainee/config"
 	"trainee/internal/domain"
 	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/signing"
+	"trainee/internal/infra/store"
 )
 
 const (
-	refresh = 48
-	access  = 2
-	LogOF   = 10
+	// the pending login of a user with mfa lasts mfaPending minutes and allows mfaAttempts wrong codes
+	mfaPending  = 5
+	mfaAttempts = 5
+	// impersonation access tokens last impersonationTTL minutes and can't be refreshed
+	impersonationTTL = 15
 )
 
+var (
+	ErrInvalidToken     = errors.New("invalid or revoked token")
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrEmailNotVerified = errors.New("email is not verified")
+	ErrCantImpersonate  = errors.New("user can not be impersonated")
+
+	errRefreshReplayed = errors.New("refresh token replayed")
+)
+
+// MFARequiredError is returned by Login when the password was right but the user has to send a code with the token.
+type MFARequiredError struct {
+	Token string
+	Exp   int64
+}
+
+func (e *MFARequiredError) Error() string {
+	return "mfa code required"
+}
+
 //go:generate mockery --dir . --name AuthService --output ./mocks
 type AuthService interface {
 	Register(user domain.User) (domain.User, error)
-	Login(user requests.LoginAuth) (string, string, int64, error)
-	ValidateJWT(tokenUID string, userID int64, isRefresh bool) (domain.User, error)
+	Login(user requests.LoginAuth, device domain.Device) (string, string, int64, error)
+	LoginMFA(mfaToken, code string) (string, string, int64, error)
+	CreateSession(user domain.User, device domain.Device) (string, string, int64, error)
+	BeginSession(user domain.User, device domain.Device) (string, string, int64, error)
+	ValidateJWT(tokenUID, sessionID string, userID int64, role domain.Role, isRefresh bool) (domain.User, error)
+	Refresh(refreshToken string, device domain.Device) (string, string, int64, error)
+	Logout(sessionID string, userID int64, device domain.Device) error
+	LogoutAll(userID int64, device domain.Device) error
+	GetSessions(userID int64) ([]domain.Session, error)
+	RevokeSession(sessionID string, userID int64) error
+	TouchSession(sessionID string) error
+	Impersonate(actorID, userID int64, device domain.Device) (string, int64, error)
 }
 
 type authService struct {
-	userService UserService
-	config      config.Configuration
-	r           *redis.Client
+	userService         UserService
+	verificationService VerificationService
+	mfaService          MFAService
+	lockoutService      LockoutService
+	eventService        AuthEventService
+	config              config.Configuration
+	keys                *signing.KeySet
+	store               store.SessionStore
 }
 
-func NewAuthService(us UserService, cf config.Configuration, red *redis.Client) AuthService {
+func NewAuthService(us UserService, vs VerificationService, ms MFAService, ls LockoutService, es AuthEventService, cf config.Configuration, keys *signing.KeySet, st store.SessionStore) AuthService {
 	return authService{
-		userService: us,
-		config:      cf,
-		r:           red,
+		userService:         us,
+		verificationService: vs,
+		mfaService:          ms,
+		lockoutService:      ls,
+		eventService:        es,
+		config:              cf,
+		keys:                keys,
+		store:               st,
 	}
 }
 
@@ -54,61 +97,194 @@ func (a authService) Register(user domain.User) (domain.User, error) {
 	if err != nil {
 		return domain.User{}, fmt.Errorf("auth service error register save user: %w", err)
 	}
+	// the user is registered either way, a lost mail can be sent again
+	err = a.verificationService.Send(user)
+	if err != nil {
+		log.Print(err)
+	}
 	return user, nil
 }
 
-func (a authService) Login(user requests.LoginAuth) (string, string, int64, error) {
+func (a authService) Login(user requests.LoginAuth, device domain.Device) (string, string, int64, error) {
+	err := a.lockoutService.Check(user.Email, device.IP)
+	if err != nil {
+		var locked *LockedError
+		if errors.As(err, &locked) {
+			a.recordLoginFailure(0, user.Email, "locked", device)
+		}
+		return "", "", 0, fmt.Errorf("auth service error login: %w", err)
+	}
 	u, err := a.userService.FindByEmail(user.Email)
 	if err != nil {
 		if errors.Is(err, db.ErrNoMoreRows) {
-			return "", "", 0, fmt.Errorf("auth service error login, invalid credentials user not exist: %w", err)
+			return "", "", 0, a.loginFailed(0, user.Email, "unknown email", device, fmt.Errorf("auth service error login, invalid credentials user not exist: %w", err))
 		}
 		return "", "", 0, fmt.Errorf("auth service error login user invalid email or password: %w", err)
 	}
-	valid := a.checkPasswordHash(user.Password, u.Password)
+	valid := a.userService.VerifyPassword(u, user.Password)
 	if !valid {
-		return "", "", 0, fmt.Errorf("auth service error login user invalid email or password: %w", err)
+		return "", "", 0, a.loginFailed(u.ID, user.Email, "wrong password", device, fmt.Errorf("auth service error login user invalid email or password: %w", err))
+	}
+	if a.config.EmailVerification == config.EmailVerificationLogin && !u.EmailVerified() {
+		a.recordLoginFailure(u.ID, user.Email, "email not verified", device)
+		return "", "", 0, fmt.Errorf("auth service error login: %w", ErrEmailNotVerified)
+	}
+	return a.BeginSession(u, device)
+}
+
+// loginFailed records and counts the failure, the one that locks the account or ip is answered with the lock instead of err.
+func (a authService) loginFailed(userID int64, email, reason string, device domain.Device, err error) error {
+	a.recordLoginFailure(userID, email, reason, device)
+	if locked := countFailure(a.lockoutService, email, device.IP); locked != nil {
+		return fmt.Errorf("auth service error login: %w", locked)
 	}
-	accessToken, accessUID, exp, err := createToken(u, access, a.config.AccessSecret)
+	return err
+}
+
+// recordLoginFailure writes a failed login to the audit log, userID is 0 when the email belongs to nobody.
+func (a authService) recordLoginFailure(userID int64, email, reason string, device domain.Device) {
+	event := domain.NewAuthEvent(domain.AuthEventLogin, domain.AuthFailure, userID, device)
+	event.Email = email
+	event.Detail = reason
+	a.eventService.Record(event)
+}
+
+// BeginSession is CreateSession for a user who has only passed the first factor,
+// users with mfa get MFARequiredError and finish with LoginMFA.
+func (a authService) BeginSession(u domain.User, device domain.Device) (string, string, int64, error) {
+	if u.MFAEnabled() {
+		return "", "", 0, a.newMFAPending(u, device)
+	}
+	return a.CreateSession(u, device)
+}
+
+type mfaPendingLogin struct {
+	UserID    int64
+	UserAgent string
+	IP        string
+}
+
+func (a authService) newMFAPending(u domain.User, device domain.Device) error {
+	pending, err := json.Marshal(mfaPendingLogin{UserID: u.ID, UserAgent: device.UserAgent, IP: device.IP})
 	if err != nil {
-		return "", "", 0, fmt.Errorf("auth service error login: %w", err)
+		return fmt.Errorf("auth service error login, couldn't marshal mfa pending login: %w", err)
 	}
-	refreshToken, refreshUID, _, err := createToken(u, refresh, a.config.RefreshSecret)
+	token := uuid.New().String()
+	err = a.store.Set(mfaPendingKey(token), string(pending), time.Minute*mfaPending)
 	if err != nil {
-		return "", "", 0, fmt.Errorf("auth service error login: %w", err)
+		return fmt.Errorf("auth service error login: %w", err)
 	}
+	return &MFARequiredError{Token: token, Exp: time.Now().Add(time.Minute * mfaPending).Unix()}
+}
 
-	tokensJSON, err := json.Marshal(RedisToken{
-		AccessID:  accessUID,
-		RefreshID: refreshUID,
-	})
+// LoginMFA finishes a login that Login answered with MFARequiredError.
+func (a authService) LoginMFA(mfaToken, code string) (string, string, int64, error) {
+	key := mfaPendingKey(mfaToken)
+	pendingJSON, err := a.store.Get(key)
+	if errors.Is(err, store.ErrNotFound) {
+		return "", "", 0, fmt.Errorf("auth service error login mfa: %w", ErrInvalidToken)
+	} else if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error login mfa: %w", err)
+	}
+	var pending mfaPendingLogin
+	err = json.Unmarshal([]byte(pendingJSON), &pending)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error login mfa, couldn't unmarshal pending login: %w", err)
+	}
+	u, err := a.userService.FindByID(pending.UserID)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error login mfa: %w", err)
+	}
+
+	pendingDevice := domain.Device{UserAgent: pending.UserAgent, IP: pending.IP}
+	err = a.lockoutService.Check(u.Email, pending.IP)
+	if err != nil {
+		var locked *LockedError
+		if errors.As(err, &locked) {
+			a.recordLoginFailure(u.ID, u.Email, "locked", pendingDevice)
+		}
+		return "", "", 0, fmt.Errorf("auth service error login mfa: %w", err)
+	}
+	// the attempt is counted before the code is checked, so concurrent guesses can't get past the cap
+	attempts, err := a.store.Incr(key+"-attempts", time.Minute*mfaPending)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error login mfa: %w", err)
+	}
+	if attempts > mfaAttempts {
+		a.store.Delete(key, key+"-attempts")
+		return "", "", 0, fmt.Errorf("auth service error login mfa: %w", ErrInvalidToken)
+	}
+	err = a.mfaService.Validate(u, code)
+	if errors.Is(err, ErrInvalidMFACode) {
+		if attempts == mfaAttempts {
+			// too many guesses, the password has to be entered again
+			a.store.Delete(key, key+"-attempts")
+		}
+		// wrong codes count towards the lockout like wrong passwords
+		return "", "", 0, a.loginFailed(u.ID, u.Email, "invalid mfa code", pendingDevice, fmt.Errorf("auth service error login mfa: %w", err))
+	} else if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error login mfa: %w", err)
+	}
+
+	_, err = a.store.Take(key)
+	if errors.Is(err, store.ErrNotFound) {
+		return "", "", 0, fmt.Errorf("auth service error login mfa: %w", ErrInvalidToken)
+	} else if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error login mfa: %w", err)
+	}
+	a.store.Delete(key + "-attempts")
+	return a.CreateSession(u, pendingDevice)
+}
+
+// CreateSession opens a new session for an already authenticated user and issues its token pair.
+func (a authService) CreateSession(u domain.User, device domain.Device) (string, string, int64, error) {
+	now := time.Now()
+	session := domain.Session{
+		ID:         uuid.New().String(),
+		UserID:     u.ID,
+		UserAgent:  device.UserAgent,
+		IP:         device.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(a.config.Session.MaxAge),
+	}
+	accessToken, refreshToken, exp, err := a.createTokenPair(u, &session)
 	if err != nil {
-		return "", "", 0, fmt.Errorf("auth service error, couldn't marshal token pair, %w", err)
+		return "", "", 0, fmt.Errorf("auth service error create session: %w", err)
 	}
 
-	a.r.Set(fmt.Sprintf("token-%d", u.ID), string(tokensJSON), time.Minute*LogOF)
-	return accessToken, refreshToken, exp, err
+	err = a.store.SaveSession(session, a.config.Session.IdleTimeout)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error create session, couldn't save session: %w", err)
+	}
+	// failed logins are forgotten only once every factor has been passed
+	err = a.lockoutService.Reset(u.Email)
+	if err != nil {
+		log.Print(err)
+	}
+	event := domain.NewAuthEvent(domain.AuthEventLogin, domain.AuthSuccess, u.ID, device)
+	event.Email = u.Email
+	a.eventService.Record(event)
+	return accessToken, refreshToken, exp, nil
 }
 
-func (a authService) ValidateJWT(tokenUID string, userID int64, isRefresh bool) (user domain.User, err error) {
+func (a authService) ValidateJWT(tokenUID, sessionID string, userID int64, role domain.Role, isRefresh bool) (user domain.User, err error) {
 	var g errgroup.Group
 	g.Go(func() error {
-		tokensJSON, err := a.r.Get(fmt.Sprintf("token-%d", userID)).Result()
+		session, err := a.getSession(sessionID)
 		if err != nil {
 			return fmt.Errorf("auth service error validate token: %w", err)
 		}
-		var redisToken RedisToken
-		err = json.Unmarshal([]byte(tokensJSON), &redisToken)
 
 		var uid string
 		if isRefresh {
-			uid = redisToken.RefreshID
+			uid = session.RefreshID
 		} else {
-			uid = redisToken.AccessID
+			uid = session.AccessID
 		}
 
-		if err != nil || uid != tokenUID {
-			return fmt.Errorf("token not exist: %w", err)
+		if session.UserID != userID || uid != tokenUID {
+			return fmt.Errorf("auth service error validate token: %w", ErrInvalidToken)
 		}
 		return nil
 	})
@@ -118,6 +294,10 @@ func (a authService) ValidateJWT(tokenUID string, userID int64, isRefresh bool)
 		if err != nil {
 			return fmt.Errorf("auth service error validate jwt invalid credentials user not exist, %w", err)
 		}
+		if user.Role != role {
+			// the role changed after the token was issued, the client has to refresh it
+			return fmt.Errorf("auth service error validate jwt role changed: %w", ErrInvalidToken)
+		}
 		return nil
 	})
 
@@ -126,35 +306,274 @@ func (a authService) ValidateJWT(tokenUID string, userID int64, isRefresh bool)
 	return user, err
 }
 
-func createToken(user domain.User, expireTime int, secret string) (string, string, int64, error) {
-	exp := time.Now().Add(time.Hour * time.Duration(expireTime)).Unix()
+func (a authService) Refresh(refreshToken string, device domain.Device) (string, string, int64, error) {
+	claims, err := parseToken(refreshToken, a.config.RefreshSecret)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error refresh: %w", ErrInvalidToken)
+	}
+	u, err := a.userService.FindByID(claims.ID)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error refresh: %w", ErrInvalidToken)
+	}
+
+	var accessToken, newRefreshToken string
+	var exp int64
+	err = a.store.UpdateSession(claims.SID, a.config.Session.IdleTimeout, func(session *domain.Session) error {
+		if session.UserID != claims.ID {
+			return ErrInvalidToken
+		}
+		if session.RefreshID != claims.UID {
+			return errRefreshReplayed
+		}
+		a.limitSession(session)
+		var err error
+		accessToken, newRefreshToken, exp, err = a.createTokenPair(u, session)
+		if err != nil {
+			return err
+		}
+		session.LastSeenAt = time.Now()
+		return nil
+	})
+	if errors.Is(err, errRefreshReplayed) {
+		// an already rotated refresh token is being replayed, so the session is treated as stolen
+		if delErr := a.store.DeleteSession(claims.SID, claims.ID); delErr != nil {
+			log.Print(delErr)
+		}
+		a.recordRefreshFailure(claims.ID, "refresh token replayed", device)
+		return "", "", 0, fmt.Errorf("auth service error refresh: %w", ErrInvalidToken)
+	} else if errors.Is(err, store.ErrNotFound) || errors.Is(err, ErrInvalidToken) {
+		a.recordRefreshFailure(claims.ID, "session expired or revoked", device)
+		return "", "", 0, fmt.Errorf("auth service error refresh: %w", ErrInvalidToken)
+	} else if errors.Is(err, store.ErrConflict) {
+		return "", "", 0, fmt.Errorf("auth service error refresh: %w", ErrInvalidToken)
+	} else if err != nil {
+		return "", "", 0, fmt.Errorf("auth service error refresh: %w", err)
+	}
+	a.eventService.Record(domain.NewAuthEvent(domain.AuthEventRefresh, domain.AuthSuccess, claims.ID, device))
+	return accessToken, newRefreshToken, exp, nil
+}
+
+func (a authService) recordRefreshFailure(userID int64, reason string, device domain.Device) {
+	event := domain.NewAuthEvent(domain.AuthEventRefresh, domain.AuthFailure, userID, device)
+	event.Detail = reason
+	a.eventService.Record(event)
+}
+
+func (a authService) Logout(sessionID string, userID int64, device domain.Device) error {
+	err := a.deleteSession(sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("auth service error logout: %w", err)
+	}
+	a.eventService.Record(domain.NewAuthEvent(domain.AuthEventLogout, domain.AuthSuccess, userID, device))
+	return nil
+}
+
+func (a authService) LogoutAll(userID int64, device domain.Device) error {
+	err := a.store.DeleteUserSessions(userID)
+	if err != nil {
+		return fmt.Errorf("auth service error logout all: %w", err)
+	}
+	event := domain.NewAuthEvent(domain.AuthEventLogout, domain.AuthSuccess, userID, device)
+	event.Detail = "all sessions"
+	a.eventService.Record(event)
+	return nil
+}
+
+func (a authService) GetSessions(userID int64) ([]domain.Session, error) {
+	sessions, err := a.store.UserSessions(userID)
+	if err != nil {
+		return nil, fmt.Errorf("auth service error get sessions: %w", err)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+func (a authService) RevokeSession(sessionID string, userID int64) error {
+	err := a.deleteSession(sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("auth service error revoke session: %w", err)
+	}
+	return nil
+}
+
+// TouchSession restarts the idle timeout of the session, up to its max age. A concurrent request touching
+// the same session wins without an error, as it extended the session just the same.
+func (a authService) TouchSession(sessionID string) error {
+	err := a.store.UpdateSession(sessionID, a.config.Session.IdleTimeout, func(session *domain.Session) error {
+		a.limitSession(session)
+		session.LastSeenAt = time.Now()
+		return nil
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("auth service error touch session: %w", ErrSessionNotFound)
+	} else if errors.Is(err, store.ErrConflict) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("auth service error touch session: %w", err)
+	}
+	return nil
+}
+
+// limitSession gives sessions saved before max ages were kept their max age from the login.
+func (a authService) limitSession(session *domain.Session) {
+	if session.ExpiresAt.IsZero() {
+		session.ExpiresAt = session.CreatedAt.Add(a.config.Session.MaxAge)
+	}
+}
+
+// Impersonate opens a session of the user for an admin and issues a short-lived access token with the admin
+// as its act claim, there is no refresh token. Admins can't be impersonated, as that would hand over their rights.
+func (a authService) Impersonate(actorID, userID int64, device domain.Device) (string, int64, error) {
+	if actorID == userID {
+		return "", 0, fmt.Errorf("auth service error impersonate: %w", ErrCantImpersonate)
+	}
+	actor, err := a.userService.FindByID(actorID)
+	if err != nil {
+		return "", 0, fmt.Errorf("auth service error impersonate: %w", err)
+	}
+	u, err := a.userService.FindByID(userID)
+	if err != nil {
+		return "", 0, fmt.Errorf("auth service error impersonate: %w", err)
+	}
+	if u.Role == domain.RoleAdmin {
+		return "", 0, fmt.Errorf("auth service error impersonate: %w", ErrCantImpersonate)
+	}
+
+	now := time.Now()
+	session := domain.Session{
+		ID:         uuid.New().String(),
+		UserID:     u.ID,
+		ActorID:    actor.ID,
+		UserAgent:  device.UserAgent,
+		IP:         device.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		// the session ends with its token
+		ExpiresAt: now.Add(time.Minute * impersonationTTL),
+	}
+	act := &ActorClaim{ID: actor.ID, Name: actor.Name}
+	accessToken, accessUID, exp, err := createToken(u, session.ID, time.Minute*impersonationTTL, act, a.keys.Sign)
+	if err != nil {
+		return "", 0, fmt.Errorf("auth service error impersonate: %w", err)
+	}
+	session.AccessID = accessUID
+	err = a.store.SaveSession(session, a.config.Session.IdleTimeout)
+	if err != nil {
+		return "", 0, fmt.Errorf("auth service error impersonate, couldn't save session: %w", err)
+	}
+
+	event := domain.NewAuthEvent(domain.AuthEventImpersonate, domain.AuthSuccess, u.ID, device)
+	event.ActorID = actor.ID
+	a.eventService.Record(event)
+	return accessToken, exp, nil
+}
+
+func (a authService) getSession(sessionID string) (domain.Session, error) {
+	session, err := a.store.GetSession(sessionID)
+	if errors.Is(err, store.ErrNotFound) {
+		return domain.Session{}, ErrSessionNotFound
+	}
+	return session, err
+}
+
+func (a authService) deleteSession(sessionID string, userID int64) error {
+	session, err := a.getSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	return a.store.DeleteSession(sessionID, userID)
+}
+
+// createTokenPair issues the tokens of the session and records their ids in it.
+// Neither token outlives the session.
+func (a authService) createTokenPair(u domain.User, session *domain.Session) (string, string, int64, error) {
+	accessTTL := untilExpiry(a.config.Session.AccessTTL, session.ExpiresAt)
+	accessToken, accessUID, exp, err := createToken(u, session.ID, accessTTL, nil, a.keys.Sign)
+	if err != nil {
+		return "", "", 0, err
+	}
+	// refresh tokens only come back to us, so they stay signed with the shared secret
+	refreshTTL := untilExpiry(a.config.Session.RefreshTTL, session.ExpiresAt)
+	refreshToken, refreshUID, _, err := createToken(u, session.ID, refreshTTL, nil, signHMAC(a.config.RefreshSecret))
+	if err != nil {
+		return "", "", 0, err
+	}
+	session.AccessID = accessUID
+	session.RefreshID = refreshUID
+	return accessToken, refreshToken, exp, nil
+}
+
+func untilExpiry(ttl time.Duration, expiresAt time.Time) time.Duration {
+	if left := time.Until(expiresAt); left < ttl {
+		return left
+	}
+	return ttl
+}
+
+func createToken(user domain.User, sessionID string, ttl time.Duration, act *ActorClaim, sign func(jwt.Claims) (string, error)) (string, string, int64, error) {
+	exp := time.Now().Add(ttl).Unix()
 	uid := uuid.New().String()
 	claimsAccess := JwtTokenClaim{
 		Name: user.Name,
 		ID:   user.ID,
 		UID:  uid,
+		SID:  sessionID,
+		Role: user.Role,
+		Act:  act,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: exp,
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claimsAccess)
-	t, err := token.SignedString([]byte(secret))
+	t, err := sign(claimsAccess)
 
 	return t, uid, exp, err
 }
 
-func (a authService) checkPasswordHash(password, hash string) bool {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+func signHMAC(secret string) func(jwt.Claims) (string, error) {
+	return func(claims jwt.Claims) (string, error) {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	}
+}
+
+func parseToken(tokenString, secret string) (*JwtTokenClaim, error) {
+	claims := &JwtTokenClaim{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
 }
 
 type JwtTokenClaim struct {
-	Name string `json:"name"`
-	ID   int64  `json:"id"`
-	UID  string `json:"uid"`
+	Name string      `json:"name"`
+	ID   int64       `json:"id"`
+	UID  string      `json:"uid"`
+	SID  string      `json:"sid"`
+	Role domain.Role `json:"role"`
+	// Act names the admin behind an impersonation token, it is nil in the user's own tokens
+	Act *ActorClaim `json:"act,omitempty"`
 	jwt.StandardClaims
 }
 
-type RedisToken struct {
-	AccessID  string `json:"access"`
-	RefreshID string `json:"refresh"`
+// ActorClaim is the act claim of RFC 8693, the party acting on behalf of the token's user.
+type ActorClaim struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func mfaPendingKey(token string) string {
+	return fmt.Sprintf("mfa-pending-%s", token)
 }
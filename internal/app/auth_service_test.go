@@ -0,0 +1,362 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+	"trainee/config"
+	smocks "trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/signing"
+	"trainee/internal/infra/store"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// eventLog keeps the recorded auth events in memory.
+type eventLog struct {
+	events []domain.AuthEvent
+}
+
+func (l *eventLog) Record(event domain.AuthEvent) {
+	l.events = append(l.events, event)
+}
+
+func (l *eventLog) Find(domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	return l.events, nil
+}
+
+// outcomes lists the recorded events as type, outcome and detail, and forgets them.
+func (l *eventLog) outcomes() []string {
+	var outcomes []string
+	for _, e := range l.events {
+		outcomes = append(outcomes, strings.TrimSuffix(fmt.Sprintf("%s %s %s", e.Type, e.Outcome, e.Detail), " "))
+	}
+	l.events = nil
+	return outcomes
+}
+
+var testLifetime = config.SessionLifetime{
+	AccessTTL:   2 * time.Hour,
+	RefreshTTL:  48 * time.Hour,
+	IdleTimeout: 10 * time.Minute,
+	MaxAge:      time.Hour,
+}
+
+// newTestAuthService runs the auth service on the in-memory store, the clock only drives the store's expiry.
+func newTestAuthService(t *testing.T, us UserService, ms MFAService) (AuthService, *signing.KeySet, *testClock, *eventLog) {
+	clock := &testClock{now: time.Now()}
+	st := store.NewMemorySessionStore(clock.Now)
+	keys := signing.NewHMACKeySet("access-secret")
+	conf := config.Configuration{RefreshSecret: "refresh-secret", Session: testLifetime}
+	events := &eventLog{}
+	return NewAuthService(us, smocks.NewVerificationService(t), ms, NewLockoutService(st), events, conf, keys, st), keys, clock, events
+}
+
+func accessClaims(t *testing.T, keys *signing.KeySet, token string) *JwtTokenClaim {
+	claims := &JwtTokenClaim{}
+	_, err := jwt.ParseWithClaims(token, claims, keys.Keyfunc)
+	require.NoError(t, err)
+	return claims
+}
+
+func Test_authService_SessionCycle(t *testing.T) {
+	user := domain.User{ID: 1, Email: "user@example.com", Role: domain.RoleUser}
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	us := smocks.NewUserService(t)
+	us.
+		On("FindByEmail", user.Email).Return(user, nil).
+		On("VerifyPassword", user, "password").Return(true).
+		On("FindByID", user.ID).Return(user, nil)
+	as, keys, clock, events := newTestAuthService(t, us, smocks.NewMFAService(t))
+
+	accessToken, refreshToken, _, err := as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	require.NoError(t, err)
+	claims := accessClaims(t, keys, accessToken)
+	_, err = as.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
+	assert.NoError(t, err)
+
+	// refreshing rotates both tokens, the old access token stops working
+	newAccess, newRefresh, _, err := as.Refresh(refreshToken, device)
+	require.NoError(t, err)
+	_, err = as.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	claims = accessClaims(t, keys, newAccess)
+	_, err = as.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
+	assert.NoError(t, err)
+
+	// replaying the rotated refresh token ends the session
+	_, _, _, err = as.Refresh(refreshToken, device)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, _, err = as.Refresh(newRefresh, device)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = as.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	assert.Equal(t, []string{
+		"login success",
+		"refresh success",
+		"refresh failure refresh token replayed",
+		"refresh failure session expired or revoked",
+	}, events.outcomes())
+
+	// idle sessions expire, touched ones stay
+	accessToken, _, _, err = as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	require.NoError(t, err)
+	claims = accessClaims(t, keys, accessToken)
+	clock.now = clock.now.Add(testLifetime.IdleTimeout - time.Minute)
+	assert.NoError(t, as.TouchSession(claims.SID))
+	clock.now = clock.now.Add(testLifetime.IdleTimeout - time.Minute)
+	_, err = as.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
+	assert.NoError(t, err)
+	clock.now = clock.now.Add(testLifetime.IdleTimeout)
+	_, err = as.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+
+	// logout ends one session, logout all ends the rest
+	first, _, _, err := as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	require.NoError(t, err)
+	second, _, _, err := as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	require.NoError(t, err)
+	sessions, err := as.GetSessions(user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	firstClaims := accessClaims(t, keys, first)
+	assert.NoError(t, as.Logout(firstClaims.SID, user.ID, device))
+	_, err = as.ValidateJWT(firstClaims.UID, firstClaims.SID, firstClaims.ID, firstClaims.Role, false)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	assert.ErrorIs(t, as.Logout(firstClaims.SID, user.ID, device), ErrSessionNotFound)
+
+	secondClaims := accessClaims(t, keys, second)
+	assert.ErrorIs(t, as.RevokeSession(secondClaims.SID, 2), ErrSessionNotFound)
+	assert.NoError(t, as.LogoutAll(user.ID, device))
+	_, err = as.ValidateJWT(secondClaims.UID, secondClaims.SID, secondClaims.ID, secondClaims.Role, false)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	sessions, err = as.GetSessions(user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+	assert.Equal(t, []string{"login success", "login success", "login success", "logout success", "logout success all sessions"}, events.outcomes())
+}
+
+func Test_authService_LoginMFA(t *testing.T) {
+	enabled := time.Now()
+	user := domain.User{ID: 1, Email: "user@example.com", Role: domain.RoleUser, MFAEnabledAt: &enabled}
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	us := smocks.NewUserService(t)
+	us.
+		On("FindByEmail", user.Email).Return(user, nil).
+		On("VerifyPassword", user, "password").Return(true).
+		On("FindByID", user.ID).Return(user, nil)
+	ms := smocks.NewMFAService(t)
+	ms.
+		On("Validate", user, "000000").Return(ErrInvalidMFACode).
+		On("Validate", user, "123456").Return(nil)
+	as, keys, _, events := newTestAuthService(t, us, ms)
+
+	_, _, _, err := as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	var mfaErr *MFARequiredError
+	require.True(t, errors.As(err, &mfaErr))
+
+	_, _, _, err = as.LoginMFA(mfaErr.Token, "000000")
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+	accessToken, _, _, err := as.LoginMFA(mfaErr.Token, "123456")
+	require.NoError(t, err)
+	claims := accessClaims(t, keys, accessToken)
+	_, err = as.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
+	assert.NoError(t, err)
+
+	// the pending login is used up
+	_, _, _, err = as.LoginMFA(mfaErr.Token, "123456")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Equal(t, []string{"login failure invalid mfa code", "login success"}, events.outcomes())
+
+	// too many wrong codes drop the pending login
+	_, _, _, err = as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	require.True(t, errors.As(err, &mfaErr))
+	for i := 1; i < mfaAttempts; i++ {
+		_, _, _, err = as.LoginMFA(mfaErr.Token, "000000")
+		assert.ErrorIs(t, err, ErrInvalidMFACode)
+	}
+	var locked *LockedError
+	_, _, _, err = as.LoginMFA(mfaErr.Token, "000000")
+	assert.True(t, errors.As(err, &locked))
+	_, _, _, err = as.LoginMFA(mfaErr.Token, "123456")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func Test_authService_LoginMFALockout(t *testing.T) {
+	enabled := time.Now()
+	user := domain.User{ID: 1, Email: "user@example.com", Role: domain.RoleUser, MFAEnabledAt: &enabled}
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	us := smocks.NewUserService(t)
+	us.
+		On("FindByEmail", user.Email).Return(user, nil).
+		On("VerifyPassword", user, "password").Return(true).
+		On("FindByID", user.ID).Return(user, nil)
+	ms := smocks.NewMFAService(t)
+	ms.
+		On("Validate", user, "000000").Return(ErrInvalidMFACode).
+		On("Validate", user, "123456").Return(nil)
+	as, _, clock, events := newTestAuthService(t, us, ms)
+
+	// the right password doesn't forget the wrong codes, a new pending login per guess still locks the account
+	var mfaErr *MFARequiredError
+	var locked *LockedError
+	for i := 1; i <= accountThreshold; i++ {
+		_, _, _, err := as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+		require.True(t, errors.As(err, &mfaErr))
+		_, _, _, err = as.LoginMFA(mfaErr.Token, "000000")
+		if i < accountThreshold {
+			assert.ErrorIs(t, err, ErrInvalidMFACode)
+		} else {
+			require.True(t, errors.As(err, &locked))
+			assert.Equal(t, time.Minute, locked.RetryAfter)
+		}
+	}
+	_, _, _, err := as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	assert.True(t, errors.As(err, &locked))
+
+	clock.now = clock.now.Add(time.Minute)
+	_, _, _, err = as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	require.True(t, errors.As(err, &mfaErr))
+	_, _, _, err = as.LoginMFA(mfaErr.Token, "123456")
+	assert.NoError(t, err)
+
+	// the session reset the failures, a single wrong code doesn't lock again
+	_, _, _, err = as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	require.True(t, errors.As(err, &mfaErr))
+	_, _, _, err = as.LoginMFA(mfaErr.Token, "000000")
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+
+	outcomes := events.outcomes()
+	assert.Equal(t, "login failure invalid mfa code", outcomes[0])
+	assert.Equal(t, "login failure locked", outcomes[accountThreshold])
+	assert.Equal(t, "login success", outcomes[accountThreshold+1])
+}
+
+func Test_authService_LoginLockout(t *testing.T) {
+	user := domain.User{ID: 1, Email: "user@example.com", Role: domain.RoleUser}
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	us := smocks.NewUserService(t)
+	us.
+		On("FindByEmail", user.Email).Return(user, nil).
+		On("VerifyPassword", user, "wrong").Return(false)
+	as, _, clock, events := newTestAuthService(t, us, smocks.NewMFAService(t))
+
+	var locked *LockedError
+	for i := 1; i < accountThreshold; i++ {
+		_, _, _, err := as.Login(requests.LoginAuth{Email: user.Email, Password: "wrong"}, device)
+		assert.False(t, errors.As(err, &locked))
+	}
+	_, _, _, err := as.Login(requests.LoginAuth{Email: user.Email, Password: "wrong"}, device)
+	require.True(t, errors.As(err, &locked))
+	assert.Equal(t, time.Minute, locked.RetryAfter)
+
+	// the lock is checked before the password
+	_, _, _, err = as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	assert.True(t, errors.As(err, &locked))
+
+	clock.now = clock.now.Add(time.Minute)
+	us.On("VerifyPassword", user, "password").Return(true)
+	_, _, _, err = as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	assert.NoError(t, err)
+
+	outcomes := events.outcomes()
+	assert.Len(t, outcomes, accountThreshold+2)
+	assert.Equal(t, "login failure wrong password", outcomes[0])
+	assert.Equal(t, "login failure locked", outcomes[accountThreshold])
+	assert.Equal(t, "login success", outcomes[accountThreshold+1])
+}
+
+func Test_authService_Impersonate(t *testing.T) {
+	admin := domain.User{ID: 1, Name: "Admin", Email: "admin@example.com", Role: domain.RoleAdmin}
+	user := domain.User{ID: 2, Name: "User", Email: "user@example.com", Role: domain.RoleUser}
+	other := domain.User{ID: 3, Name: "Other", Email: "other@example.com", Role: domain.RoleAdmin}
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	us := smocks.NewUserService(t)
+	us.
+		On("FindByID", admin.ID).Return(admin, nil).
+		On("FindByID", user.ID).Return(user, nil).
+		On("FindByID", other.ID).Return(other, nil)
+	as, keys, _, events := newTestAuthService(t, us, smocks.NewMFAService(t))
+
+	accessToken, exp, err := as.Impersonate(admin.ID, user.ID, device)
+	require.NoError(t, err)
+	claims := accessClaims(t, keys, accessToken)
+	assert.Equal(t, user.ID, claims.ID)
+	assert.Equal(t, user.Role, claims.Role)
+	assert.Equal(t, &ActorClaim{ID: admin.ID, Name: admin.Name}, claims.Act)
+	assert.Equal(t, claims.ExpiresAt, exp)
+	assert.LessOrEqual(t, exp, time.Now().Add(time.Minute*impersonationTTL).Unix())
+	_, err = as.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
+	assert.NoError(t, err)
+
+	// the user sees who is acting in their sessions
+	sessions, err := as.GetSessions(user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, admin.ID, sessions[0].ActorID)
+	assert.Empty(t, sessions[0].RefreshID)
+	require.Len(t, events.events, 1)
+	assert.Equal(t, admin.ID, events.events[0].ActorID)
+	assert.Equal(t, user.ID, events.events[0].UserID)
+	assert.Equal(t, []string{"impersonate success"}, events.outcomes())
+
+	_, _, err = as.Impersonate(admin.ID, admin.ID, device)
+	assert.ErrorIs(t, err, ErrCantImpersonate)
+	_, _, err = as.Impersonate(admin.ID, other.ID, device)
+	assert.ErrorIs(t, err, ErrCantImpersonate)
+	assert.Empty(t, events.outcomes())
+}
+
+func Test_authService_SessionMaxAge(t *testing.T) {
+	user := domain.User{ID: 1, Email: "user@example.com", Role: domain.RoleUser}
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	us := smocks.NewUserService(t)
+	us.
+		On("FindByEmail", user.Email).Return(user, nil).
+		On("VerifyPassword", user, "password").Return(true).
+		On("FindByID", user.ID).Return(user, nil)
+	as, keys, clock, _ := newTestAuthService(t, us, smocks.NewMFAService(t))
+
+	accessToken, refreshToken, exp, err := as.Login(requests.LoginAuth{Email: user.Email, Password: "password"}, device)
+	require.NoError(t, err)
+	claims := accessClaims(t, keys, accessToken)
+	// the tokens don't outlive the session either
+	assert.LessOrEqual(t, exp, time.Now().Add(testLifetime.MaxAge).Unix())
+	sessions, err := as.GetSessions(user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.WithinDuration(t, sessions[0].CreatedAt.Add(testLifetime.MaxAge), sessions[0].ExpiresAt, 0)
+
+	// staying active keeps the session up to its max age, refreshing doesn't push it back
+	for elapsed := time.Duration(0); elapsed+testLifetime.IdleTimeout < testLifetime.MaxAge; elapsed += testLifetime.IdleTimeout / 2 {
+		clock.now = clock.now.Add(testLifetime.IdleTimeout / 2)
+		require.NoError(t, as.TouchSession(claims.SID))
+	}
+	accessToken, refreshToken, _, err = as.Refresh(refreshToken, device)
+	require.NoError(t, err)
+	claims = accessClaims(t, keys, accessToken)
+
+	// a touch just before the max age extends the session only up to it
+	clock.now = clock.now.Add(testLifetime.IdleTimeout - time.Minute)
+	assert.NoError(t, as.TouchSession(claims.SID))
+	clock.now = clock.now.Add(2 * time.Minute)
+	assert.ErrorIs(t, as.TouchSession(claims.SID), ErrSessionNotFound)
+	_, err = as.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+	_, _, _, err = as.Refresh(refreshToken, device)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
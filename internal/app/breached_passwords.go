@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// breachedRanges answers the k-anonymity range query of Have I Been Pwned: for the first 5 hex characters of a
+// SHA-1 hash it gives the remaining 35 of every breached hash with that prefix, one SUFFIX:COUNT per line.
+// Only the prefix leaves the caller, so a source can as well be the online range API.
+type breachedRanges interface {
+	Range(prefix string) (io.ReadCloser, error)
+}
+
+// breachedRangeDir keeps a range per file, named by the prefix like PREFIX.txt, as the Have I Been Pwned
+// downloader writes them. A missing file is an empty range.
+type breachedRangeDir struct {
+	dir string
+}
+
+func newBreachedRangeDir(dir string) (breachedRangeDir, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return breachedRangeDir{}, err
+	}
+	if !info.IsDir() {
+		return breachedRangeDir{}, &fs.PathError{Op: "open", Path: dir, Err: errors.New("not a directory")}
+	}
+	return breachedRangeDir{dir: dir}, nil
+}
+
+func (d breachedRangeDir) Range(prefix string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(d.dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	return f, err
+}
+
+// breachedPasswords looks passwords up by the prefix of their SHA-1 hash and matches the suffix in its range.
+type breachedPasswords struct {
+	ranges breachedRanges
+}
+
+func (b breachedPasswords) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	r, err := b.ranges.Range(prefix)
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineSuffix := strings.SplitN(scanner.Text(), ":", 2)[0]
+		if strings.EqualFold(strings.TrimSpace(lineSuffix), suffix) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
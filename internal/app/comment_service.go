@@ -11,29 +11,32 @@ import (
 //go:generate mockery --dir . --name CommentService --output ./mocks
 type CommentService interface {
 	SaveComment(commentRequest requests.CommentRequest, postID int64, token *jwt.Token) (domain.Comment, error)
-	GetComment(id int64) (domain.Comment, error)
-	UpdateComment(commentRequest requests.CommentRequest, id int64) (domain.Comment, error)
-	DeleteComment(id int64) error
+	GetComment(id int64, token *jwt.Token) (domain.Comment, error)
+	UpdateComment(commentRequest requests.CommentRequest, id int64, token *jwt.Token) (domain.Comment, error)
+	DeleteComment(id int64, token *jwt.Token) error
 	GetCommentsByPostID(postID int64, offset int) ([]domain.Comment, error)
+	GetCommentsByUser(userID int64) ([]domain.Comment, error)
 }
 
 type commentService struct {
-	repo database.CommentRepo
-	us   UserService
-	ps   PostService
+	repo   database.CommentRepo
+	us     UserService
+	ps     PostService
+	policy Policy
 }
 
-func NewCommentService(repo database.CommentRepo, us UserService, ps PostService) CommentService {
+func NewCommentService(repo database.CommentRepo, us UserService, ps PostService, p Policy) CommentService {
 	return commentService{
-		repo: repo,
-		us:   us,
-		ps:   ps,
+		repo:   repo,
+		us:     us,
+		ps:     ps,
+		policy: p,
 	}
 }
 
 func (s commentService) SaveComment(commentRequest requests.CommentRequest, postID int64, token *jwt.Token) (domain.Comment, error) {
 	claims := token.Claims.(*JwtTokenClaim)
-	_, err := s.ps.GetPost(postID)
+	_, err := s.ps.GetPost(postID, token)
 	if err != nil {
 		return domain.Comment{}, fmt.Errorf("service error save comment: %w", err)
 	}
@@ -43,6 +46,7 @@ func (s commentService) SaveComment(commentRequest requests.CommentRequest, post
 	}
 	domainComment := domain.Comment{
 		PostID: postID,
+		UserID: user.ID,
 		Name:   user.Name,
 		Email:  user.Email,
 		Body:   commentRequest.Body,
@@ -54,16 +58,20 @@ func (s commentService) SaveComment(commentRequest requests.CommentRequest, post
 	return comment, nil
 }
 
-func (s commentService) GetComment(id int64) (domain.Comment, error) {
-	comment, err := s.repo.GetComment(id)
+func (s commentService) GetComment(id int64, token *jwt.Token) (domain.Comment, error) {
+	comment, err := s.visibleComment(id, token)
 	if err != nil {
 		return domain.Comment{}, fmt.Errorf("service error get comment: %w", err)
 	}
 	return comment, nil
 }
 
-func (s commentService) UpdateComment(commentRequest requests.CommentRequest, id int64) (domain.Comment, error) {
-	comment, err := s.repo.GetComment(id)
+func (s commentService) UpdateComment(commentRequest requests.CommentRequest, id int64, token *jwt.Token) (domain.Comment, error) {
+	comment, err := s.visibleComment(id, token)
+	if err != nil {
+		return domain.Comment{}, fmt.Errorf("service error update comment: %w", err)
+	}
+	err = s.policy.CanUpdateComment(token, comment)
 	if err != nil {
 		return domain.Comment{}, fmt.Errorf("service error update comment: %w", err)
 	}
@@ -75,8 +83,12 @@ func (s commentService) UpdateComment(commentRequest requests.CommentRequest, id
 	return comment, nil
 }
 
-func (s commentService) DeleteComment(id int64) error {
-	_, err := s.repo.GetComment(id)
+func (s commentService) DeleteComment(id int64, token *jwt.Token) error {
+	comment, err := s.visibleComment(id, token)
+	if err != nil {
+		return fmt.Errorf("service error delete comment: %w", err)
+	}
+	err = s.policy.CanDeleteComment(token, comment)
 	if err != nil {
 		return fmt.Errorf("service error delete comment: %w", err)
 	}
@@ -94,3 +106,24 @@ func (s commentService) GetCommentsByPostID(postID int64, offset int) ([]domain.
 	}
 	return comments, nil
 }
+
+func (s commentService) GetCommentsByUser(userID int64) ([]domain.Comment, error) {
+	comments, err := s.repo.GetCommentsByUser(userID)
+	if err != nil {
+		return []domain.Comment{}, fmt.Errorf("service error get comments by user id: %w", err)
+	}
+	return comments, nil
+}
+
+// visibleComment finds a comment on a post the token holder can see, comments on the posts they can't are not found.
+func (s commentService) visibleComment(id int64, token *jwt.Token) (domain.Comment, error) {
+	comment, err := s.repo.GetComment(id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	_, err = s.ps.GetPost(comment.PostID, token)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return comment, nil
+}
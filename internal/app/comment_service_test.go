@@ -3,6 +3,7 @@ package app
 import (
 	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/upper/db/v4"
 	"testing"
 	smocks "trainee/internal/app/mocks"
@@ -17,8 +18,9 @@ func Test_commentService_GetComment(t *testing.T) {
 		name    string
 		id      int64
 		repo    func(id int64) database.CommentRepo
+		ps      func() PostService
 		want    domain.Comment
-		wantErr bool
+		wantErr error
 	}{
 		{
 			"success get comment",
@@ -27,11 +29,18 @@ func Test_commentService_GetComment(t *testing.T) {
 				mock := rmocks.NewCommentRepo(t)
 				mock.
 					On("GetComment", id).
-					Return(domain.Comment{ID: 2}, nil)
+					Return(domain.Comment{ID: 2, PostID: 3}, nil)
 				return mock
 			},
-			domain.Comment{ID: 2},
-			false,
+			func() PostService {
+				ps := smocks.NewPostService(t)
+				ps.
+					On("GetPost", int64(3), mock.AnythingOfType("*jwt.Token")).
+					Return(domain.Post{ID: 3}, nil).Times(1)
+				return ps
+			},
+			domain.Comment{ID: 2, PostID: 3},
+			nil,
 		},
 		{
 			"error get comment",
@@ -43,27 +52,61 @@ func Test_commentService_GetComment(t *testing.T) {
 					Return(domain.Comment{}, db.ErrNoMoreRows)
 				return mock
 			},
+			func() PostService {
+				return smocks.NewPostService(t)
+			},
 			domain.Comment{},
-			true,
+			db.ErrNoMoreRows,
+		},
+		{
+			"comment on a post the user can't see",
+			2,
+			func(id int64) database.CommentRepo {
+				mock := rmocks.NewCommentRepo(t)
+				mock.
+					On("GetComment", id).
+					Return(domain.Comment{ID: 2, PostID: 3}, nil)
+				return mock
+			},
+			func() PostService {
+				ps := smocks.NewPostService(t)
+				ps.
+					On("GetPost", int64(3), mock.AnythingOfType("*jwt.Token")).
+					Return(domain.Post{}, db.ErrNoMoreRows).Times(1)
+				return ps
+			},
+			domain.Comment{},
+			db.ErrNoMoreRows,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := commentService{
-				repo: tt.repo(tt.id),
-			}
-			comment, err := NewCommentService(s.repo, s.us, s.ps).GetComment(tt.id)
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.Equal(t, comment, tt.want)
+			comment, err := NewCommentService(tt.repo(tt.id), nil, tt.ps(), NewPolicy()).GetComment(tt.id, token())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, comment, tt.want)
 			}
+			assert.Equal(t, tt.want, comment)
 		})
 	}
 }
 
+func Test_commentService_HiddenPost(t *testing.T) {
+	// the comment is the user's own, but its post went back to draft
+	comment := domain.Comment{ID: 2, PostID: 3, UserID: 1}
+	repo := rmocks.NewCommentRepo(t)
+	repo.On("GetComment", int64(2)).Return(comment, nil).Times(2)
+	ps := smocks.NewPostService(t)
+	ps.On("GetPost", int64(3), mock.AnythingOfType("*jwt.Token")).Return(domain.Post{}, db.ErrNoMoreRows).Times(2)
+	s := NewCommentService(repo, nil, ps, NewPolicy())
+
+	_, err := s.UpdateComment(requests.CommentRequest{Body: "body"}, 2, token())
+	assert.ErrorIs(t, err, db.ErrNoMoreRows)
+	err = s.DeleteComment(2, token())
+	assert.ErrorIs(t, err, db.ErrNoMoreRows)
+}
+
 func Test_commentService_SaveComment(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -82,11 +125,11 @@ func Test_commentService_SaveComment(t *testing.T) {
 			2,
 			token(),
 			func(postID int64) PostService {
-				mock := smocks.NewPostService(t)
-				mock.
-					On("GetPost", postID).
+				ps := smocks.NewPostService(t)
+				ps.
+					On("GetPost", postID, mock.AnythingOfType("*jwt.Token")).
 					Return(domain.Post{}, nil).Times(1)
-				return mock
+				return ps
 			},
 			func(id int64) UserService {
 				mock := smocks.NewUserService(t)
@@ -103,6 +146,7 @@ func Test_commentService_SaveComment(t *testing.T) {
 				mock := rmocks.NewCommentRepo(t)
 				domainComment := domain.Comment{
 					PostID: 2,
+					UserID: 1,
 					Name:   "Name",
 					Email:  "comment@mail.com",
 					Body:   "body",
@@ -114,6 +158,7 @@ func Test_commentService_SaveComment(t *testing.T) {
 			},
 			domain.Comment{
 				PostID: 2,
+				UserID: 1,
 				Name:   "Name",
 				Email:  "comment@mail.com",
 				Body:   "body",
@@ -126,11 +171,11 @@ func Test_commentService_SaveComment(t *testing.T) {
 			2,
 			token(),
 			func(postID int64) PostService {
-				mock := smocks.NewPostService(t)
-				mock.
-					On("GetPost", postID).
+				ps := smocks.NewPostService(t)
+				ps.
+					On("GetPost", postID, mock.AnythingOfType("*jwt.Token")).
 					Return(domain.Post{}, db.ErrNoMoreRows).Times(1)
-				return mock
+				return ps
 			},
 			func(id int64) UserService {
 				mock := smocks.NewUserService(t)
@@ -149,11 +194,11 @@ func Test_commentService_SaveComment(t *testing.T) {
 			2,
 			token(),
 			func(postID int64) PostService {
-				mock := smocks.NewPostService(t)
-				mock.
-					On("GetPost", postID).
+				ps := smocks.NewPostService(t)
+				ps.
+					On("GetPost", postID, mock.AnythingOfType("*jwt.Token")).
 					Return(domain.Post{}, nil).Times(1)
-				return mock
+				return ps
 			},
 			func(id int64) UserService {
 				mock := smocks.NewUserService(t)
@@ -175,11 +220,11 @@ func Test_commentService_SaveComment(t *testing.T) {
 			2,
 			token(),
 			func(postID int64) PostService {
-				mock := smocks.NewPostService(t)
-				mock.
-					On("GetPost", postID).
+				ps := smocks.NewPostService(t)
+				ps.
+					On("GetPost", postID, mock.AnythingOfType("*jwt.Token")).
 					Return(domain.Post{}, nil).Times(1)
-				return mock
+				return ps
 			},
 			func(id int64) UserService {
 				mock := smocks.NewUserService(t)
@@ -196,6 +241,7 @@ func Test_commentService_SaveComment(t *testing.T) {
 				mock := rmocks.NewCommentRepo(t)
 				domainComment := domain.Comment{
 					PostID: 2,
+					UserID: 1,
 					Name:   "Name",
 					Email:  "comment@mail.com",
 					Body:   "body",
@@ -216,7 +262,7 @@ func Test_commentService_SaveComment(t *testing.T) {
 				us:   tt.us(tt.token.Claims.(*JwtTokenClaim).ID),
 				ps:   tt.ps(tt.postID),
 			}
-			comment, err := NewCommentService(s.repo, s.us, s.ps).SaveComment(tt.commentRequest, tt.postID, tt.token)
+			comment, err := NewCommentService(s.repo, s.us, s.ps, NewPolicy()).SaveComment(tt.commentRequest, tt.postID, tt.token)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Equal(t, comment, tt.want)
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContext is how many unchanged lines surround the changes of a hunk.
+	diffContext = 3
+	// diffMaxEdits bounds the search for the shortest edit, texts further apart than that are diffed as all of
+	// one removed and all of the other added. The search keeps about diffMaxEdits² positions, 8 MB at most.
+	diffMaxEdits = 1000
+)
+
+// diffLine is a line of a diff, op is ' ' for a kept line, '-' for a removed and '+' for an added one.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff compares two texts line by line in the unified format, fromName and toName label them in the
+// header. Texts that are the same give an empty diff.
+func unifiedDiff(fromName, toName, from, to string) string {
+	lines := diffLines(strings.Split(from, "\n"), strings.Split(to, "\n"))
+	var changes []int
+	for i, l := range lines {
+		if l.op != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for first := 0; first < len(changes); {
+		// changes closer than twice the context share a hunk
+		last := first
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*diffContext {
+			last++
+		}
+		start, end := changes[first]-diffContext, changes[last]+diffContext+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		writeHunk(&b, lines, start, end)
+		first = last + 1
+	}
+	return b.String()
+}
+
+func writeHunk(b *strings.Builder, lines []diffLine, start, end int) {
+	fromLine, toLine := 1, 1
+	for _, l := range lines[:start] {
+		if l.op != '+' {
+			fromLine++
+		}
+		if l.op != '-' {
+			toLine++
+		}
+	}
+	fromCount, toCount := 0, 0
+	for _, l := range lines[start:end] {
+		if l.op != '+' {
+			fromCount++
+		}
+		if l.op != '-' {
+			toCount++
+		}
+	}
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount))
+	for _, l := range lines[start:end] {
+		b.WriteByte(l.op)
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+}
+
+// hunkRange writes the lines of a hunk the way diff does: the count is left out when it is one, and an empty range
+// starts at the line before it.
+func hunkRange(line, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// diffLines finds the shortest edit from a to b with the Myers algorithm, up to diffMaxEdits.
+func diffLines(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	total := n + m
+	// v[offset+k] is the furthest x reached on diagonal k, with a spare diagonal on both ends
+	offset := total + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] keeps the diagonals -d-1..d+1 of v as it was before step d, the only ones the walk back reads
+	var trace [][]int
+search:
+	for d := 0; ; d++ {
+		if d > total || d > diffMaxEdits {
+			return replaceLines(a, b)
+		}
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// walk the furthest reaching paths back from the end, the lines come out last first
+	var lines []diffLine
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && v[d+k] < v[d+k+2] {
+			prevK = k + 1
+		}
+		prevX := v[d+1+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			lines = append(lines, diffLine{op: ' ', text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				lines = append(lines, diffLine{op: '+', text: b[y-1]})
+			} else {
+				lines = append(lines, diffLine{op: '-', text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// replaceLines is the edit that removes every line of a and adds every line of b.
+func replaceLines(a, b []string) []diffLine {
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, l := range a {
+		lines = append(lines, diffLine{op: '-', text: l})
+	}
+	for _, l := range b {
+		lines = append(lines, diffLine{op: '+', text: l})
+	}
+	return lines
+}
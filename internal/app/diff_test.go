@@ -0,0 +1,78 @@
+package app
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_unifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			"same text",
+			"Title\n\nBody",
+			"Title\n\nBody",
+			"",
+		},
+		{
+			"changed line",
+			"Title\n\none\ntwo\nthree",
+			"Title 2\n\none\ntwo\nthree",
+			"--- revision 1\n+++ revision 2\n@@ -1,4 +1,4 @@\n-Title\n+Title 2\n \n one\n two\n",
+		},
+		{
+			"distant changes in separate hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14",
+			"1\n2\nX\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15",
+			"--- revision 1\n+++ revision 2\n@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+X\n 4\n 5\n 6\n@@ -12,3 +12,4 @@\n 12\n 13\n 14\n+15\n",
+		},
+		{
+			"close changes in one hunk",
+			"a\nb\nc\nd\ne",
+			"a\nB\nc\nd\nE",
+			"--- revision 1\n+++ revision 2\n@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n-e\n+E\n",
+		},
+		{
+			"lines removed",
+			"a\nb\nc",
+			"a",
+			"--- revision 1\n+++ revision 2\n@@ -1,3 +1 @@\n a\n-b\n-c\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, unifiedDiff("revision 1", "revision 2", tt.from, tt.to))
+		})
+	}
+}
+
+func Test_diffLines_maxEdits(t *testing.T) {
+	lines := func(prefix string, n int) []string {
+		l := make([]string, n)
+		for i := range l {
+			l[i] = fmt.Sprintf("%s%d", prefix, i)
+		}
+		return l
+	}
+
+	// texts too far apart are replaced as a whole instead of searched for the shortest edit
+	a, b := lines("a", 5000), lines("b", 5000)
+	diff := diffLines(a, b)
+	assert.Len(t, diff, 10000)
+	assert.Equal(t, diffLine{op: '-', text: "a0"}, diff[0])
+	assert.Equal(t, diffLine{op: '+', text: "b0"}, diff[5000])
+
+	// long texts with few changes still get the shortest edit
+	b = append([]string(nil), a...)
+	b[2500] = "changed"
+	diff = diffLines(a, b)
+	assert.Len(t, diff, 5001)
+	assert.Equal(t, diffLine{op: '-', text: "a2500"}, diff[2500])
+	assert.Equal(t, diffLine{op: '+', text: "changed"}, diff[2501])
+
+}
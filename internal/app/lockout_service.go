@@ -0,0 +1,156 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"trainee/internal/infra/store"
+)
+
+const (
+	// failures are forgotten after lockoutWindow hours without a new one
+	lockoutWindow = 24
+	// an account is locked from its accountThreshold failed login in a row, an ip from its ipThreshold,
+	// first for lockoutBase and twice as long on every further failure, up to lockoutMax
+	accountThreshold = 5
+	ipThreshold      = 20
+	lockoutBase      = time.Minute
+	lockoutMax       = time.Hour
+)
+
+// LockedError is returned while logins for an account or from an ip are locked.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("too many failed logins, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+//go:generate mockery --dir . --name LockoutService --output ./mocks
+type LockoutService interface {
+	Check(email, ip string) error
+	Fail(email, ip string) error
+	Reset(email string) error
+	Unlock(email string) error
+}
+
+type lockoutService struct {
+	store store.SessionStore
+}
+
+func NewLockoutService(st store.SessionStore) LockoutService {
+	return lockoutService{
+		store: st,
+	}
+}
+
+// Check returns LockedError when the account or the ip may not try to log in yet.
+func (l lockoutService) Check(email, ip string) error {
+	var retryAfter time.Duration
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		ttl, err := l.store.TTL(lockKey(key))
+		if err != nil {
+			return fmt.Errorf("lockout service error check: %w", err)
+		}
+		if ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Fail counts a failed login, the failure that reaches a threshold returns LockedError.
+func (l lockoutService) Fail(email, ip string) error {
+	var retryAfter time.Duration
+	for _, c := range []struct {
+		key       string
+		threshold int64
+	}{
+		{accountKey(email), accountThreshold},
+		{ipKey(ip), ipThreshold},
+	} {
+		failures, err := l.store.Incr(c.key, time.Hour*lockoutWindow)
+		if err != nil {
+			return fmt.Errorf("lockout service error fail: %w", err)
+		}
+		d := lockDuration(failures, c.threshold)
+		if d == 0 {
+			continue
+		}
+		err = l.store.Set(lockKey(c.key), "1", d)
+		if err != nil {
+			return fmt.Errorf("lockout service error fail: %w", err)
+		}
+		if d > retryAfter {
+			retryAfter = d
+		}
+	}
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Reset forgets the failures of an account after a successful login, the ip counter keeps running.
+func (l lockoutService) Reset(email string) error {
+	err := l.store.Delete(accountKey(email))
+	if err != nil {
+		return fmt.Errorf("lockout service error reset: %w", err)
+	}
+	return nil
+}
+
+// Unlock lifts the lock of an account and forgets its failures.
+func (l lockoutService) Unlock(email string) error {
+	key := accountKey(email)
+	err := l.store.Delete(key, lockKey(key))
+	if err != nil {
+		return fmt.Errorf("lockout service error unlock: %w", err)
+	}
+	return nil
+}
+
+// countFailure counts a wrong password or code of the user, and returns the LockedError when that failure locked them.
+// Other lockout errors are only logged, they must not hide why the attempt failed.
+func countFailure(l LockoutService, email, ip string) error {
+	err := l.Fail(email, ip)
+	var locked *LockedError
+	if errors.As(err, &locked) {
+		return err
+	} else if err != nil {
+		log.Print(err)
+	}
+	return nil
+}
+
+func lockDuration(failures, threshold int64) time.Duration {
+	if failures < threshold {
+		return 0
+	}
+	d := lockoutBase
+	for i := threshold; i < failures && d < lockoutMax; i++ {
+		d *= 2
+	}
+	if d > lockoutMax {
+		d = lockoutMax
+	}
+	return d
+}
+
+func accountKey(email string) string {
+	return fmt.Sprintf("login-fail-account-%s", strings.ToLower(email))
+}
+
+func ipKey(ip string) string {
+	return fmt.Sprintf("login-fail-ip-%s", ip)
+}
+
+func lockKey(key string) string {
+	return key + "-lock"
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_lockDuration(t *testing.T) {
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{1, 0},
+		{4, 0},
+		{5, time.Minute},
+		{6, 2 * time.Minute},
+		{8, 8 * time.Minute},
+		{11, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, lockDuration(tt.failures, accountThreshold), "failures %d", tt.failures)
+	}
+}
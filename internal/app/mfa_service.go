@@ -0,0 +1,204 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"trainee/config"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+)
+
+const recoveryCodes = 10
+
+var (
+	ErrMFANotConfigured  = errors.New("mfa is not configured")
+	ErrMFAAlreadyEnabled = errors.New("mfa is already enabled")
+	ErrMFANotEnrolled    = errors.New("mfa is not enrolled")
+	ErrInvalidMFACode    = errors.New("invalid mfa code")
+)
+
+//go:generate mockery --dir . --name MFAService --output ./mocks
+type MFAService interface {
+	Enroll(userID int64) (string, string, error)
+	Confirm(userID int64, code string) ([]string, error)
+	Disable(userID int64, code string, device domain.Device) error
+	Validate(user domain.User, code string) error
+}
+
+type mfaService struct {
+	userService    UserService
+	recoveryRepo   database.RecoveryCodeRepo
+	lockoutService LockoutService
+	box            secretBox
+	config         config.Configuration
+}
+
+func NewMFAService(us UserService, rr database.RecoveryCodeRepo, ls LockoutService, conf config.Configuration) MFAService {
+	return mfaService{
+		userService:    us,
+		recoveryRepo:   rr,
+		lockoutService: ls,
+		box:            newSecretBox(conf.MFAKey),
+		config:         conf,
+	}
+}
+
+// Enroll stores a new secret and returns it with its provisioning uri. Mfa is only enabled once Confirm gets a code.
+func (m mfaService) Enroll(userID int64) (string, string, error) {
+	if m.config.MFAKey == "" {
+		return "", "", fmt.Errorf("mfa service error enroll: %w", ErrMFANotConfigured)
+	}
+	user, err := m.userService.FindByID(userID)
+	if err != nil {
+		return "", "", fmt.Errorf("mfa service error enroll: %w", err)
+	}
+	if user.MFAEnabled() {
+		return "", "", fmt.Errorf("mfa service error enroll: %w", ErrMFAAlreadyEnabled)
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return "", "", fmt.Errorf("mfa service error enroll: %w", err)
+	}
+	sealed, err := m.box.seal(secret)
+	if err != nil {
+		return "", "", fmt.Errorf("mfa service error enroll: %w", err)
+	}
+	err = m.userService.SetMFA(userID, sealed, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("mfa service error enroll: %w", err)
+	}
+	return secret, totpURI(m.config.MFAIssuer, user.Email, secret), nil
+}
+
+// Confirm enables mfa and returns the recovery codes, they are shown only this once.
+func (m mfaService) Confirm(userID int64, code string) ([]string, error) {
+	user, err := m.userService.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("mfa service error confirm: %w", err)
+	}
+	if user.MFAEnabled() {
+		return nil, fmt.Errorf("mfa service error confirm: %w", ErrMFAAlreadyEnabled)
+	}
+	if user.MFASecret == "" {
+		return nil, fmt.Errorf("mfa service error confirm: %w", ErrMFANotEnrolled)
+	}
+	secret, err := m.box.open(user.MFASecret)
+	if err != nil {
+		return nil, fmt.Errorf("mfa service error confirm: %w", err)
+	}
+	counter, ok := validateTOTP(secret, code, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("mfa service error confirm: %w", ErrInvalidMFACode)
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("mfa service error confirm: %w", err)
+	}
+	err = m.recoveryRepo.Replace(userID, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("mfa service error confirm: %w", err)
+	}
+	now := time.Now()
+	err = m.userService.SetMFA(userID, user.MFASecret, &now)
+	if err != nil {
+		return nil, fmt.Errorf("mfa service error confirm: %w", err)
+	}
+	err = m.userService.UseMFACounter(userID, counter)
+	if err != nil {
+		return nil, fmt.Errorf("mfa service error confirm: %w", err)
+	}
+	return codes, nil
+}
+
+// Disable turns mfa off for a user who gives a code, wrong codes count towards the login lockout.
+func (m mfaService) Disable(userID int64, code string, device domain.Device) error {
+	user, err := m.userService.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("mfa service error disable: %w", err)
+	}
+	err = m.lockoutService.Check(user.Email, device.IP)
+	if err != nil {
+		return fmt.Errorf("mfa service error disable: %w", err)
+	}
+	err = m.Validate(user, code)
+	if errors.Is(err, ErrInvalidMFACode) {
+		// a stolen session must not guess codes any faster than a login can
+		if locked := countFailure(m.lockoutService, user.Email, device.IP); locked != nil {
+			return fmt.Errorf("mfa service error disable: %w", locked)
+		}
+		return fmt.Errorf("mfa service error disable: %w", err)
+	} else if err != nil {
+		return fmt.Errorf("mfa service error disable: %w", err)
+	}
+	err = m.userService.SetMFA(userID, "", nil)
+	if err != nil {
+		return fmt.Errorf("mfa service error disable: %w", err)
+	}
+	err = m.recoveryRepo.DeleteAll(userID)
+	if err != nil {
+		return fmt.Errorf("mfa service error disable: %w", err)
+	}
+	return nil
+}
+
+// Validate accepts a current TOTP code or an unused recovery code. Each of them works only once.
+func (m mfaService) Validate(user domain.User, code string) error {
+	if !user.MFAEnabled() {
+		return fmt.Errorf("mfa service error validate: %w", ErrMFANotEnrolled)
+	}
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		err := m.recoveryRepo.Use(user.ID, hashUserToken(normalizeRecoveryCode(code)))
+		if errors.Is(err, database.ErrRecoveryCodeInvalid) {
+			return fmt.Errorf("mfa service error validate: %w", ErrInvalidMFACode)
+		} else if err != nil {
+			return fmt.Errorf("mfa service error validate: %w", err)
+		}
+		return nil
+	}
+
+	secret, err := m.box.open(user.MFASecret)
+	if err != nil {
+		return fmt.Errorf("mfa service error validate: %w", err)
+	}
+	counter, ok := validateTOTP(secret, code, time.Now())
+	if !ok {
+		return fmt.Errorf("mfa service error validate: %w", ErrInvalidMFACode)
+	}
+	err = m.userService.UseMFACounter(user.ID, counter)
+	if errors.Is(err, database.ErrMFACodeUsed) {
+		return fmt.Errorf("mfa service error validate: %w", ErrInvalidMFACode)
+	} else if err != nil {
+		return fmt.Errorf("mfa service error validate: %w", err)
+	}
+	return nil
+}
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes returns the codes formatted for the user, and the hashes to store.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodes)
+	hashes := make([]string, recoveryCodes)
+	for i := range codes {
+		b := make([]byte, 7)
+		_, err := rand.Read(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(recoveryEncoding.EncodeToString(b))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hashUserToken(code)
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	"trainee/config"
+	smocks "trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	rmocks "trainee/internal/infra/database/mock"
+)
+
+func Test_mfaService_Enroll(t *testing.T) {
+	conf := config.Configuration{MFAKey: "key", MFAIssuer: "trainee"}
+	enabled := time.Now()
+
+	t.Run("enroll stores the encrypted secret", func(t *testing.T) {
+		var sealed string
+		us := smocks.NewUserService(t)
+		us.On("FindByID", int64(1)).Return(domain.User{ID: 1, Email: "user@email.com"}, nil).Times(1)
+		us.On("SetMFA", int64(1), mock.AnythingOfType("string"), (*time.Time)(nil)).
+			Run(func(args mock.Arguments) { sealed = args.String(1) }).
+			Return(nil).Times(1)
+		m := NewMFAService(us, rmocks.NewRecoveryCodeRepo(t), smocks.NewLockoutService(t), conf)
+
+		secret, uri, err := m.Enroll(1)
+		assert.NoError(t, err)
+		assert.Contains(t, uri, "secret="+secret)
+		assert.NotEqual(t, secret, sealed)
+		plain, err := newSecretBox("key").open(sealed)
+		assert.NoError(t, err)
+		assert.Equal(t, secret, plain)
+	})
+
+	t.Run("enroll with mfa enabled", func(t *testing.T) {
+		us := smocks.NewUserService(t)
+		us.On("FindByID", int64(1)).Return(domain.User{ID: 1, MFAEnabledAt: &enabled}, nil).Times(1)
+		m := NewMFAService(us, rmocks.NewRecoveryCodeRepo(t), smocks.NewLockoutService(t), conf)
+
+		_, _, err := m.Enroll(1)
+		assert.ErrorIs(t, err, ErrMFAAlreadyEnabled)
+	})
+
+	t.Run("enroll without key", func(t *testing.T) {
+		m := NewMFAService(smocks.NewUserService(t), rmocks.NewRecoveryCodeRepo(t), smocks.NewLockoutService(t), config.Configuration{})
+
+		_, _, err := m.Enroll(1)
+		assert.ErrorIs(t, err, ErrMFANotConfigured)
+	})
+}
+
+func Test_mfaService_Confirm(t *testing.T) {
+	conf := config.Configuration{MFAKey: "key"}
+	secret, _ := newTOTPSecret()
+	sealed, _ := newSecretBox("key").seal(secret)
+	counter := totpCounter(time.Now())
+	code, _ := totpCode(secret, counter)
+
+	t.Run("confirm enables mfa and stores recovery codes", func(t *testing.T) {
+		var hashes []string
+		us := smocks.NewUserService(t)
+		us.
+			On("FindByID", int64(1)).Return(domain.User{ID: 1, MFASecret: sealed}, nil).Times(1).
+			On("SetMFA", int64(1), sealed, mock.AnythingOfType("*time.Time")).Return(nil).Times(1).
+			On("UseMFACounter", int64(1), mock.AnythingOfType("int64")).Return(nil).Times(1)
+		rr := rmocks.NewRecoveryCodeRepo(t)
+		rr.On("Replace", int64(1), mock.AnythingOfType("[]string")).
+			Run(func(args mock.Arguments) { hashes = args.Get(1).([]string) }).
+			Return(nil).Times(1)
+		m := NewMFAService(us, rr, smocks.NewLockoutService(t), conf)
+
+		codes, err := m.Confirm(1, code)
+		assert.NoError(t, err)
+		assert.Len(t, codes, recoveryCodes)
+		assert.Equal(t, hashUserToken(normalizeRecoveryCode(codes[0])), hashes[0])
+	})
+
+	t.Run("confirm with a wrong code", func(t *testing.T) {
+		wrong, _ := totpCode(secret, counter+5)
+		us := smocks.NewUserService(t)
+		us.On("FindByID", int64(1)).Return(domain.User{ID: 1, MFASecret: sealed}, nil).Times(1)
+		m := NewMFAService(us, rmocks.NewRecoveryCodeRepo(t), smocks.NewLockoutService(t), conf)
+
+		_, err := m.Confirm(1, wrong)
+		assert.ErrorIs(t, err, ErrInvalidMFACode)
+	})
+
+	t.Run("confirm without enroll", func(t *testing.T) {
+		us := smocks.NewUserService(t)
+		us.On("FindByID", int64(1)).Return(domain.User{ID: 1}, nil).Times(1)
+		m := NewMFAService(us, rmocks.NewRecoveryCodeRepo(t), smocks.NewLockoutService(t), conf)
+
+		_, err := m.Confirm(1, code)
+		assert.ErrorIs(t, err, ErrMFANotEnrolled)
+	})
+}
+
+func Test_mfaService_Validate(t *testing.T) {
+	conf := config.Configuration{MFAKey: "key"}
+	secret, _ := newTOTPSecret()
+	sealed, _ := newSecretBox("key").seal(secret)
+	counter := totpCounter(time.Now())
+	code, _ := totpCode(secret, counter)
+	enabled := time.Now()
+	user := domain.User{ID: 1, MFASecret: sealed, MFAEnabledAt: &enabled}
+
+	tests := []struct {
+		name    string
+		user    domain.User
+		code    string
+		us      func() UserService
+		rr      func() database.RecoveryCodeRepo
+		wantErr error
+	}{
+		{
+			"totp code",
+			user,
+			code,
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("UseMFACounter", int64(1), counter).Return(nil).Times(1)
+				return mock
+			},
+			func() database.RecoveryCodeRepo { return rmocks.NewRecoveryCodeRepo(t) },
+			nil,
+		},
+		{
+			"replayed totp code",
+			user,
+			code,
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("UseMFACounter", int64(1), counter).Return(database.ErrMFACodeUsed).Times(1)
+				return mock
+			},
+			func() database.RecoveryCodeRepo { return rmocks.NewRecoveryCodeRepo(t) },
+			ErrInvalidMFACode,
+		},
+		{
+			"recovery code",
+			user,
+			"ABCDE-fghij",
+			func() UserService { return smocks.NewUserService(t) },
+			func() database.RecoveryCodeRepo {
+				mock := rmocks.NewRecoveryCodeRepo(t)
+				mock.On("Use", int64(1), hashUserToken("abcdefghij")).Return(nil).Times(1)
+				return mock
+			},
+			nil,
+		},
+		{
+			"used recovery code",
+			user,
+			"abcde-fghij",
+			func() UserService { return smocks.NewUserService(t) },
+			func() database.RecoveryCodeRepo {
+				mock := rmocks.NewRecoveryCodeRepo(t)
+				mock.On("Use", int64(1), hashUserToken("abcdefghij")).Return(database.ErrRecoveryCodeInvalid).Times(1)
+				return mock
+			},
+			ErrInvalidMFACode,
+		},
+		{
+			"mfa not enabled",
+			domain.User{ID: 1},
+			code,
+			func() UserService { return smocks.NewUserService(t) },
+			func() database.RecoveryCodeRepo { return rmocks.NewRecoveryCodeRepo(t) },
+			ErrMFANotEnrolled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMFAService(tt.us(), tt.rr(), smocks.NewLockoutService(t), conf)
+			err := m.Validate(tt.user, tt.code)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_mfaService_Disable(t *testing.T) {
+	conf := config.Configuration{MFAKey: "key"}
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	secret, _ := newTOTPSecret()
+	sealed, _ := newSecretBox("key").seal(secret)
+	counter := totpCounter(time.Now())
+	code, _ := totpCode(secret, counter)
+	wrong, _ := totpCode(secret, counter+5)
+	enabled := time.Now()
+	user := domain.User{ID: 1, Email: "user@email.com", MFASecret: sealed, MFAEnabledAt: &enabled}
+	locked := &LockedError{RetryAfter: time.Minute}
+
+	tests := []struct {
+		name    string
+		code    string
+		us      func() UserService
+		rr      func() database.RecoveryCodeRepo
+		ls      func() LockoutService
+		wantErr error
+	}{
+		{
+			"right code",
+			code,
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(1)).Return(user, nil).Times(1).
+					On("UseMFACounter", int64(1), counter).Return(nil).Times(1).
+					On("SetMFA", int64(1), "", (*time.Time)(nil)).Return(nil).Times(1)
+				return mock
+			},
+			func() database.RecoveryCodeRepo {
+				mock := rmocks.NewRecoveryCodeRepo(t)
+				mock.On("DeleteAll", int64(1)).Return(nil).Times(1)
+				return mock
+			},
+			func() LockoutService {
+				mock := smocks.NewLockoutService(t)
+				mock.On("Check", user.Email, device.IP).Return(nil).Times(1)
+				return mock
+			},
+			nil,
+		},
+		{
+			"wrong code counts a failure",
+			wrong,
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("FindByID", int64(1)).Return(user, nil).Times(1)
+				return mock
+			},
+			func() database.RecoveryCodeRepo { return rmocks.NewRecoveryCodeRepo(t) },
+			func() LockoutService {
+				mock := smocks.NewLockoutService(t)
+				mock.
+					On("Check", user.Email, device.IP).Return(nil).Times(1).
+					On("Fail", user.Email, device.IP).Return(nil).Times(1)
+				return mock
+			},
+			ErrInvalidMFACode,
+		},
+		{
+			"wrong code that locks",
+			wrong,
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("FindByID", int64(1)).Return(user, nil).Times(1)
+				return mock
+			},
+			func() database.RecoveryCodeRepo { return rmocks.NewRecoveryCodeRepo(t) },
+			func() LockoutService {
+				mock := smocks.NewLockoutService(t)
+				mock.
+					On("Check", user.Email, device.IP).Return(nil).Times(1).
+					On("Fail", user.Email, device.IP).Return(locked).Times(1)
+				return mock
+			},
+			locked,
+		},
+		{
+			"locked account isn't checked",
+			code,
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("FindByID", int64(1)).Return(user, nil).Times(1)
+				return mock
+			},
+			func() database.RecoveryCodeRepo { return rmocks.NewRecoveryCodeRepo(t) },
+			func() LockoutService {
+				mock := smocks.NewLockoutService(t)
+				mock.On("Check", user.Email, device.IP).Return(locked).Times(1)
+				return mock
+			},
+			locked,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMFAService(tt.us(), tt.rr(), tt.ls(), conf)
+			err := m.Disable(1, tt.code, device)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APITokenService is an autogenerated mock type for the APITokenService type
+type APITokenService struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: token
+func (_m *APITokenService) Authenticate(token string) (domain.User, domain.APIToken, error) {
+	ret := _m.Called(token)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string) domain.User); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 domain.APIToken
+	if rf, ok := ret.Get(1).(func(string) domain.APIToken); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Get(1).(domain.APIToken)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(token)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Authorize provides a mock function with given fields: token, scope, device
+func (_m *APITokenService) Authorize(token domain.APIToken, scope domain.Scope, device domain.Device) error {
+	ret := _m.Called(token, scope, device)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.APIToken, domain.Scope, domain.Device) error); ok {
+		r0 = rf(token, scope, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: userID, name, scopes, ttlDays, device
+func (_m *APITokenService) Create(userID int64, name string, scopes []domain.Scope, ttlDays int, device domain.Device) (string, domain.APIToken, error) {
+	ret := _m.Called(userID, name, scopes, ttlDays, device)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(int64, string, []domain.Scope, int, domain.Device) string); ok {
+		r0 = rf(userID, name, scopes, ttlDays, device)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 domain.APIToken
+	if rf, ok := ret.Get(1).(func(int64, string, []domain.Scope, int, domain.Device) domain.APIToken); ok {
+		r1 = rf(userID, name, scopes, ttlDays, device)
+	} else {
+		r1 = ret.Get(1).(domain.APIToken)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int64, string, []domain.Scope, int, domain.Device) error); ok {
+		r2 = rf(userID, name, scopes, ttlDays, device)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Delete provides a mock function with given fields: id, userID, device
+func (_m *APITokenService) Delete(id int64, userID int64, device domain.Device) error {
+	ret := _m.Called(id, userID, device)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, int64, domain.Device) error); ok {
+		r0 = rf(id, userID, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// List provides a mock function with given fields: userID
+func (_m *APITokenService) List(userID int64) ([]domain.APIToken, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.APIToken
+	if rf, ok := ret.Get(0).(func(int64) []domain.APIToken); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.APIToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Touch provides a mock function with given fields: id
+func (_m *APITokenService) Touch(id int64) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAPITokenService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPITokenService creates a new instance of APITokenService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPITokenService(t mockConstructorTestingTNewAPITokenService) *APITokenService {
+	mock := &APITokenService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
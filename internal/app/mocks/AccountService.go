@@ -0,0 +1,64 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccountService is an autogenerated mock type for the AccountService type
+type AccountService struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: userID, password, device
+func (_m *AccountService) Delete(userID int64, password string, device domain.Device) error {
+	ret := _m.Called(userID, password, device)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string, domain.Device) error); ok {
+		r0 = rf(userID, password, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Export provides a mock function with given fields: userID
+func (_m *AccountService) Export(userID int64) (domain.AccountExport, error) {
+	ret := _m.Called(userID)
+
+	var r0 domain.AccountExport
+	if rf, ok := ret.Get(0).(func(int64) domain.AccountExport); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(domain.AccountExport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAccountService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAccountService creates a new instance of AccountService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAccountService(t mockConstructorTestingTNewAccountService) *AccountService {
+	mock := &AccountService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuthEventService is an autogenerated mock type for the AuthEventService type
+type AuthEventService struct {
+	mock.Mock
+}
+
+// Find provides a mock function with given fields: filter
+func (_m *AuthEventService) Find(filter domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	ret := _m.Called(filter)
+
+	var r0 []domain.AuthEvent
+	if rf, ok := ret.Get(0).(func(domain.AuthEventFilter) []domain.AuthEvent); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuthEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.AuthEventFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Record provides a mock function with given fields: event
+func (_m *AuthEventService) Record(event domain.AuthEvent) {
+	_m.Called(event)
+}
+
+type mockConstructorTestingTNewAuthEventService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuthEventService creates a new instance of AuthEventService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuthEventService(t mockConstructorTestingTNewAuthEventService) *AuthEventService {
+	mock := &AuthEventService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,10 +4,9 @@ package mocks
 
 import (
 	domain "trainee/internal/domain"
+	requests "trainee/internal/infra/http/requests"
 
 	mock "github.com/stretchr/testify/mock"
-
-	requests "trainee/internal/infra/http/requests"
 )
 
 // AuthService is an autogenerated mock type for the AuthService type
@@ -15,34 +14,253 @@ type AuthService struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: user
-func (_m *AuthService) Login(user requests.LoginAuth) (string, string, int64, error) {
-	ret := _m.Called(user)
+// BeginSession provides a mock function with given fields: user, device
+func (_m *AuthService) BeginSession(user domain.User, device domain.Device) (string, string, int64, error) {
+	ret := _m.Called(user, device)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(requests.LoginAuth) string); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(domain.User, domain.Device) string); ok {
+		r0 = rf(user, device)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 string
-	if rf, ok := ret.Get(1).(func(requests.LoginAuth) string); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(domain.User, domain.Device) string); ok {
+		r1 = rf(user, device)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 int64
+	if rf, ok := ret.Get(2).(func(domain.User, domain.Device) int64); ok {
+		r2 = rf(user, device)
+	} else {
+		r2 = ret.Get(2).(int64)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(domain.User, domain.Device) error); ok {
+		r3 = rf(user, device)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// CreateSession provides a mock function with given fields: user, device
+func (_m *AuthService) CreateSession(user domain.User, device domain.Device) (string, string, int64, error) {
+	ret := _m.Called(user, device)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(domain.User, domain.Device) string); ok {
+		r0 = rf(user, device)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(domain.User, domain.Device) string); ok {
+		r1 = rf(user, device)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 int64
+	if rf, ok := ret.Get(2).(func(domain.User, domain.Device) int64); ok {
+		r2 = rf(user, device)
+	} else {
+		r2 = ret.Get(2).(int64)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(domain.User, domain.Device) error); ok {
+		r3 = rf(user, device)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// GetSessions provides a mock function with given fields: userID
+func (_m *AuthService) GetSessions(userID int64) ([]domain.Session, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.Session
+	if rf, ok := ret.Get(0).(func(int64) []domain.Session); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Impersonate provides a mock function with given fields: actorID, userID, device
+func (_m *AuthService) Impersonate(actorID int64, userID int64, device domain.Device) (string, int64, error) {
+	ret := _m.Called(actorID, userID, device)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(int64, int64, domain.Device) string); ok {
+		r0 = rf(actorID, userID, device)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(int64, int64, domain.Device) int64); ok {
+		r1 = rf(actorID, userID, device)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int64, int64, domain.Device) error); ok {
+		r2 = rf(actorID, userID, device)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Login provides a mock function with given fields: user, device
+func (_m *AuthService) Login(user requests.LoginAuth, device domain.Device) (string, string, int64, error) {
+	ret := _m.Called(user, device)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(requests.LoginAuth, domain.Device) string); ok {
+		r0 = rf(user, device)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(requests.LoginAuth, domain.Device) string); ok {
+		r1 = rf(user, device)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 int64
+	if rf, ok := ret.Get(2).(func(requests.LoginAuth, domain.Device) int64); ok {
+		r2 = rf(user, device)
+	} else {
+		r2 = ret.Get(2).(int64)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(requests.LoginAuth, domain.Device) error); ok {
+		r3 = rf(user, device)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// LoginMFA provides a mock function with given fields: mfaToken, code
+func (_m *AuthService) LoginMFA(mfaToken string, code string) (string, string, int64, error) {
+	ret := _m.Called(mfaToken, code)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(mfaToken, code)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, string) string); ok {
+		r1 = rf(mfaToken, code)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 int64
+	if rf, ok := ret.Get(2).(func(string, string) int64); ok {
+		r2 = rf(mfaToken, code)
+	} else {
+		r2 = ret.Get(2).(int64)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(string, string) error); ok {
+		r3 = rf(mfaToken, code)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// Logout provides a mock function with given fields: sessionID, userID, device
+func (_m *AuthService) Logout(sessionID string, userID int64, device domain.Device) error {
+	ret := _m.Called(sessionID, userID, device)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64, domain.Device) error); ok {
+		r0 = rf(sessionID, userID, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LogoutAll provides a mock function with given fields: userID, device
+func (_m *AuthService) LogoutAll(userID int64, device domain.Device) error {
+	ret := _m.Called(userID, device)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, domain.Device) error); ok {
+		r0 = rf(userID, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Refresh provides a mock function with given fields: refreshToken, device
+func (_m *AuthService) Refresh(refreshToken string, device domain.Device) (string, string, int64, error) {
+	ret := _m.Called(refreshToken, device)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, domain.Device) string); ok {
+		r0 = rf(refreshToken, device)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, domain.Device) string); ok {
+		r1 = rf(refreshToken, device)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
 	var r2 int64
-	if rf, ok := ret.Get(2).(func(requests.LoginAuth) int64); ok {
-		r2 = rf(user)
+	if rf, ok := ret.Get(2).(func(string, domain.Device) int64); ok {
+		r2 = rf(refreshToken, device)
 	} else {
 		r2 = ret.Get(2).(int64)
 	}
 
 	var r3 error
-	if rf, ok := ret.Get(3).(func(requests.LoginAuth) error); ok {
-		r3 = rf(user)
+	if rf, ok := ret.Get(3).(func(string, domain.Device) error); ok {
+		r3 = rf(refreshToken, device)
 	} else {
 		r3 = ret.Error(3)
 	}
@@ -71,20 +289,48 @@ func (_m *AuthService) Register(user domain.User) (domain.User, error) {
 	return r0, r1
 }
 
-// ValidateJWT provides a mock function with given fields: tokenUID, userID, isRefresh
-func (_m *AuthService) ValidateJWT(tokenUID string, userID int64, isRefresh bool) (domain.User, error) {
-	ret := _m.Called(tokenUID, userID, isRefresh)
+// RevokeSession provides a mock function with given fields: sessionID, userID
+func (_m *AuthService) RevokeSession(sessionID string, userID int64) error {
+	ret := _m.Called(sessionID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64) error); ok {
+		r0 = rf(sessionID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchSession provides a mock function with given fields: sessionID
+func (_m *AuthService) TouchSession(sessionID string) error {
+	ret := _m.Called(sessionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ValidateJWT provides a mock function with given fields: tokenUID, sessionID, userID, role, isRefresh
+func (_m *AuthService) ValidateJWT(tokenUID string, sessionID string, userID int64, role domain.Role, isRefresh bool) (domain.User, error) {
+	ret := _m.Called(tokenUID, sessionID, userID, role, isRefresh)
 
 	var r0 domain.User
-	if rf, ok := ret.Get(0).(func(string, int64, bool) domain.User); ok {
-		r0 = rf(tokenUID, userID, isRefresh)
+	if rf, ok := ret.Get(0).(func(string, string, int64, domain.Role, bool) domain.User); ok {
+		r0 = rf(tokenUID, sessionID, userID, role, isRefresh)
 	} else {
 		r0 = ret.Get(0).(domain.User)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int64, bool) error); ok {
-		r1 = rf(tokenUID, userID, isRefresh)
+	if rf, ok := ret.Get(1).(func(string, string, int64, domain.Role, bool) error); ok {
+		r1 = rf(tokenUID, sessionID, userID, role, isRefresh)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -4,12 +4,10 @@ package mocks
 
 import (
 	domain "trainee/internal/domain"
+	requests "trainee/internal/infra/http/requests"
 
 	jwt "github.com/golang-jwt/jwt"
-
 	mock "github.com/stretchr/testify/mock"
-
-	requests "trainee/internal/infra/http/requests"
 )
 
 // CommentService is an autogenerated mock type for the CommentService type
@@ -17,13 +15,13 @@ type CommentService struct {
 	mock.Mock
 }
 
-// DeleteComment provides a mock function with given fields: id
-func (_m *CommentService) DeleteComment(id int64) error {
-	ret := _m.Called(id)
+// DeleteComment provides a mock function with given fields: id, token
+func (_m *CommentService) DeleteComment(id int64, token *jwt.Token) error {
+	ret := _m.Called(id, token)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int64, *jwt.Token) error); ok {
+		r0 = rf(id, token)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,20 +29,20 @@ func (_m *CommentService) DeleteComment(id int64) error {
 	return r0
 }
 
-// GetComment provides a mock function with given fields: id
-func (_m *CommentService) GetComment(id int64) (domain.Comment, error) {
-	ret := _m.Called(id)
+// GetComment provides a mock function with given fields: id, token
+func (_m *CommentService) GetComment(id int64, token *jwt.Token) (domain.Comment, error) {
+	ret := _m.Called(id, token)
 
 	var r0 domain.Comment
-	if rf, ok := ret.Get(0).(func(int64) domain.Comment); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int64, *jwt.Token) domain.Comment); ok {
+		r0 = rf(id, token)
 	} else {
 		r0 = ret.Get(0).(domain.Comment)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(int64, *jwt.Token) error); ok {
+		r1 = rf(id, token)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -75,6 +73,29 @@ func (_m *CommentService) GetCommentsByPostID(postID int64, offset int) ([]domai
 	return r0, r1
 }
 
+// GetCommentsByUser provides a mock function with given fields: userID
+func (_m *CommentService) GetCommentsByUser(userID int64) ([]domain.Comment, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.Comment
+	if rf, ok := ret.Get(0).(func(int64) []domain.Comment); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveComment provides a mock function with given fields: commentRequest, postID, token
 func (_m *CommentService) SaveComment(commentRequest requests.CommentRequest, postID int64, token *jwt.Token) (domain.Comment, error) {
 	ret := _m.Called(commentRequest, postID, token)
@@ -96,20 +117,20 @@ func (_m *CommentService) SaveComment(commentRequest requests.CommentRequest, po
 	return r0, r1
 }
 
-// UpdateComment provides a mock function with given fields: commentRequest, id
-func (_m *CommentService) UpdateComment(commentRequest requests.CommentRequest, id int64) (domain.Comment, error) {
-	ret := _m.Called(commentRequest, id)
+// UpdateComment provides a mock function with given fields: commentRequest, id, token
+func (_m *CommentService) UpdateComment(commentRequest requests.CommentRequest, id int64, token *jwt.Token) (domain.Comment, error) {
+	ret := _m.Called(commentRequest, id, token)
 
 	var r0 domain.Comment
-	if rf, ok := ret.Get(0).(func(requests.CommentRequest, int64) domain.Comment); ok {
-		r0 = rf(commentRequest, id)
+	if rf, ok := ret.Get(0).(func(requests.CommentRequest, int64, *jwt.Token) domain.Comment); ok {
+		r0 = rf(commentRequest, id, token)
 	} else {
 		r0 = ret.Get(0).(domain.Comment)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(requests.CommentRequest, int64) error); ok {
-		r1 = rf(commentRequest, id)
+	if rf, ok := ret.Get(1).(func(requests.CommentRequest, int64, *jwt.Token) error); ok {
+		r1 = rf(commentRequest, id, token)
 	} else {
 		r1 = ret.Error(1)
 	}
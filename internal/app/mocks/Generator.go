@@ -1,4 +1,4 @@
-// Code generated by mockery v2.14.0. DO NOT EDIT.
+// Code generated by mockery v2.15.0. DO NOT EDIT.
 
 package mocks
 
@@ -9,6 +9,20 @@ type Generator struct {
 	mock.Mock
 }
 
+// CompareHashAndPassword provides a mock function with given fields: hash, password
+func (_m *Generator) CompareHashAndPassword(hash string, password string) bool {
+	ret := _m.Called(hash, password)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(hash, password)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // GeneratePasswordHash provides a mock function with given fields: password
 func (_m *Generator) GeneratePasswordHash(password string) (string, error) {
 	ret := _m.Called(password)
@@ -30,6 +44,20 @@ func (_m *Generator) GeneratePasswordHash(password string) (string, error) {
 	return r0, r1
 }
 
+// NeedsRehash provides a mock function with given fields: hash
+func (_m *Generator) NeedsRehash(hash string) bool {
+	ret := _m.Called(hash)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewGenerator interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,81 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// LockoutService is an autogenerated mock type for the LockoutService type
+type LockoutService struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: email, ip
+func (_m *LockoutService) Check(email string, ip string) error {
+	ret := _m.Called(email, ip)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(email, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fail provides a mock function with given fields: email, ip
+func (_m *LockoutService) Fail(email string, ip string) error {
+	ret := _m.Called(email, ip)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(email, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reset provides a mock function with given fields: email
+func (_m *LockoutService) Reset(email string) error {
+	ret := _m.Called(email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unlock provides a mock function with given fields: email
+func (_m *LockoutService) Unlock(email string) error {
+	ret := _m.Called(email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLockoutService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLockoutService creates a new instance of LockoutService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLockoutService(t mockConstructorTestingTNewLockoutService) *LockoutService {
+	mock := &LockoutService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MFAService is an autogenerated mock type for the MFAService type
+type MFAService struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: userID, code
+func (_m *MFAService) Confirm(userID int64, code string) ([]string, error) {
+	ret := _m.Called(userID, code)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(int64, string) []string); ok {
+		r0 = rf(userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
+		r1 = rf(userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Disable provides a mock function with given fields: userID, code, device
+func (_m *MFAService) Disable(userID int64, code string, device domain.Device) error {
+	ret := _m.Called(userID, code, device)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string, domain.Device) error); ok {
+		r0 = rf(userID, code, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enroll provides a mock function with given fields: userID
+func (_m *MFAService) Enroll(userID int64) (string, string, error) {
+	ret := _m.Called(userID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(int64) string); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(int64) string); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(int64) error); ok {
+		r2 = rf(userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Validate provides a mock function with given fields: user, code
+func (_m *MFAService) Validate(user domain.User, code string) error {
+	ret := _m.Called(user, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.User, string) error); ok {
+		r0 = rf(user, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMFAService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMFAService creates a new instance of MFAService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMFAService(t mockConstructorTestingTNewMFAService) *MFAService {
+	mock := &MFAService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,162 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OAuthService is an autogenerated mock type for the OAuthService type
+type OAuthService struct {
+	mock.Mock
+}
+
+// AuthCodeURL provides a mock function with given fields: provider
+func (_m *OAuthService) AuthCodeURL(provider string) (string, string, error) {
+	ret := _m.Called(provider)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(provider)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string) string); ok {
+		r1 = rf(provider)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(provider)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Callback provides a mock function with given fields: provider, state, code, device
+func (_m *OAuthService) Callback(provider string, state string, code string, device domain.Device) (string, string, int64, error) {
+	ret := _m.Called(provider, state, code, device)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string, domain.Device) string); ok {
+		r0 = rf(provider, state, code, device)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, string, string, domain.Device) string); ok {
+		r1 = rf(provider, state, code, device)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 int64
+	if rf, ok := ret.Get(2).(func(string, string, string, domain.Device) int64); ok {
+		r2 = rf(provider, state, code, device)
+	} else {
+		r2 = ret.Get(2).(int64)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(string, string, string, domain.Device) error); ok {
+		r3 = rf(provider, state, code, device)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// Login provides a mock function with given fields: identity, device
+func (_m *OAuthService) Login(identity domain.Identity, device domain.Device) (string, string, int64, error) {
+	ret := _m.Called(identity, device)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(domain.Identity, domain.Device) string); ok {
+		r0 = rf(identity, device)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(domain.Identity, domain.Device) string); ok {
+		r1 = rf(identity, device)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 int64
+	if rf, ok := ret.Get(2).(func(domain.Identity, domain.Device) int64); ok {
+		r2 = rf(identity, device)
+	} else {
+		r2 = ret.Get(2).(int64)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(domain.Identity, domain.Device) error); ok {
+		r3 = rf(identity, device)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// NewState provides a mock function with given fields: provider
+func (_m *OAuthService) NewState(provider string) (string, error) {
+	ret := _m.Called(provider)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(provider)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(provider)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyState provides a mock function with given fields: provider, state
+func (_m *OAuthService) VerifyState(provider string, state string) error {
+	ret := _m.Called(provider, state)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(provider, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewOAuthService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewOAuthService creates a new instance of OAuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewOAuthService(t mockConstructorTestingTNewOAuthService) *OAuthService {
+	mock := &OAuthService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
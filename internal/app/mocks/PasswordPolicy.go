@@ -0,0 +1,43 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordPolicy is an autogenerated mock type for the PasswordPolicy type
+type PasswordPolicy struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: password, user
+func (_m *PasswordPolicy) Check(password string, user domain.User) error {
+	ret := _m.Called(password, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.User) error); ok {
+		r0 = rf(password, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPasswordPolicy interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordPolicy creates a new instance of PasswordPolicy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordPolicy(t mockConstructorTestingTNewPasswordPolicy) *PasswordPolicy {
+	mock := &PasswordPolicy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,71 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordService is an autogenerated mock type for the PasswordService type
+type PasswordService struct {
+	mock.Mock
+}
+
+// Change provides a mock function with given fields: userID, sessionID, current, password, device
+func (_m *PasswordService) Change(userID int64, sessionID string, current string, password string, device domain.Device) error {
+	ret := _m.Called(userID, sessionID, current, password, device)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string, string, string, domain.Device) error); ok {
+		r0 = rf(userID, sessionID, current, password, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Forgot provides a mock function with given fields: email
+func (_m *PasswordService) Forgot(email string) error {
+	ret := _m.Called(email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reset provides a mock function with given fields: token, password, device
+func (_m *PasswordService) Reset(token string, password string, device domain.Device) error {
+	ret := _m.Called(token, password, device)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, domain.Device) error); ok {
+		r0 = rf(token, password, device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPasswordService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordService creates a new instance of PasswordService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordService(t mockConstructorTestingTNewPasswordService) *PasswordService {
+	mock := &PasswordService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,86 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	jwt "github.com/golang-jwt/jwt"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Policy is an autogenerated mock type for the Policy type
+type Policy struct {
+	mock.Mock
+}
+
+// CanDeleteComment provides a mock function with given fields: token, comment
+func (_m *Policy) CanDeleteComment(token *jwt.Token, comment domain.Comment) error {
+	ret := _m.Called(token, comment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*jwt.Token, domain.Comment) error); ok {
+		r0 = rf(token, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CanModifyPost provides a mock function with given fields: token, post
+func (_m *Policy) CanModifyPost(token *jwt.Token, post domain.Post) error {
+	ret := _m.Called(token, post)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*jwt.Token, domain.Post) error); ok {
+		r0 = rf(token, post)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CanUpdateComment provides a mock function with given fields: token, comment
+func (_m *Policy) CanUpdateComment(token *jwt.Token, comment domain.Comment) error {
+	ret := _m.Called(token, comment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*jwt.Token, domain.Comment) error); ok {
+		r0 = rf(token, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CanViewPost provides a mock function with given fields: token, post
+func (_m *Policy) CanViewPost(token *jwt.Token, post domain.Post) error {
+	ret := _m.Called(token, post)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*jwt.Token, domain.Post) error); ok {
+		r0 = rf(token, post)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPolicy interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPolicy creates a new instance of Policy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPolicy(t mockConstructorTestingTNewPolicy) *Policy {
+	mock := &Policy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	jwt "github.com/golang-jwt/jwt"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PostRevisionService is an autogenerated mock type for the PostRevisionService type
+type PostRevisionService struct {
+	mock.Mock
+}
+
+// Diff provides a mock function with given fields: postID, from, to, token
+func (_m *PostRevisionService) Diff(postID int64, from int, to int, token *jwt.Token) (domain.PostDiff, error) {
+	ret := _m.Called(postID, from, to, token)
+
+	var r0 domain.PostDiff
+	if rf, ok := ret.Get(0).(func(int64, int, int, *jwt.Token) domain.PostDiff); ok {
+		r0 = rf(postID, from, to, token)
+	} else {
+		r0 = ret.Get(0).(domain.PostDiff)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int, int, *jwt.Token) error); ok {
+		r1 = rf(postID, from, to, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRevisions provides a mock function with given fields: postID, token
+func (_m *PostRevisionService) FindRevisions(postID int64, token *jwt.Token) ([]domain.PostRevision, error) {
+	ret := _m.Called(postID, token)
+
+	var r0 []domain.PostRevision
+	if rf, ok := ret.Get(0).(func(int64, *jwt.Token) []domain.PostRevision); ok {
+		r0 = rf(postID, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PostRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, *jwt.Token) error); ok {
+		r1 = rf(postID, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: postID, revision, token
+func (_m *PostRevisionService) Restore(postID int64, revision int, token *jwt.Token) (domain.Post, error) {
+	ret := _m.Called(postID, revision, token)
+
+	var r0 domain.Post
+	if rf, ok := ret.Get(0).(func(int64, int, *jwt.Token) domain.Post); ok {
+		r0 = rf(postID, revision, token)
+	} else {
+		r0 = ret.Get(0).(domain.Post)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int, *jwt.Token) error); ok {
+		r1 = rf(postID, revision, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewPostRevisionService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPostRevisionService creates a new instance of PostRevisionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPostRevisionService(t mockConstructorTestingTNewPostRevisionService) *PostRevisionService {
+	mock := &PostRevisionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
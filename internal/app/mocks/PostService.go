@@ -3,13 +3,12 @@
 package mocks
 
 import (
+	time "time"
 	domain "trainee/internal/domain"
+	requests "trainee/internal/infra/http/requests"
 
 	jwt "github.com/golang-jwt/jwt"
-
 	mock "github.com/stretchr/testify/mock"
-
-	requests "trainee/internal/infra/http/requests"
 )
 
 // PostService is an autogenerated mock type for the PostService type
@@ -17,13 +16,34 @@ type PostService struct {
 	mock.Mock
 }
 
-// DeletePost provides a mock function with given fields: id
-func (_m *PostService) DeletePost(id int64) error {
-	ret := _m.Called(id)
+// ArchivePost provides a mock function with given fields: id, token
+func (_m *PostService) ArchivePost(id int64, token *jwt.Token) (domain.Post, error) {
+	ret := _m.Called(id, token)
+
+	var r0 domain.Post
+	if rf, ok := ret.Get(0).(func(int64, *jwt.Token) domain.Post); ok {
+		r0 = rf(id, token)
+	} else {
+		r0 = ret.Get(0).(domain.Post)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, *jwt.Token) error); ok {
+		r1 = rf(id, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePost provides a mock function with given fields: id, token
+func (_m *PostService) DeletePost(id int64, token *jwt.Token) error {
+	ret := _m.Called(id, token)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int64, *jwt.Token) error); ok {
+		r0 = rf(id, token)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,20 +51,20 @@ func (_m *PostService) DeletePost(id int64) error {
 	return r0
 }
 
-// GetPost provides a mock function with given fields: id
-func (_m *PostService) GetPost(id int64) (domain.Post, error) {
-	ret := _m.Called(id)
+// GetPost provides a mock function with given fields: id, token
+func (_m *PostService) GetPost(id int64, token *jwt.Token) (domain.Post, error) {
+	ret := _m.Called(id, token)
 
 	var r0 domain.Post
-	if rf, ok := ret.Get(0).(func(int64) domain.Post); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int64, *jwt.Token) domain.Post); ok {
+		r0 = rf(id, token)
 	} else {
 		r0 = ret.Get(0).(domain.Post)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(int64, *jwt.Token) error); ok {
+		r1 = rf(id, token)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -75,6 +95,69 @@ func (_m *PostService) GetPostsByUser(userID int64) ([]domain.Post, error) {
 	return r0, r1
 }
 
+// ListPosts provides a mock function with given fields: filter, cursor
+func (_m *PostService) ListPosts(filter domain.PostFilter, cursor string) (domain.PostPage, error) {
+	ret := _m.Called(filter, cursor)
+
+	var r0 domain.PostPage
+	if rf, ok := ret.Get(0).(func(domain.PostFilter, string) domain.PostPage); ok {
+		r0 = rf(filter, cursor)
+	} else {
+		r0 = ret.Get(0).(domain.PostPage)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.PostFilter, string) error); ok {
+		r1 = rf(filter, cursor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishPost provides a mock function with given fields: id, publishAt, token
+func (_m *PostService) PublishPost(id int64, publishAt *time.Time, token *jwt.Token) (domain.Post, error) {
+	ret := _m.Called(id, publishAt, token)
+
+	var r0 domain.Post
+	if rf, ok := ret.Get(0).(func(int64, *time.Time, *jwt.Token) domain.Post); ok {
+		r0 = rf(id, publishAt, token)
+	} else {
+		r0 = ret.Get(0).(domain.Post)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, *time.Time, *jwt.Token) error); ok {
+		r1 = rf(id, publishAt, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishScheduled provides a mock function with given fields:
+func (_m *PostService) PublishScheduled() (int64, error) {
+	ret := _m.Called()
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SavePost provides a mock function with given fields: postRequest, token
 func (_m *PostService) SavePost(postRequest requests.PostRequest, token *jwt.Token) (domain.Post, error) {
 	ret := _m.Called(postRequest, token)
@@ -96,20 +179,41 @@ func (_m *PostService) SavePost(postRequest requests.PostRequest, token *jwt.Tok
 	return r0, r1
 }
 
-// UpdatePost provides a mock function with given fields: postRequest, postID
-func (_m *PostService) UpdatePost(postRequest requests.PostRequest, postID int64) (domain.Post, error) {
-	ret := _m.Called(postRequest, postID)
+// UnpublishPost provides a mock function with given fields: id, token
+func (_m *PostService) UnpublishPost(id int64, token *jwt.Token) (domain.Post, error) {
+	ret := _m.Called(id, token)
+
+	var r0 domain.Post
+	if rf, ok := ret.Get(0).(func(int64, *jwt.Token) domain.Post); ok {
+		r0 = rf(id, token)
+	} else {
+		r0 = ret.Get(0).(domain.Post)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, *jwt.Token) error); ok {
+		r1 = rf(id, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePost provides a mock function with given fields: postRequest, postID, token
+func (_m *PostService) UpdatePost(postRequest requests.PostRequest, postID int64, token *jwt.Token) (domain.Post, error) {
+	ret := _m.Called(postRequest, postID, token)
 
 	var r0 domain.Post
-	if rf, ok := ret.Get(0).(func(requests.PostRequest, int64) domain.Post); ok {
-		r0 = rf(postRequest, postID)
+	if rf, ok := ret.Get(0).(func(requests.PostRequest, int64, *jwt.Token) domain.Post); ok {
+		r0 = rf(postRequest, postID, token)
 	} else {
 		r0 = ret.Get(0).(domain.Post)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(requests.PostRequest, int64) error); ok {
-		r1 = rf(postRequest, postID)
+	if rf, ok := ret.Get(1).(func(requests.PostRequest, int64, *jwt.Token) error); ok {
+		r1 = rf(postRequest, postID, token)
 	} else {
 		r1 = ret.Error(1)
 	}
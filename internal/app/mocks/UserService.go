@@ -1,8 +1,9 @@
-// Code generated by mockery v2.14.0. DO NOT EDIT.
+// Code generated by mockery v2.15.0. DO NOT EDIT.
 
 package mocks
 
 import (
+	time "time"
 	domain "trainee/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,6 +14,20 @@ type UserService struct {
 	mock.Mock
 }
 
+// CheckPassword provides a mock function with given fields: user, password
+func (_m *UserService) CheckPassword(user domain.User, password string) error {
+	ret := _m.Called(user, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.User, string) error); ok {
+		r0 = rf(user, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: id
 func (_m *UserService) Delete(id int64) error {
 	ret := _m.Called(id)
@@ -69,6 +84,27 @@ func (_m *UserService) FindByID(id int64) (domain.User, error) {
 	return r0, r1
 }
 
+// MarkEmailVerified provides a mock function with given fields: id
+func (_m *UserService) MarkEmailVerified(id int64) (domain.User, error) {
+	ret := _m.Called(id)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(int64) domain.User); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: user
 func (_m *UserService) Save(user domain.User) (domain.User, error) {
 	ret := _m.Called(user)
@@ -90,6 +126,90 @@ func (_m *UserService) Save(user domain.User) (domain.User, error) {
 	return r0, r1
 }
 
+// SetMFA provides a mock function with given fields: id, secret, enabledAt
+func (_m *UserService) SetMFA(id int64, secret string, enabledAt *time.Time) error {
+	ret := _m.Called(id, secret, enabledAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string, *time.Time) error); ok {
+		r0 = rf(id, secret, enabledAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: id, password
+func (_m *UserService) UpdatePassword(id int64, password string) (domain.User, error) {
+	ret := _m.Called(id, password)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(int64, string) domain.User); ok {
+		r0 = rf(id, password)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
+		r1 = rf(id, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRole provides a mock function with given fields: id, role
+func (_m *UserService) UpdateRole(id int64, role domain.Role) (domain.User, error) {
+	ret := _m.Called(id, role)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(int64, domain.Role) domain.User); ok {
+		r0 = rf(id, role)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, domain.Role) error); ok {
+		r1 = rf(id, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseMFACounter provides a mock function with given fields: id, counter
+func (_m *UserService) UseMFACounter(id int64, counter int64) error {
+	ret := _m.Called(id, counter)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, int64) error); ok {
+		r0 = rf(id, counter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyPassword provides a mock function with given fields: user, password
+func (_m *UserService) VerifyPassword(user domain.User, password string) bool {
+	ret := _m.Called(user, password)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(domain.User, string) bool); ok {
+		r0 = rf(user, password)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewUserService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,71 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// VerificationService is an autogenerated mock type for the VerificationService type
+type VerificationService struct {
+	mock.Mock
+}
+
+// Resend provides a mock function with given fields: email
+func (_m *VerificationService) Resend(email string) error {
+	ret := _m.Called(email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Send provides a mock function with given fields: user
+func (_m *VerificationService) Send(user domain.User) error {
+	ret := _m.Called(user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.User) error); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Verify provides a mock function with given fields: token
+func (_m *VerificationService) Verify(token string) error {
+	ret := _m.Called(token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewVerificationService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewVerificationService creates a new instance of VerificationService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewVerificationService(t mockConstructorTestingTNewVerificationService) *VerificationService {
+	mock := &VerificationService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
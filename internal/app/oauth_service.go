@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/upper/db/v4"
+	"time"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	"trainee/internal/infra/oauth"
+	"trainee/internal/infra/store"
+)
+
+// OAuthStateTTL is how long a login started with a provider can take.
+const OAuthStateTTL = 10 * time.Minute
+
+var (
+	ErrInvalidOAuthState = errors.New("invalid or expired oauth state")
+	ErrUnverifiedEmail   = errors.New("provider email is not verified")
+	ErrUnknownProvider   = errors.New("unknown oauth provider")
+	ErrOAuthProvider     = errors.New("oauth provider error")
+)
+
+//go:generate mockery --dir . --name OAuthService --output ./mocks
+type OAuthService interface {
+	AuthCodeURL(provider string) (string, string, error)
+	Callback(provider, state, code string, device domain.Device) (string, string, int64, error)
+	NewState(provider string) (string, error)
+	VerifyState(provider, state string) error
+	Login(identity domain.Identity, device domain.Device) (string, string, int64, error)
+}
+
+type oauthService struct {
+	identityRepo database.IdentityRepo
+	userService  UserService
+	authService  AuthService
+	eventService AuthEventService
+	providers    oauth.Registry
+	store        store.SessionStore
+}
+
+func NewOAuthService(ir database.IdentityRepo, us UserService, as AuthService, es AuthEventService, providers oauth.Registry, st store.SessionStore) OAuthService {
+	return oauthService{
+		identityRepo: ir,
+		providers:    providers,
+		userService:  us,
+		authService:  as,
+		eventService: es,
+		store:        st,
+	}
+}
+
+// AuthCodeURL starts a login with the provider, it gives the url to send the browser to and the state that
+// the callback has to come back with. The caller binds the state to the browser.
+func (o oauthService) AuthCodeURL(provider string) (string, string, error) {
+	p, ok := o.providers.Get(provider)
+	if !ok {
+		return "", "", fmt.Errorf("oauth service error auth code url: %w", ErrUnknownProvider)
+	}
+	state, err := o.NewState(provider)
+	if err != nil {
+		return "", "", err
+	}
+	return p.AuthCodeURL(state), state, nil
+}
+
+func (o oauthService) Callback(provider, state, code string, device domain.Device) (string, string, int64, error) {
+	p, ok := o.providers.Get(provider)
+	if !ok {
+		return "", "", 0, fmt.Errorf("oauth service error callback: %w", ErrUnknownProvider)
+	}
+	err := o.VerifyState(provider, state)
+	if err != nil {
+		return "", "", 0, err
+	}
+	identity, err := p.Identity(context.Background(), code)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("oauth service error callback: %w: %s", ErrOAuthProvider, err)
+	}
+	return o.Login(identity, device)
+}
+
+func (o oauthService) NewState(provider string) (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("oauth service error new state: %w", err)
+	}
+	state := base64.URLEncoding.EncodeToString(b)
+	err = o.store.Set(oauthStateKey(provider, state), "1", OAuthStateTTL)
+	if err != nil {
+		return "", fmt.Errorf("oauth service error new state: %w", err)
+	}
+	return state, nil
+}
+
+// VerifyState consumes the state, so a callback can't be replayed with it.
+// The state is bound to the provider it was issued for.
+func (o oauthService) VerifyState(provider, state string) error {
+	if state == "" {
+		return fmt.Errorf("oauth service error verify state: %w", ErrInvalidOAuthState)
+	}
+	_, err := o.store.Take(oauthStateKey(provider, state))
+	if errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("oauth service error verify state: %w", ErrInvalidOAuthState)
+	} else if err != nil {
+		return fmt.Errorf("oauth service error verify state: %w", err)
+	}
+	return nil
+}
+
+func (o oauthService) Login(identity domain.Identity, device domain.Device) (string, string, int64, error) {
+	user, err := o.findOrCreateUser(identity, device)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("oauth service error login: %w", err)
+	}
+	return o.authService.BeginSession(user, device)
+}
+
+func (o oauthService) findOrCreateUser(identity domain.Identity, device domain.Device) (domain.User, error) {
+	linked, err := o.identityRepo.FindByProviderSubject(identity.Provider, identity.Subject)
+	if err == nil {
+		return o.userService.FindByID(linked.UserID)
+	} else if !errors.Is(err, db.ErrNoMoreRows) {
+		return domain.User{}, err
+	}
+
+	// linking by email is only safe when the provider vouches for the address
+	if !identity.EmailVerified {
+		event := domain.NewAuthEvent(domain.AuthEventOAuthLink, domain.AuthFailure, 0, device)
+		event.Email = identity.Email
+		event.Detail = fmt.Sprintf("%s email not verified", identity.Provider)
+		o.eventService.Record(event)
+		return domain.User{}, ErrUnverifiedEmail
+	}
+
+	// the provider vouched for the address, so there is nothing left to verify
+	user, err := o.userService.FindByEmail(identity.Email)
+	if errors.Is(err, db.ErrNoMoreRows) {
+		now := time.Now()
+		user, err = o.userService.Save(domain.User{
+			Email:           identity.Email,
+			Name:            identity.Name,
+			EmailVerifiedAt: &now,
+		})
+	} else if err == nil && !user.EmailVerified() {
+		user, err = o.userService.MarkEmailVerified(user.ID)
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	identity.UserID = user.ID
+	_, err = o.identityRepo.Save(identity)
+	if err != nil {
+		return domain.User{}, err
+	}
+	event := domain.NewAuthEvent(domain.AuthEventOAuthLink, domain.AuthSuccess, user.ID, device)
+	event.Email = identity.Email
+	event.Detail = identity.Provider
+	o.eventService.Record(event)
+	return user, nil
+}
+
+func oauthStateKey(provider, state string) string {
+	return fmt.Sprintf("oauth-state-%s-%s", provider, state)
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/upper/db/v4"
+	"testing"
+	smocks "trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	rmocks "trainee/internal/infra/database/mock"
+	"trainee/internal/infra/oauth"
+	omocks "trainee/internal/infra/oauth/mock"
+)
+
+func Test_oauthService_Login(t *testing.T) {
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	identity := domain.Identity{
+		Provider:      "google",
+		Subject:       "1234",
+		Email:         "user@gmail.com",
+		EmailVerified: true,
+		Name:          "Name",
+	}
+	user := domain.User{ID: 2, Email: "user@gmail.com", Name: "Name"}
+	// users created from a provider have no password and a verified email
+	linkedEvent := &domain.AuthEvent{UserID: 2, Email: "user@gmail.com", Type: domain.AuthEventOAuthLink, Outcome: domain.AuthSuccess, Detail: "google", IP: device.IP, UserAgent: device.UserAgent}
+	newUser := mock.MatchedBy(func(u domain.User) bool {
+		return u.Email == "user@gmail.com" && u.Name == "Name" && u.Password == "" && u.EmailVerified()
+	})
+
+	tests := []struct {
+		name         string
+		identity     domain.Identity
+		identityRepo func() database.IdentityRepo
+		us           func() UserService
+		as           func() AuthService
+		wantErr      error
+		event        *domain.AuthEvent
+	}{
+		{
+			"login with linked identity",
+			identity,
+			func() database.IdentityRepo {
+				mock := rmocks.NewIdentityRepo(t)
+				mock.
+					On("FindByProviderSubject", "google", "1234").
+					Return(domain.Identity{UserID: 2}, nil).Times(1)
+				return mock
+			},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("FindByID", int64(2)).Return(user, nil).Times(1)
+				return mock
+			},
+			func() AuthService {
+				mock := smocks.NewAuthService(t)
+				mock.On("BeginSession", user, device).Return("access", "refresh", int64(123), nil).Times(1)
+				return mock
+			},
+			nil,
+			nil,
+		},
+		{
+			"link identity to existing user with the same email",
+			identity,
+			func() database.IdentityRepo {
+				linked := identity
+				linked.UserID = 2
+				mock := rmocks.NewIdentityRepo(t)
+				mock.
+					On("FindByProviderSubject", "google", "1234").
+					Return(domain.Identity{}, db.ErrNoMoreRows).Times(1).
+					On("Save", linked).
+					Return(linked, nil).Times(1)
+				return mock
+			},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByEmail", "user@gmail.com").
+					Return(user, nil).Times(1).
+					On("MarkEmailVerified", int64(2)).
+					Return(user, nil).Times(1)
+				return mock
+			},
+			func() AuthService {
+				mock := smocks.NewAuthService(t)
+				mock.On("BeginSession", user, device).Return("access", "refresh", int64(123), nil).Times(1)
+				return mock
+			},
+			nil,
+			linkedEvent,
+		},
+		{
+			"register new user without password",
+			identity,
+			func() database.IdentityRepo {
+				linked := identity
+				linked.UserID = 2
+				mock := rmocks.NewIdentityRepo(t)
+				mock.
+					On("FindByProviderSubject", "google", "1234").
+					Return(domain.Identity{}, db.ErrNoMoreRows).Times(1).
+					On("Save", linked).
+					Return(linked, nil).Times(1)
+				return mock
+			},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByEmail", "user@gmail.com").
+					Return(domain.User{}, db.ErrNoMoreRows).Times(1).
+					On("Save", newUser).
+					Return(user, nil).Times(1)
+				return mock
+			},
+			func() AuthService {
+				mock := smocks.NewAuthService(t)
+				mock.On("BeginSession", user, device).Return("access", "refresh", int64(123), nil).Times(1)
+				return mock
+			},
+			nil,
+			linkedEvent,
+		},
+		{
+			"unverified email is never linked",
+			domain.Identity{Provider: "google", Subject: "1234", Email: "user@gmail.com"},
+			func() database.IdentityRepo {
+				mock := rmocks.NewIdentityRepo(t)
+				mock.
+					On("FindByProviderSubject", "google", "1234").
+					Return(domain.Identity{}, db.ErrNoMoreRows).Times(1)
+				return mock
+			},
+			func() UserService {
+				return smocks.NewUserService(t)
+			},
+			func() AuthService {
+				return smocks.NewAuthService(t)
+			},
+			ErrUnverifiedEmail,
+			&domain.AuthEvent{Email: "user@gmail.com", Type: domain.AuthEventOAuthLink, Outcome: domain.AuthFailure, Detail: "google email not verified", IP: device.IP, UserAgent: device.UserAgent},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOAuthService(tt.identityRepo(), tt.us(), tt.as(), recordsEvent(t, tt.event), nil, nil)
+			access, refresh, exp, err := o.Login(tt.identity, device)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "access", access)
+				assert.Equal(t, "refresh", refresh)
+				assert.Equal(t, int64(123), exp)
+			}
+		})
+	}
+}
+
+func Test_oauthService_UnknownProvider(t *testing.T) {
+	providers := oauth.Registry{"google": omocks.NewProvider(t)}
+	o := NewOAuthService(rmocks.NewIdentityRepo(t), smocks.NewUserService(t), smocks.NewAuthService(t), smocks.NewAuthEventService(t), providers, nil)
+
+	_, _, err := o.AuthCodeURL("github")
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+
+	_, _, _, err = o.Callback("github", "state", "code", domain.Device{})
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+}
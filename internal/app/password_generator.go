@@ -1,10 +1,40 @@
 package app
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"trainee/config"
+)
 
+const (
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+var errUnknownHash = errors.New("unknown password hash format")
+
+// Generator makes password hashes in one format and verifies the hashes of every format,
+// each hash names its algorithm and parameters.
+//
 //go:generate mockery --dir . --name Generator --output ./mocks
 type Generator interface {
 	GeneratePasswordHash(password string) (string, error)
+	CompareHashAndPassword(hash, password string) bool
+	NeedsRehash(hash string) bool
+}
+
+// NewPasswordGenerator returns the generator configured for new hashes.
+func NewPasswordGenerator(conf config.PasswordHash) Generator {
+	if conf.Algorithm == config.PasswordHashBcrypt {
+		return NewGeneratePasswordHash(conf.BcryptCost)
+	}
+	return NewArgon2idGenerator(conf.Argon2Memory, conf.Argon2Time, conf.Argon2Threads)
 }
 
 type generatePasswordHash struct {
@@ -21,3 +51,88 @@ func (g generatePasswordHash) GeneratePasswordHash(password string) (string, err
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), g.cost)
 	return string(bytes), err
 }
+
+func (g generatePasswordHash) CompareHashAndPassword(hash, password string) bool {
+	return comparePasswordHash(hash, password)
+}
+
+func (g generatePasswordHash) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != g.cost
+}
+
+type argon2idGenerator struct {
+	params argon2Params
+}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+func NewArgon2idGenerator(memory, time uint32, threads uint8) Generator {
+	return argon2idGenerator{
+		params: argon2Params{memory: memory, time: time, threads: threads},
+	}
+}
+
+// GeneratePasswordHash encodes the hash as $argon2id$v=19$m=<KiB>,t=<passes>,p=<lanes>$<salt>$<key>.
+func (g argon2idGenerator) GeneratePasswordHash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	p := g.params
+	key := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, argon2KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.memory, p.time, p.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (g argon2idGenerator) CompareHashAndPassword(hash, password string) bool {
+	return comparePasswordHash(hash, password)
+}
+
+func (g argon2idGenerator) NeedsRehash(hash string) bool {
+	p, _, key, err := decodeArgon2id(hash)
+	return err != nil || p != g.params || len(key) != argon2KeyLen
+}
+
+func comparePasswordHash(hash, password string) bool {
+	if hash == "" {
+		return false
+	}
+	if strings.HasPrefix(hash, "$argon2id$") {
+		p, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false
+		}
+		other := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func decodeArgon2id(hash string) (argon2Params, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return argon2Params{}, nil, nil, errUnknownHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return argon2Params{}, nil, nil, errUnknownHash
+	}
+	var p argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil || p.time == 0 || p.threads == 0 {
+		return argon2Params{}, nil, nil, errUnknownHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return argon2Params{}, nil, nil, errUnknownHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return argon2Params{}, nil, nil, errUnknownHash
+	}
+	return p, salt, key, nil
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"trainee/config"
+)
+
+func TestPasswordGenerators(t *testing.T) {
+	bcryptGen := NewGeneratePasswordHash(4)
+	argonGen := NewArgon2idGenerator(1024, 1, 1)
+
+	bcryptHash, err := bcryptGen.GeneratePasswordHash("password")
+	assert.NoError(t, err)
+	argonHash, err := argonGen.GeneratePasswordHash("password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(argonHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	// both formats verify whichever generator is configured
+	for _, g := range []Generator{bcryptGen, argonGen} {
+		assert.True(t, g.CompareHashAndPassword(bcryptHash, "password"))
+		assert.True(t, g.CompareHashAndPassword(argonHash, "password"))
+		assert.False(t, g.CompareHashAndPassword(bcryptHash, "wrong"))
+		assert.False(t, g.CompareHashAndPassword(argonHash, "wrong"))
+		assert.False(t, g.CompareHashAndPassword("", ""))
+		assert.False(t, g.CompareHashAndPassword("$argon2id$v=19$m=1024,t=1,p=0$c2FsdA$a2V5", "password"))
+	}
+
+	assert.False(t, bcryptGen.NeedsRehash(bcryptHash))
+	assert.True(t, bcryptGen.NeedsRehash(argonHash))
+	assert.True(t, NewGeneratePasswordHash(5).NeedsRehash(bcryptHash))
+
+	assert.False(t, argonGen.NeedsRehash(argonHash))
+	assert.True(t, argonGen.NeedsRehash(bcryptHash))
+	assert.True(t, NewArgon2idGenerator(2048, 1, 1).NeedsRehash(argonHash))
+	assert.True(t, NewArgon2idGenerator(1024, 2, 1).NeedsRehash(argonHash))
+}
+
+func TestNewPasswordGenerator(t *testing.T) {
+	g := NewPasswordGenerator(config.PasswordHash{Algorithm: config.PasswordHashBcrypt, BcryptCost: 4})
+	assert.IsType(t, generatePasswordHash{}, g)
+
+	g = NewPasswordGenerator(config.PasswordHash{Algorithm: config.PasswordHashArgon2id, Argon2Memory: 1024, Argon2Time: 1, Argon2Threads: 1})
+	assert.IsType(t, argon2idGenerator{}, g)
+}
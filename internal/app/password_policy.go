@@ -0,0 +1,94 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"trainee/config"
+	"trainee/internal/domain"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicyError lists every rule a password breaks, each as a message about the password field.
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password " + strings.Join(e.Violations, ", ")
+}
+
+//go:generate mockery --dir . --name PasswordPolicy --output ./mocks
+type PasswordPolicy interface {
+	Check(password string, user domain.User) error
+}
+
+type passwordPolicy struct {
+	config   config.PasswordPolicy
+	breached *breachedPasswords
+}
+
+func NewPasswordPolicy(conf config.PasswordPolicy) (PasswordPolicy, error) {
+	p := passwordPolicy{config: conf}
+	if conf.BreachedDir != "" {
+		ranges, err := newBreachedRangeDir(conf.BreachedDir)
+		if err != nil {
+			return nil, fmt.Errorf("password policy error breached dir: %w", err)
+		}
+		p.breached = &breachedPasswords{ranges: ranges}
+	}
+	return p, nil
+}
+
+// Check returns PasswordPolicyError when the password of the user breaks the policy.
+func (p passwordPolicy) Check(password string, user domain.User) error {
+	var violations []string
+	if utf8.RuneCountInString(password) < p.config.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.config.MinLength))
+	}
+	if characterClasses(password) < p.config.MinClasses {
+		violations = append(violations, fmt.Sprintf("must mix at least %d of lowercase letters, uppercase letters, digits and symbols", p.config.MinClasses))
+	}
+
+	lower := strings.ToLower(password)
+	local := strings.ToLower(strings.SplitN(user.Email, "@", 2)[0])
+	if len(local) >= 3 && strings.Contains(lower, local) {
+		violations = append(violations, "must not contain your email address")
+	}
+	name := strings.ToLower(strings.TrimSpace(user.Name))
+	if len(name) >= 3 && strings.Contains(lower, name) {
+		violations = append(violations, "must not contain your name")
+	}
+
+	if p.breached != nil {
+		breached, err := p.breached.Contains(password)
+		if err != nil {
+			return fmt.Errorf("password policy error check: %w", err)
+		}
+		if breached {
+			violations = append(violations, "has appeared in a data breach, choose another one")
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"trainee/config"
+	"trainee/internal/domain"
+)
+
+// writeBreachedRanges writes the passwords' hashes as range files, with a few other suffixes in each range.
+func writeBreachedRanges(t *testing.T, passwords ...string) string {
+	dir := t.TempDir()
+	ranges := map[string][]string{}
+	for i, p := range passwords {
+		sum := sha1.Sum([]byte(p))
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		ranges[hash[:5]] = append(ranges[hash[:5]], fmt.Sprintf("%s:%d", hash[5:], i+1))
+	}
+	for prefix, lines := range ranges {
+		lines = append(lines, strings.Repeat("0", 35)+":1", strings.Repeat("F", 35)+":2")
+		sort.Strings(lines)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(strings.Join(lines, "\r\n")), 0600))
+	}
+	return dir
+}
+
+// rangeLog is a range source that remembers what it was asked.
+type rangeLog struct {
+	breachedRanges
+	prefixes []string
+}
+
+func (l *rangeLog) Range(prefix string) (io.ReadCloser, error) {
+	l.prefixes = append(l.prefixes, prefix)
+	return l.breachedRanges.Range(prefix)
+}
+
+func Test_breachedPasswords_Contains(t *testing.T) {
+	var listed []string
+	for i := 0; i < 200; i++ {
+		listed = append(listed, fmt.Sprintf("password%d", i))
+	}
+	dir, err := newBreachedRangeDir(writeBreachedRanges(t, listed...))
+	assert.NoError(t, err)
+	ranges := &rangeLog{breachedRanges: dir}
+	b := breachedPasswords{ranges: ranges}
+
+	for _, p := range listed {
+		found, err := b.Contains(p)
+		assert.NoError(t, err)
+		assert.True(t, found, p)
+	}
+	for _, p := range []string{"", "password200", "correct-Horse-battery"} {
+		found, err := b.Contains(p)
+		assert.NoError(t, err)
+		assert.False(t, found, p)
+	}
+	// only the 5 character prefix of a hash is ever looked up
+	for _, prefix := range ranges.prefixes {
+		assert.Len(t, prefix, 5)
+	}
+
+	_, err = newBreachedRangeDir(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func Test_passwordPolicy_Check(t *testing.T) {
+	user := domain.User{Email: "jane.doe@email.com", Name: "Doris"}
+	policy, err := NewPasswordPolicy(config.PasswordPolicy{
+		MinLength:   10,
+		MinClasses:  3,
+		BreachedDir: writeBreachedRanges(t, "Summer2022!x"),
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		password string
+		want     []string
+	}{
+		{"correct-Horse-battery", nil},
+		{"short1A", []string{"must be at least 10 characters long"}},
+		{"alllowercase", []string{"must mix at least 3 of lowercase letters, uppercase letters, digits and symbols"}},
+		{"My-jane.doe-pass", []string{"must not contain your email address"}},
+		{"I-am-DORIS-2022", []string{"must not contain your name"}},
+		{"Summer2022!x", []string{"has appeared in a data breach, choose another one"}},
+		{"doris", []string{
+			"must be at least 10 characters long",
+			"must mix at least 3 of lowercase letters, uppercase letters, digits and symbols",
+			"must not contain your name",
+		}},
+	}
+	for _, tt := range tests {
+		err := policy.Check(tt.password, user)
+		if tt.want == nil {
+			assert.NoError(t, err, tt.password)
+			continue
+		}
+		var policyErr *PasswordPolicyError
+		if assert.True(t, errors.As(err, &policyErr), tt.password) {
+			assert.Equal(t, tt.want, policyErr.Violations, tt.password)
+		}
+	}
+}
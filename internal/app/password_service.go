@@ -0,0 +1,167 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/upper/db/v4"
+	"time"
+	"trainee/config"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	"trainee/internal/infra/mail"
+)
+
+const passwordResetTTL = 60
+
+var (
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	ErrWrongPassword     = errors.New("current password is wrong")
+)
+
+//go:generate mockery --dir . --name PasswordService --output ./mocks
+type PasswordService interface {
+	Forgot(email string) error
+	Reset(token, password string, device domain.Device) error
+	Change(userID int64, sessionID, current, password string, device domain.Device) error
+}
+
+type passwordService struct {
+	tokenRepo      database.UserTokenRepo
+	userService    UserService
+	authService    AuthService
+	eventService   AuthEventService
+	lockoutService LockoutService
+	mailer         mail.Mailer
+	config         config.Configuration
+}
+
+func NewPasswordService(tr database.UserTokenRepo, us UserService, as AuthService, es AuthEventService, ls LockoutService,
+	m mail.Mailer, conf config.Configuration) PasswordService {
+	return passwordService{
+		tokenRepo:      tr,
+		userService:    us,
+		authService:    as,
+		eventService:   es,
+		lockoutService: ls,
+		mailer:         m,
+		config:         conf,
+	}
+}
+
+// Forgot mails a reset token. An unknown address is not an error, so the endpoint can't be used to probe for accounts.
+func (p passwordService) Forgot(email string) error {
+	user, err := p.userService.FindByEmail(email)
+	if errors.Is(err, db.ErrNoMoreRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("password service error forgot: %w", err)
+	}
+
+	plain, token, err := newUserToken(user.ID, domain.TokenPasswordReset, time.Minute*passwordResetTTL)
+	if err != nil {
+		return fmt.Errorf("password service error forgot: %w", err)
+	}
+	_, err = p.tokenRepo.Save(token)
+	if err != nil {
+		return fmt.Errorf("password service error forgot: %w", err)
+	}
+
+	err = p.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use this link to set a new password, it expires in %d minutes:\n%s?token=%s\n\n"+
+			"If you didn't ask for it, ignore this mail.\n", passwordResetTTL, p.config.PasswordResetURL, plain),
+	})
+	if err != nil {
+		return fmt.Errorf("password service error forgot: %w", err)
+	}
+	return nil
+}
+
+// Reset sets the new password and logs the user out everywhere, as the old one may be known to someone else.
+func (p passwordService) Reset(plain, password string, device domain.Device) error {
+	token, err := p.tokenRepo.FindByHash(domain.TokenPasswordReset, hashUserToken(plain))
+	if errors.Is(err, db.ErrNoMoreRows) {
+		return fmt.Errorf("password service error reset: %w", ErrInvalidResetToken)
+	} else if err != nil {
+		return fmt.Errorf("password service error reset: %w", err)
+	}
+	if !token.Usable(time.Now()) {
+		return fmt.Errorf("password service error reset: %w", ErrInvalidResetToken)
+	}
+	// a password the policy refuses must not use up the token
+	user, err := p.userService.FindByID(token.UserID)
+	if err != nil {
+		return fmt.Errorf("password service error reset: %w", err)
+	}
+	err = p.userService.CheckPassword(user, password)
+	if err != nil {
+		return fmt.Errorf("password service error reset: %w", err)
+	}
+
+	err = p.tokenRepo.Use(token.ID)
+	if errors.Is(err, database.ErrTokenUsed) {
+		return fmt.Errorf("password service error reset: %w", ErrInvalidResetToken)
+	} else if err != nil {
+		return fmt.Errorf("password service error reset: %w", err)
+	}
+
+	_, err = p.userService.UpdatePassword(token.UserID, password)
+	if err != nil {
+		return fmt.Errorf("password service error reset: %w", err)
+	}
+	err = p.tokenRepo.UseAll(token.UserID, domain.TokenPasswordReset)
+	if err != nil {
+		return fmt.Errorf("password service error reset: %w", err)
+	}
+	event := domain.NewAuthEvent(domain.AuthEventPasswordChange, domain.AuthSuccess, token.UserID, device)
+	event.Detail = "reset"
+	p.eventService.Record(event)
+	err = p.authService.LogoutAll(token.UserID, device)
+	if err != nil {
+		return fmt.Errorf("password service error reset: %w", err)
+	}
+	return nil
+}
+
+// Change replaces the password of a logged in user who knows the current one, and revokes their other sessions.
+// Wrong current passwords count towards the login lockout, so a stolen session can't be used to guess it.
+func (p passwordService) Change(userID int64, sessionID, current, password string, device domain.Device) error {
+	user, err := p.userService.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("password service error change: %w", err)
+	}
+	err = p.lockoutService.Check(user.Email, device.IP)
+	if err != nil {
+		return fmt.Errorf("password service error change: %w", err)
+	}
+	if !p.userService.VerifyPassword(user, current) {
+		event := domain.NewAuthEvent(domain.AuthEventPasswordChange, domain.AuthFailure, userID, device)
+		event.Detail = "wrong current password"
+		p.eventService.Record(event)
+		if locked := countFailure(p.lockoutService, user.Email, device.IP); locked != nil {
+			return fmt.Errorf("password service error change: %w", locked)
+		}
+		return fmt.Errorf("password service error change: %w", ErrWrongPassword)
+	}
+	_, err = p.userService.UpdatePassword(userID, password)
+	if err != nil {
+		return fmt.Errorf("password service error change: %w", err)
+	}
+	p.eventService.Record(domain.NewAuthEvent(domain.AuthEventPasswordChange, domain.AuthSuccess, userID, device))
+
+	sessions, err := p.authService.GetSessions(userID)
+	if err != nil {
+		return fmt.Errorf("password service error change: %w", err)
+	}
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			continue
+		}
+		err = p.authService.RevokeSession(session.ID, userID)
+		if err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return fmt.Errorf("password service error change: %w", err)
+		}
+	}
+	return nil
+}
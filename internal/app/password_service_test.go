@@ -0,0 +1,327 @@
+package app
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/upper/db/v4"
+	"strings"
+	"testing"
+	"time"
+	"trainee/config"
+	smocks "trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	rmocks "trainee/internal/infra/database/mock"
+	"trainee/internal/infra/mail"
+	mmocks "trainee/internal/infra/mail/mock"
+)
+
+func Test_passwordService_Forgot(t *testing.T) {
+	user := domain.User{ID: 2, Email: "user@email.com"}
+
+	t.Run("unknown email sends nothing", func(t *testing.T) {
+		us := smocks.NewUserService(t)
+		us.On("FindByEmail", "nobody@email.com").Return(domain.User{}, db.ErrNoMoreRows).Times(1)
+		p := NewPasswordService(rmocks.NewUserTokenRepo(t), us, smocks.NewAuthService(t), smocks.NewAuthEventService(t), smocks.NewLockoutService(t), mmocks.NewMailer(t), config.Configuration{})
+
+		assert.NoError(t, p.Forgot("nobody@email.com"))
+	})
+
+	t.Run("mails the token and stores its hash", func(t *testing.T) {
+		var saved domain.UserToken
+		var sent mail.Message
+
+		us := smocks.NewUserService(t)
+		us.On("FindByEmail", user.Email).Return(user, nil).Times(1)
+		tr := rmocks.NewUserTokenRepo(t)
+		tr.On("Save", mock.AnythingOfType("domain.UserToken")).
+			Run(func(args mock.Arguments) { saved = args.Get(0).(domain.UserToken) }).
+			Return(domain.UserToken{ID: 1}, nil).Times(1)
+		m := mmocks.NewMailer(t)
+		m.On("Send", mock.AnythingOfType("mail.Message")).
+			Run(func(args mock.Arguments) { sent = args.Get(0).(mail.Message) }).
+			Return(nil).Times(1)
+		p := NewPasswordService(tr, us, smocks.NewAuthService(t), smocks.NewAuthEventService(t), smocks.NewLockoutService(t), m, config.Configuration{PasswordResetURL: "http://app/reset-password"})
+
+		assert.NoError(t, p.Forgot(user.Email))
+
+		assert.Equal(t, user.Email, sent.To)
+		i := strings.Index(sent.Body, "http://app/reset-password?token=")
+		assert.True(t, i >= 0)
+		plain := strings.Fields(sent.Body[i+len("http://app/reset-password?token="):])[0]
+		assert.Equal(t, hashUserToken(plain), saved.Hash)
+		assert.Equal(t, user.ID, saved.UserID)
+		assert.Equal(t, domain.TokenPasswordReset, saved.Purpose)
+		assert.True(t, saved.ExpiresDate.After(time.Now()))
+	})
+}
+
+func Test_passwordService_Reset(t *testing.T) {
+	hash := hashUserToken("plain")
+	valid := domain.UserToken{ID: 1, UserID: 2, Purpose: domain.TokenPasswordReset, Hash: hash, ExpiresDate: time.Now().Add(time.Hour)}
+	used := time.Now()
+	policyErr := &PasswordPolicyError{Violations: []string{"must be at least 8 characters long"}}
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+
+	tests := []struct {
+		name      string
+		tokenRepo func() database.UserTokenRepo
+		us        func() UserService
+		as        func() AuthService
+		wantErr   error
+		event     *domain.AuthEvent
+	}{
+		{
+			"reset ok",
+			func() database.UserTokenRepo {
+				mock := rmocks.NewUserTokenRepo(t)
+				mock.
+					On("FindByHash", domain.TokenPasswordReset, hash).Return(valid, nil).Times(1).
+					On("Use", int64(1)).Return(nil).Times(1).
+					On("UseAll", int64(2), domain.TokenPasswordReset).Return(nil).Times(1)
+				return mock
+			},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(domain.User{ID: 2}, nil).Times(1).
+					On("CheckPassword", domain.User{ID: 2}, "new-password").Return(nil).Times(1).
+					On("UpdatePassword", int64(2), "new-password").Return(domain.User{ID: 2}, nil).Times(1)
+				return mock
+			},
+			func() AuthService {
+				mock := smocks.NewAuthService(t)
+				mock.On("LogoutAll", int64(2), device).Return(nil).Times(1)
+				return mock
+			},
+			nil,
+			&domain.AuthEvent{UserID: 2, Type: domain.AuthEventPasswordChange, Outcome: domain.AuthSuccess, Detail: "reset", IP: device.IP, UserAgent: device.UserAgent},
+		},
+		{
+			"unknown token",
+			func() database.UserTokenRepo {
+				mock := rmocks.NewUserTokenRepo(t)
+				mock.On("FindByHash", domain.TokenPasswordReset, hash).Return(domain.UserToken{}, db.ErrNoMoreRows).Times(1)
+				return mock
+			},
+			func() UserService { return smocks.NewUserService(t) },
+			func() AuthService { return smocks.NewAuthService(t) },
+			ErrInvalidResetToken,
+			nil,
+		},
+		{
+			"expired token",
+			func() database.UserTokenRepo {
+				expired := valid
+				expired.ExpiresDate = time.Now().Add(-time.Minute)
+				mock := rmocks.NewUserTokenRepo(t)
+				mock.On("FindByHash", domain.TokenPasswordReset, hash).Return(expired, nil).Times(1)
+				return mock
+			},
+			func() UserService { return smocks.NewUserService(t) },
+			func() AuthService { return smocks.NewAuthService(t) },
+			ErrInvalidResetToken,
+			nil,
+		},
+		{
+			"used token",
+			func() database.UserTokenRepo {
+				usedToken := valid
+				usedToken.UsedDate = &used
+				mock := rmocks.NewUserTokenRepo(t)
+				mock.On("FindByHash", domain.TokenPasswordReset, hash).Return(usedToken, nil).Times(1)
+				return mock
+			},
+			func() UserService { return smocks.NewUserService(t) },
+			func() AuthService { return smocks.NewAuthService(t) },
+			ErrInvalidResetToken,
+			nil,
+		},
+		{
+			"password breaks the policy keeps the token",
+			func() database.UserTokenRepo {
+				mock := rmocks.NewUserTokenRepo(t)
+				mock.On("FindByHash", domain.TokenPasswordReset, hash).Return(valid, nil).Times(1)
+				return mock
+			},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(domain.User{ID: 2}, nil).Times(1).
+					On("CheckPassword", domain.User{ID: 2}, "new-password").Return(policyErr).Times(1)
+				return mock
+			},
+			func() AuthService { return smocks.NewAuthService(t) },
+			policyErr,
+			nil,
+		},
+		{
+			"token used concurrently",
+			func() database.UserTokenRepo {
+				mock := rmocks.NewUserTokenRepo(t)
+				mock.
+					On("FindByHash", domain.TokenPasswordReset, hash).Return(valid, nil).Times(1).
+					On("Use", int64(1)).Return(database.ErrTokenUsed).Times(1)
+				return mock
+			},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(domain.User{ID: 2}, nil).Times(1).
+					On("CheckPassword", domain.User{ID: 2}, "new-password").Return(nil).Times(1)
+				return mock
+			},
+			func() AuthService { return smocks.NewAuthService(t) },
+			ErrInvalidResetToken,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPasswordService(tt.tokenRepo(), tt.us(), tt.as(), recordsEvent(t, tt.event), smocks.NewLockoutService(t), mmocks.NewMailer(t), config.Configuration{})
+			err := p.Reset("plain", "new-password", device)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_passwordService_Change(t *testing.T) {
+	user := domain.User{ID: 2, Email: "user@example.com", Password: "hash"}
+	sessions := []domain.Session{{ID: "current"}, {ID: "other"}}
+	policyErr := &PasswordPolicyError{Violations: []string{"must not contain your name"}}
+	device := domain.Device{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	wrongPassword := &domain.AuthEvent{UserID: 2, Type: domain.AuthEventPasswordChange, Outcome: domain.AuthFailure, Detail: "wrong current password", IP: device.IP, UserAgent: device.UserAgent}
+	locked := &LockedError{RetryAfter: time.Minute}
+	notLocked := func() LockoutService {
+		mock := smocks.NewLockoutService(t)
+		mock.On("Check", user.Email, device.IP).Return(nil).Times(1)
+		return mock
+	}
+
+	tests := []struct {
+		name    string
+		us      func() UserService
+		as      func() AuthService
+		ls      func() LockoutService
+		wantErr error
+		event   *domain.AuthEvent
+	}{
+		{
+			"change ok revokes the other sessions",
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(user, nil).Times(1).
+					On("VerifyPassword", user, "old-password").Return(true).Times(1).
+					On("UpdatePassword", int64(2), "new-password").Return(user, nil).Times(1)
+				return mock
+			},
+			func() AuthService {
+				mock := smocks.NewAuthService(t)
+				mock.
+					On("GetSessions", int64(2)).Return(sessions, nil).Times(1).
+					On("RevokeSession", "other", int64(2)).Return(nil).Times(1)
+				return mock
+			},
+			notLocked,
+			nil,
+			&domain.AuthEvent{UserID: 2, Type: domain.AuthEventPasswordChange, Outcome: domain.AuthSuccess, IP: device.IP, UserAgent: device.UserAgent},
+		},
+		{
+			"wrong current password",
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(user, nil).Times(1).
+					On("VerifyPassword", user, "old-password").Return(false).Times(1)
+				return mock
+			},
+			func() AuthService { return smocks.NewAuthService(t) },
+			func() LockoutService {
+				mock := smocks.NewLockoutService(t)
+				mock.
+					On("Check", user.Email, device.IP).Return(nil).Times(1).
+					On("Fail", user.Email, device.IP).Return(nil).Times(1)
+				return mock
+			},
+			ErrWrongPassword,
+			wrongPassword,
+		},
+		{
+			"wrong current password that locks",
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(user, nil).Times(1).
+					On("VerifyPassword", user, "old-password").Return(false).Times(1)
+				return mock
+			},
+			func() AuthService { return smocks.NewAuthService(t) },
+			func() LockoutService {
+				mock := smocks.NewLockoutService(t)
+				mock.
+					On("Check", user.Email, device.IP).Return(nil).Times(1).
+					On("Fail", user.Email, device.IP).Return(locked).Times(1)
+				return mock
+			},
+			locked,
+			wrongPassword,
+		},
+		{
+			"locked account isn't checked",
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("FindByID", int64(2)).Return(user, nil).Times(1)
+				return mock
+			},
+			func() AuthService { return smocks.NewAuthService(t) },
+			func() LockoutService {
+				mock := smocks.NewLockoutService(t)
+				mock.On("Check", user.Email, device.IP).Return(locked).Times(1)
+				return mock
+			},
+			locked,
+			nil,
+		},
+		{
+			"new password breaks the policy",
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.
+					On("FindByID", int64(2)).Return(user, nil).Times(1).
+					On("VerifyPassword", user, "old-password").Return(true).Times(1).
+					On("UpdatePassword", int64(2), "new-password").Return(domain.User{}, fmt.Errorf("user service update password: %w", policyErr)).Times(1)
+				return mock
+			},
+			func() AuthService { return smocks.NewAuthService(t) },
+			notLocked,
+			policyErr,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPasswordService(rmocks.NewUserTokenRepo(t), tt.us(), tt.as(), recordsEvent(t, tt.event), tt.ls(), mmocks.NewMailer(t), config.Configuration{})
+			err := p.Change(2, "current", "old-password", "new-password", device)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// recordsEvent expects event to be recorded once, or nothing when it is nil.
+func recordsEvent(t *testing.T, event *domain.AuthEvent) AuthEventService {
+	mock := smocks.NewAuthEventService(t)
+	if event != nil {
+		mock.On("Record", *event).Return().Times(1)
+	}
+	return mock
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"trainee/internal/domain"
+)
+
+// ForbiddenError is returned when the authenticated user is not allowed to act on a resource.
+type ForbiddenError struct {
+	Action string
+}
+
+func (e ForbiddenError) Error() string {
+	return fmt.Sprintf("forbidden: not allowed to %s", e.Action)
+}
+
+//go:generate mockery --dir . --name Policy --output ./mocks
+type Policy interface {
+	CanViewPost(token *jwt.Token, post domain.Post) error
+	CanModifyPost(token *jwt.Token, post domain.Post) error
+	CanUpdateComment(token *jwt.Token, comment domain.Comment) error
+	CanDeleteComment(token *jwt.Token, comment domain.Comment) error
+}
+
+type policy struct{}
+
+func NewPolicy() Policy {
+	return policy{}
+}
+
+// CanViewPost lets everyone see published posts, the other ones only their author.
+func (p policy) CanViewPost(token *jwt.Token, post domain.Post) error {
+	claims := token.Claims.(*JwtTokenClaim)
+	if post.Status != domain.PostStatusPublished && post.UserID != claims.ID {
+		return ForbiddenError{Action: "view this post"}
+	}
+	return nil
+}
+
+func (p policy) CanModifyPost(token *jwt.Token, post domain.Post) error {
+	claims := token.Claims.(*JwtTokenClaim)
+	if post.UserID != claims.ID {
+		return ForbiddenError{Action: "modify this post"}
+	}
+	return nil
+}
+
+func (p policy) CanUpdateComment(token *jwt.Token, comment domain.Comment) error {
+	claims := token.Claims.(*JwtTokenClaim)
+	if comment.UserID != claims.ID {
+		return ForbiddenError{Action: "modify this comment"}
+	}
+	return nil
+}
+
+// CanDeleteComment lets roles with PermissionDeleteAnyComment remove any comment, everyone else only their own.
+func (p policy) CanDeleteComment(token *jwt.Token, comment domain.Comment) error {
+	claims := token.Claims.(*JwtTokenClaim)
+	if claims.Role.Can(domain.PermissionDeleteAnyComment) {
+		return nil
+	}
+	if comment.UserID != claims.ID {
+		return ForbiddenError{Action: "delete this comment"}
+	}
+	return nil
+}
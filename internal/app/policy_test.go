@@ -0,0 +1,174 @@
+package app
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"trainee/internal/domain"
+)
+
+func Test_policy_CanModifyPost(t *testing.T) {
+	tests := []struct {
+		name    string
+		post    domain.Post
+		wantErr bool
+	}{
+		{
+			"owner can modify post",
+			domain.Post{ID: 2, UserID: 1},
+			false,
+		},
+		{
+			"another user can not modify post",
+			domain.Post{ID: 2, UserID: 3},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewPolicy().CanModifyPost(token(), tt.post)
+			if tt.wantErr {
+				var forbidden ForbiddenError
+				assert.True(t, errors.As(err, &forbidden))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_policy_CanViewPost(t *testing.T) {
+	tests := []struct {
+		name    string
+		post    domain.Post
+		wantErr bool
+	}{
+		{
+			"anyone can view published post",
+			domain.Post{ID: 2, UserID: 3, Status: domain.PostStatusPublished},
+			false,
+		},
+		{
+			"owner can view draft",
+			domain.Post{ID: 2, UserID: 1, Status: domain.PostStatusDraft},
+			false,
+		},
+		{
+			"another user can not view draft",
+			domain.Post{ID: 2, UserID: 3, Status: domain.PostStatusDraft},
+			true,
+		},
+		{
+			"another user can not view scheduled post",
+			domain.Post{ID: 2, UserID: 3, Status: domain.PostStatusScheduled},
+			true,
+		},
+		{
+			"another user can not view archived post",
+			domain.Post{ID: 2, UserID: 3, Status: domain.PostStatusArchived},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewPolicy().CanViewPost(token(), tt.post)
+			if tt.wantErr {
+				var forbidden ForbiddenError
+				assert.True(t, errors.As(err, &forbidden))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_policy_CanUpdateComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   *jwt.Token
+		comment domain.Comment
+		wantErr bool
+	}{
+		{
+			"owner can update comment",
+			token(),
+			domain.Comment{ID: 2, UserID: 1},
+			false,
+		},
+		{
+			"another user can not update comment",
+			token(),
+			domain.Comment{ID: 2, UserID: 3},
+			true,
+		},
+		{
+			"moderator can not update comment of another user",
+			roleToken(domain.RoleModerator),
+			domain.Comment{ID: 2, UserID: 3},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewPolicy().CanUpdateComment(tt.token, tt.comment)
+			if tt.wantErr {
+				var forbidden ForbiddenError
+				assert.True(t, errors.As(err, &forbidden))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_policy_CanDeleteComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   *jwt.Token
+		comment domain.Comment
+		wantErr bool
+	}{
+		{
+			"owner can delete comment",
+			token(),
+			domain.Comment{ID: 2, UserID: 1},
+			false,
+		},
+		{
+			"another user can not delete comment",
+			token(),
+			domain.Comment{ID: 2, UserID: 3},
+			true,
+		},
+		{
+			"moderator can delete any comment",
+			roleToken(domain.RoleModerator),
+			domain.Comment{ID: 2, UserID: 3},
+			false,
+		},
+		{
+			"admin can delete any comment",
+			roleToken(domain.RoleAdmin),
+			domain.Comment{ID: 2, UserID: 3},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewPolicy().CanDeleteComment(tt.token, tt.comment)
+			if tt.wantErr {
+				var forbidden ForbiddenError
+				assert.True(t, errors.As(err, &forbidden))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func roleToken(role domain.Role) *jwt.Token {
+	t := token()
+	t.Claims.(*JwtTokenClaim).Role = role
+	return t
+}
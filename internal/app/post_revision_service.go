@@ -0,0 +1,117 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/upper/db/v4"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+//go:generate mockery --dir . --name PostRevisionService --output ./mocks
+type PostRevisionService interface {
+	FindRevisions(postID int64, token *jwt.Token) ([]domain.PostRevision, error)
+	Diff(postID int64, from, to int, token *jwt.Token) (domain.PostDiff, error)
+	Restore(postID int64, revision int, token *jwt.Token) (domain.Post, error)
+}
+
+type postRevisionService struct {
+	postRepo     database.PostRepo
+	revisionRepo database.PostRevisionRepo
+	policy       Policy
+}
+
+func NewPostRevisionService(pr database.PostRepo, rr database.PostRevisionRepo, p Policy) PostRevisionService {
+	return postRevisionService{
+		postRepo:     pr,
+		revisionRepo: rr,
+		policy:       p,
+	}
+}
+
+// FindRevisions lists the revisions of a post, the latest first. Only the author of the post sees them.
+func (s postRevisionService) FindRevisions(postID int64, token *jwt.Token) ([]domain.PostRevision, error) {
+	_, err := s.editablePost(postID, token)
+	if err != nil {
+		return nil, fmt.Errorf("post revision service error find revisions: %w", err)
+	}
+	revisions, err := s.revisionRepo.FindRevisions(postID)
+	if err != nil {
+		return nil, fmt.Errorf("post revision service error find revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// Diff compares two revisions of a post as a unified diff of their title and body.
+func (s postRevisionService) Diff(postID int64, from, to int, token *jwt.Token) (domain.PostDiff, error) {
+	_, err := s.editablePost(postID, token)
+	if err != nil {
+		return domain.PostDiff{}, fmt.Errorf("post revision service error diff: %w", err)
+	}
+	fromRev, err := s.revision(postID, from)
+	if err != nil {
+		return domain.PostDiff{}, fmt.Errorf("post revision service error diff: %w", err)
+	}
+	toRev, err := s.revision(postID, to)
+	if err != nil {
+		return domain.PostDiff{}, fmt.Errorf("post revision service error diff: %w", err)
+	}
+	return domain.PostDiff{
+		PostID: postID,
+		From:   from,
+		To:     to,
+		Diff: unifiedDiff(fmt.Sprintf("revision %d", from), fmt.Sprintf("revision %d", to),
+			revisionText(fromRev), revisionText(toRev)),
+	}, nil
+}
+
+// Restore brings back the title and body of a revision as a new revision, the tags and status stay as they are.
+func (s postRevisionService) Restore(postID int64, revision int, token *jwt.Token) (domain.Post, error) {
+	post, err := s.editablePost(postID, token)
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("post revision service error restore: %w", err)
+	}
+	rev, err := s.revision(postID, revision)
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("post revision service error restore: %w", err)
+	}
+	post.Title = rev.Title
+	post.Body = rev.Body
+	post, err = s.postRepo.UpdatePost(post, token.Claims.(*JwtTokenClaim).ID)
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("post revision service error restore: %w", err)
+	}
+	return post, nil
+}
+
+// editablePost finds a post the token holder may edit, the posts they can't see are not found.
+func (s postRevisionService) editablePost(postID int64, token *jwt.Token) (domain.Post, error) {
+	post, err := s.postRepo.GetPost(postID)
+	if err != nil {
+		return domain.Post{}, err
+	}
+	if s.policy.CanViewPost(token, post) != nil {
+		return domain.Post{}, db.ErrNoMoreRows
+	}
+	err = s.policy.CanModifyPost(token, post)
+	if err != nil {
+		return domain.Post{}, err
+	}
+	return post, nil
+}
+
+func (s postRevisionService) revision(postID int64, revision int) (domain.PostRevision, error) {
+	rev, err := s.revisionRepo.GetRevision(postID, revision)
+	if errors.Is(err, db.ErrNoMoreRows) {
+		return domain.PostRevision{}, ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+// revisionText is what a diff compares: the title, an empty line and the body.
+func revisionText(rev domain.PostRevision) string {
+	return rev.Title + "\n\n" + rev.Body
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/upper/db/v4"
+	"testing"
+	"trainee/internal/domain"
+	rmocks "trainee/internal/infra/database/mock"
+)
+
+func Test_postRevisionService_FindRevisions(t *testing.T) {
+	tests := []struct {
+		name    string
+		post    domain.Post
+		wantErr error
+	}{
+		{"author sees revisions", domain.Post{ID: 2, UserID: 1, Status: domain.PostStatusPublished}, nil},
+		{"others can't", domain.Post{ID: 2, UserID: 3, Status: domain.PostStatusPublished}, ForbiddenError{Action: "modify this post"}},
+		{"others' drafts are not found", domain.Post{ID: 2, UserID: 3, Status: domain.PostStatusDraft}, db.ErrNoMoreRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts := rmocks.NewPostRepo(t)
+			posts.On("GetPost", int64(2)).Return(tt.post, nil).Times(1)
+			revisions := rmocks.NewPostRevisionRepo(t)
+			if tt.wantErr == nil {
+				revisions.On("FindRevisions", int64(2)).Return([]domain.PostRevision{{PostID: 2, Revision: 1}}, nil).Times(1)
+			}
+			found, err := NewPostRevisionService(posts, revisions, NewPolicy()).FindRevisions(2, token())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, found, 1)
+		})
+	}
+}
+
+func Test_postRevisionService_Diff(t *testing.T) {
+	posts := rmocks.NewPostRepo(t)
+	posts.On("GetPost", int64(2)).Return(domain.Post{ID: 2, UserID: 1, Status: domain.PostStatusDraft}, nil)
+	revisions := rmocks.NewPostRevisionRepo(t)
+	revisions.On("GetRevision", int64(2), 1).Return(domain.PostRevision{PostID: 2, Revision: 1, Title: "Title", Body: "Body"}, nil)
+	revisions.On("GetRevision", int64(2), 2).Return(domain.PostRevision{PostID: 2, Revision: 2, Title: "Title", Body: "New body"}, nil)
+	revisions.On("GetRevision", int64(2), 9).Return(domain.PostRevision{}, db.ErrNoMoreRows)
+	s := NewPostRevisionService(posts, revisions, NewPolicy())
+
+	diff, err := s.Diff(2, 1, 2, token())
+	require.NoError(t, err)
+	assert.Equal(t, domain.PostDiff{
+		PostID: 2,
+		From:   1,
+		To:     2,
+		Diff:   "--- revision 1\n+++ revision 2\n@@ -1,3 +1,3 @@\n Title\n \n-Body\n+New body\n",
+	}, diff)
+
+	_, err = s.Diff(2, 1, 9, token())
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+}
+
+func Test_postRevisionService_Restore(t *testing.T) {
+	post := domain.Post{ID: 2, UserID: 1, Title: "Title", Body: "New body", Tags: []string{"go"}, Status: domain.PostStatusPublished}
+	restored := post
+	restored.Body = "Body"
+
+	posts := rmocks.NewPostRepo(t)
+	posts.On("GetPost", int64(2)).Return(post, nil)
+	// the restored content is saved as a new revision by the editor, tags and status are kept
+	posts.On("UpdatePost", restored, int64(1)).Return(restored, nil).Times(1)
+	revisions := rmocks.NewPostRevisionRepo(t)
+	revisions.On("GetRevision", int64(2), 1).Return(domain.PostRevision{PostID: 2, Revision: 1, Title: "Title", Body: "Body"}, nil).Times(1)
+	revisions.On("GetRevision", int64(2), 5).Return(domain.PostRevision{}, db.ErrNoMoreRows).Times(1)
+	s := NewPostRevisionService(posts, revisions, NewPolicy())
+
+	got, err := s.Restore(2, 1, token())
+	require.NoError(t, err)
+	assert.Equal(t, restored, got)
+
+	_, err = s.Restore(2, 5, token())
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunPostScheduler publishes the scheduled posts that are due every interval until ctx is done. Every instance can
+// run it, a post gets published by one of them only.
+func RunPostScheduler(ctx context.Context, ps PostService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		published, err := ps.PublishScheduled()
+		if err != nil {
+			log.Print(err)
+		} else if published > 0 {
+			log.Printf("published %d scheduled posts", published)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	smocks "trainee/internal/app/mocks"
+)
+
+func TestRunPostScheduler(t *testing.T) {
+	ps := smocks.NewPostService(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	// the scheduler runs right away and stops once ctx is done
+	ps.On("PublishScheduled").Return(int64(1), nil).Run(func(_ mock.Arguments) { cancel() }).Once()
+
+	done := make(chan struct{})
+	go func() {
+		RunPostScheduler(ctx, ps, time.Hour)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop")
+	}
+}
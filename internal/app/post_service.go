@@ -1,41 +1,76 @@
 package app
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt"
+	"github.com/upper/db/v4"
 	"log"
+	"time"
 	"trainee/internal/domain"
 	"trainee/internal/infra/database"
 	"trainee/internal/infra/http/requests"
 )
 
+const (
+	postsPage    = 20
+	postsMaxPage = 100
+)
+
+var (
+	ErrInvalidCursor   = errors.New("invalid cursor")
+	ErrInvalidSchedule = errors.New("publish time must be in the future")
+)
+
 //go:generate mockery --dir . --name PostService --output ./mocks
 type PostService interface {
 	SavePost(postRequest requests.PostRequest, token *jwt.Token) (domain.Post, error)
-	GetPost(id int64) (domain.Post, error)
-	UpdatePost(postRequest requests.PostRequest, postID int64) (domain.Post, error)
-	DeletePost(id int64) error
+	GetPost(id int64, token *jwt.Token) (domain.Post, error)
+	UpdatePost(postRequest requests.PostRequest, postID int64, token *jwt.Token) (domain.Post, error)
+	DeletePost(id int64, token *jwt.Token) error
 	GetPostsByUser(userID int64) ([]domain.Post, error)
+	ListPosts(filter domain.PostFilter, cursor string) (domain.PostPage, error)
+	PublishPost(id int64, publishAt *time.Time, token *jwt.Token) (domain.Post, error)
+	UnpublishPost(id int64, token *jwt.Token) (domain.Post, error)
+	ArchivePost(id int64, token *jwt.Token) (domain.Post, error)
+	PublishScheduled() (int64, error)
 }
 
 type postService struct {
-	repo database.PostRepo
+	repo   database.PostRepo
+	policy Policy
 }
 
-func NewPostService(repo database.PostRepo) PostService {
+func NewPostService(repo database.PostRepo, p Policy) PostService {
 	return postService{
-		repo: repo,
+		repo:   repo,
+		policy: p,
 	}
 }
 
 func (s postService) SavePost(postRequest requests.PostRequest, token *jwt.Token) (domain.Post, error) {
 	claim := token.Claims.(*JwtTokenClaim)
 	userID := claim.ID
+	tags, err := normalizeTags(postRequest.Tags)
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("service error save post: %w", err)
+	}
 	domainPost := domain.Post{
 		Title:  postRequest.Title,
 		Body:   postRequest.Body,
+		Tags:   tags,
 		UserID: userID,
 	}
+	status := domain.PostStatus(postRequest.Status)
+	if status == "" {
+		status = domain.PostStatusPublished
+	}
+	err = setPostStatus(&domainPost, status, postRequest.PublishAt, time.Now())
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("service error save post: %w", err)
+	}
 	post, err := s.repo.SavePost(domainPost)
 	if err != nil {
 		return domain.Post{}, fmt.Errorf("service error save post: %w", err)
@@ -43,24 +78,43 @@ func (s postService) SavePost(postRequest requests.PostRequest, token *jwt.Token
 	return post, nil
 }
 
-func (s postService) GetPost(id int64) (domain.Post, error) {
+// GetPost finds a post the token holder can see, the posts of others that are not published are not found.
+func (s postService) GetPost(id int64, token *jwt.Token) (domain.Post, error) {
 	post, err := s.repo.GetPost(id)
 	if err != nil {
 		return domain.Post{}, fmt.Errorf("service error get post: %w", err)
 	}
+	if s.policy.CanViewPost(token, post) != nil {
+		return domain.Post{}, fmt.Errorf("service error get post: %w", db.ErrNoMoreRows)
+	}
 	return post, nil
 }
 
-func (s postService) UpdatePost(postRequest requests.PostRequest, postID int64) (domain.Post, error) {
+func (s postService) UpdatePost(postRequest requests.PostRequest, postID int64, token *jwt.Token) (domain.Post, error) {
+	tags, err := normalizeTags(postRequest.Tags)
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("service error update post: %w", err)
+	}
 	post, err := s.repo.GetPost(postID)
 	if err != nil {
 		return domain.Post{}, fmt.Errorf("service error update post: %w", err)
 	}
+	err = s.policy.CanModifyPost(token, post)
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("service error update post: %w", err)
+	}
 
 	post.Body = postRequest.Body
 	post.Title = postRequest.Title
+	post.Tags = tags
+	if postRequest.Status != "" {
+		err = setPostStatus(&post, domain.PostStatus(postRequest.Status), postRequest.PublishAt, time.Now())
+		if err != nil {
+			return domain.Post{}, fmt.Errorf("service error update post: %w", err)
+		}
+	}
 
-	post, err = s.repo.UpdatePost(post)
+	post, err = s.repo.UpdatePost(post, token.Claims.(*JwtTokenClaim).ID)
 	if err != nil {
 		log.Println(err)
 		return domain.Post{}, fmt.Errorf("service error update post: %w", err)
@@ -68,8 +122,12 @@ func (s postService) UpdatePost(postRequest requests.PostRequest, postID int64)
 	return post, nil
 }
 
-func (s postService) DeletePost(id int64) error {
-	_, err := s.repo.GetPost(id)
+func (s postService) DeletePost(id int64, token *jwt.Token) error {
+	post, err := s.repo.GetPost(id)
+	if err != nil {
+		return fmt.Errorf("service error delete post: %w", err)
+	}
+	err = s.policy.CanModifyPost(token, post)
 	if err != nil {
 		return fmt.Errorf("service error delete post: %w", err)
 	}
@@ -87,3 +145,154 @@ func (s postService) GetPostsByUser(userID int64) ([]domain.Post, error) {
 	}
 	return posts, nil
 }
+
+// PublishPost publishes the post now, or schedules it when publishAt is in the future.
+func (s postService) PublishPost(id int64, publishAt *time.Time, token *jwt.Token) (domain.Post, error) {
+	post, err := s.changeStatus(id, domain.PostStatusPublished, publishAt, token)
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("service error publish post: %w", err)
+	}
+	return post, nil
+}
+
+// UnpublishPost turns the post back into a draft, a scheduled post is not published anymore.
+func (s postService) UnpublishPost(id int64, token *jwt.Token) (domain.Post, error) {
+	post, err := s.changeStatus(id, domain.PostStatusDraft, nil, token)
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("service error unpublish post: %w", err)
+	}
+	return post, nil
+}
+
+// ArchivePost takes the post out of the feed, only its author sees it afterwards.
+func (s postService) ArchivePost(id int64, token *jwt.Token) (domain.Post, error) {
+	post, err := s.changeStatus(id, domain.PostStatusArchived, nil, token)
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("service error archive post: %w", err)
+	}
+	return post, nil
+}
+
+// PublishScheduled publishes the scheduled posts that are due and tells how many there were.
+func (s postService) PublishScheduled() (int64, error) {
+	published, err := s.repo.PublishDue(time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("service error publish scheduled posts: %w", err)
+	}
+	return published, nil
+}
+
+func (s postService) changeStatus(id int64, status domain.PostStatus, publishAt *time.Time, token *jwt.Token) (domain.Post, error) {
+	post, err := s.repo.GetPost(id)
+	if err != nil {
+		return domain.Post{}, err
+	}
+	err = s.policy.CanModifyPost(token, post)
+	if err != nil {
+		return domain.Post{}, err
+	}
+	err = setPostStatus(&post, status, publishAt, time.Now())
+	if err != nil {
+		return domain.Post{}, err
+	}
+	err = s.repo.UpdateStatus(post)
+	if err != nil {
+		return domain.Post{}, err
+	}
+	return post, nil
+}
+
+// setPostStatus moves the post to the status. Publishing at a time in the future schedules the post, scheduling
+// takes such a time. A post keeps the date it was first published on while it stays published or gets archived.
+// The published_date column has no time zone, so the date is always kept in UTC like PublishScheduled compares it.
+func setPostStatus(post *domain.Post, status domain.PostStatus, publishAt *time.Time, now time.Time) error {
+	now = now.UTC()
+	if status == domain.PostStatusPublished && publishAt != nil && publishAt.After(now) {
+		status = domain.PostStatusScheduled
+	}
+	switch status {
+	case domain.PostStatusScheduled:
+		if publishAt == nil || !publishAt.After(now) {
+			return ErrInvalidSchedule
+		}
+		at := publishAt.UTC()
+		post.PublishedDate = &at
+	case domain.PostStatusPublished:
+		if post.Status != domain.PostStatusPublished && post.Status != domain.PostStatusArchived || post.PublishedDate == nil {
+			post.PublishedDate = &now
+		}
+	case domain.PostStatusDraft:
+		post.PublishedDate = nil
+	}
+	post.Status = status
+	return nil
+}
+
+// ListPosts gives a page of the feed, cursor is the NextCursor of the previous page and empty for the first one.
+func (s postService) ListPosts(filter domain.PostFilter, cursor string) (domain.PostPage, error) {
+	if filter.Sort == "" {
+		filter.Sort = domain.PostSortCreated
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = postsPage
+	} else if filter.Limit > postsMaxPage {
+		filter.Limit = postsMaxPage
+	}
+	tags, err := normalizeTags(filter.Tags)
+	if err != nil {
+		return domain.PostPage{}, fmt.Errorf("service error list posts: %w", err)
+	}
+	filter.Tags = tags
+	if cursor != "" {
+		after, err := decodePostCursor(cursor, filter)
+		if err != nil {
+			return domain.PostPage{}, fmt.Errorf("service error list posts: %w", err)
+		}
+		filter.After = &after
+	}
+
+	// one post more than asked for tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	posts, total, err := s.repo.FindPosts(filter)
+	if err != nil {
+		return domain.PostPage{}, fmt.Errorf("service error list posts: %w", err)
+	}
+	page := domain.PostPage{Posts: posts, Total: total}
+	if len(posts) > limit {
+		page.Posts = posts[:limit]
+		page.NextCursor = encodePostCursor(filter, page.Posts[limit-1])
+	}
+	return page, nil
+}
+
+// postCursor is what a feed cursor holds, it only continues a feed of the same sort and order.
+type postCursor struct {
+	Sort      domain.PostSort `json:"s"`
+	Ascending bool            `json:"a,omitempty"`
+	Date      time.Time       `json:"d"`
+	ID        int64           `json:"id"`
+}
+
+func encodePostCursor(filter domain.PostFilter, last domain.Post) string {
+	date := last.CreatedDate
+	if filter.Sort == domain.PostSortUpdated {
+		date = last.UpdatedDate
+	}
+	// marshalling a struct of these types can't fail
+	c, _ := json.Marshal(postCursor{Sort: filter.Sort, Ascending: filter.Ascending, Date: date, ID: last.ID})
+	return base64.RawURLEncoding.EncodeToString(c)
+}
+
+func decodePostCursor(cursor string, filter domain.PostFilter) (domain.PostCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return domain.PostCursor{}, ErrInvalidCursor
+	}
+	var c postCursor
+	err = json.Unmarshal(raw, &c)
+	if err != nil || c.ID <= 0 || c.Sort != filter.Sort || c.Ascending != filter.Ascending {
+		return domain.PostCursor{}, ErrInvalidCursor
+	}
+	return domain.PostCursor{Date: c.Date, ID: c.ID}, nil
+}
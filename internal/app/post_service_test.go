@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
@@ -14,7 +15,7 @@ import (
 )
 
 func token() *jwt.Token {
-	exp := time.Now().Add(time.Hour * access).Unix()
+	exp := time.Now().Add(time.Hour * 2).Unix()
 	claimsAccess := &JwtTokenClaim{
 		Name: "Name",
 		ID:   int64(1),
@@ -26,6 +27,17 @@ func token() *jwt.Token {
 	return tokenReturn
 }
 
+// publishedNow matches the post published when it got saved.
+func publishedNow(post domain.Post) interface{} {
+	return mock.MatchedBy(func(p domain.Post) bool {
+		if p.Status != domain.PostStatusPublished || p.PublishedDate == nil || time.Since(*p.PublishedDate) > time.Minute {
+			return false
+		}
+		p.Status, p.PublishedDate = post.Status, post.PublishedDate
+		return assert.ObjectsAreEqual(post, p)
+	})
+}
+
 func Test_postService_SavePost(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -50,7 +62,7 @@ func Test_postService_SavePost(t *testing.T) {
 			func(post domain.Post) database.PostRepo {
 				mock := mocks.NewPostRepo(t)
 				mock.
-					On("SavePost", post).
+					On("SavePost", publishedNow(post)).
 					Return(post, nil)
 				return mock
 			},
@@ -76,7 +88,7 @@ func Test_postService_SavePost(t *testing.T) {
 			func(post domain.Post) database.PostRepo {
 				mock := mocks.NewPostRepo(t)
 				mock.
-					On("SavePost", post).
+					On("SavePost", publishedNow(post)).
 					Return(domain.Post{}, errors.New("error")).Times(1)
 				return mock
 			},
@@ -90,7 +102,7 @@ func Test_postService_SavePost(t *testing.T) {
 			s := postService{
 				repo: tt.repoConstructor(tt.post),
 			}
-			post, err := NewPostService(s.repo).SavePost(tt.postRequest, tt.token)
+			post, err := NewPostService(s.repo, NewPolicy()).SavePost(tt.postRequest, tt.token)
 			if tt.wantErr {
 				assert.Error(t, err)
 				require.Equal(t, post, tt.want)
@@ -117,14 +129,45 @@ func Test_postService_GetPost(t *testing.T) {
 				mock := mocks.NewPostRepo(t)
 				mock.
 					On("GetPost", id).
-					Return(domain.Post{ID: id}, nil)
+					Return(domain.Post{ID: id, Status: domain.PostStatusPublished}, nil)
 				return mock
 			},
 			domain.Post{
-				ID: 2,
+				ID:     2,
+				Status: domain.PostStatusPublished,
 			},
 			false,
 		},
+		{
+			"Success get own draft",
+			2,
+			func(id int64) database.PostRepo {
+				mock := mocks.NewPostRepo(t)
+				mock.
+					On("GetPost", id).
+					Return(domain.Post{ID: id, UserID: 1, Status: domain.PostStatusDraft}, nil)
+				return mock
+			},
+			domain.Post{
+				ID:     2,
+				UserID: 1,
+				Status: domain.PostStatusDraft,
+			},
+			false,
+		},
+		{
+			"Error get draft of another user",
+			2,
+			func(id int64) database.PostRepo {
+				mock := mocks.NewPostRepo(t)
+				mock.
+					On("GetPost", id).
+					Return(domain.Post{ID: id, UserID: 3, Status: domain.PostStatusDraft}, nil)
+				return mock
+			},
+			domain.Post{},
+			true,
+		},
 		{
 			"Error get post",
 			2,
@@ -144,7 +187,7 @@ func Test_postService_GetPost(t *testing.T) {
 			s := postService{
 				repo: tt.repoConstructor(tt.id),
 			}
-			post, err := NewPostService(s.repo).GetPost(tt.id)
+			post, err := NewPostService(s.repo, NewPolicy()).GetPost(tt.id, token())
 			if tt.wantErr {
 				assert.Error(t, err)
 				require.Equal(t, post, tt.want)
@@ -176,19 +219,21 @@ func Test_postService_UpdatePost(t *testing.T) {
 				mock := mocks.NewPostRepo(t)
 				mock.
 					On("GetPost", id).
-					Return(domain.Post{ID: id}, nil).
-					On("UpdatePost", post).
+					Return(domain.Post{ID: id, UserID: 1}, nil).
+					On("UpdatePost", post, int64(1)).
 					Return(domain.Post{
-						ID:    2,
-						Title: post.Title,
-						Body:  post.Body,
+						ID:     2,
+						UserID: 1,
+						Title:  post.Title,
+						Body:   post.Body,
 					}, nil)
 				return mock
 			},
 			domain.Post{
-				ID:    2,
-				Title: "Title",
-				Body:  "Body",
+				ID:     2,
+				UserID: 1,
+				Title:  "Title",
+				Body:   "Body",
 			},
 			false,
 		},
@@ -209,6 +254,23 @@ func Test_postService_UpdatePost(t *testing.T) {
 			domain.Post{},
 			true,
 		},
+		{
+			"Error update post of another user",
+			requests.PostRequest{
+				Title: "Title",
+				Body:  "Body",
+			},
+			2,
+			func(post domain.Post, id int64) database.PostRepo {
+				mock := mocks.NewPostRepo(t)
+				mock.
+					On("GetPost", id).
+					Return(domain.Post{ID: id, UserID: 3}, nil)
+				return mock
+			},
+			domain.Post{},
+			true,
+		},
 		{
 			"Error update post UpdatePost repo",
 			requests.PostRequest{
@@ -220,8 +282,8 @@ func Test_postService_UpdatePost(t *testing.T) {
 				mock := mocks.NewPostRepo(t)
 				mock.
 					On("GetPost", id).
-					Return(domain.Post{ID: id}, nil).
-					On("UpdatePost", post).
+					Return(domain.Post{ID: id, UserID: 1}, nil).
+					On("UpdatePost", post, int64(1)).
 					Return(domain.Post{}, errors.New("post repository update post"))
 				return mock
 			},
@@ -232,14 +294,15 @@ func Test_postService_UpdatePost(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			post := domain.Post{
-				ID:    tt.postID,
-				Title: tt.postRequest.Title,
-				Body:  tt.postRequest.Body,
+				ID:     tt.postID,
+				UserID: 1,
+				Title:  tt.postRequest.Title,
+				Body:   tt.postRequest.Body,
 			}
 			s := postService{
 				repo: tt.repoConstructor(post, tt.postID),
 			}
-			post, err := NewPostService(s.repo).UpdatePost(tt.postRequest, tt.postID)
+			post, err := NewPostService(s.repo, NewPolicy()).UpdatePost(tt.postRequest, tt.postID, token())
 			if tt.wantErr {
 				assert.Error(t, err)
 				require.Equal(t, post, tt.want)
@@ -265,7 +328,7 @@ func Test_postService_DeletePost(t *testing.T) {
 				mock := mocks.NewPostRepo(t)
 				mock.
 					On("GetPost", id).
-					Return(domain.Post{}, nil).
+					Return(domain.Post{ID: id, UserID: 1}, nil).
 					On("DeletePost", id).
 					Return(nil)
 				return mock
@@ -284,6 +347,18 @@ func Test_postService_DeletePost(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"Error delete post of another user",
+			2,
+			func(id int64) database.PostRepo {
+				mock := mocks.NewPostRepo(t)
+				mock.
+					On("GetPost", id).
+					Return(domain.Post{ID: id, UserID: 3}, nil)
+				return mock
+			},
+			true,
+		},
 		{
 			"Error delete post DeletePost repo",
 			2,
@@ -291,7 +366,7 @@ func Test_postService_DeletePost(t *testing.T) {
 				mock := mocks.NewPostRepo(t)
 				mock.
 					On("GetPost", id).
-					Return(domain.Post{}, nil).
+					Return(domain.Post{ID: id, UserID: 1}, nil).
 					On("DeletePost", id).
 					Return(errors.New("post repository delete post"))
 				return mock
@@ -304,7 +379,7 @@ func Test_postService_DeletePost(t *testing.T) {
 			s := postService{
 				repo: tt.repoConstructor(tt.postID),
 			}
-			err := NewPostService(s.repo).DeletePost(tt.postID)
+			err := NewPostService(s.repo, NewPolicy()).DeletePost(tt.postID, token())
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -362,7 +437,7 @@ func Test_postService_GetPostsByUser(t *testing.T) {
 			s := postService{
 				repo: tt.repoConstructor(tt.userID),
 			}
-			posts, err := NewPostService(s.repo).GetPostsByUser(tt.userID)
+			posts, err := NewPostService(s.repo, NewPolicy()).GetPostsByUser(tt.userID)
 			if tt.wantErr {
 				assert.Error(t, err)
 				require.Equal(t, posts, tt.want)
@@ -373,3 +448,144 @@ func Test_postService_GetPostsByUser(t *testing.T) {
 		})
 	}
 }
+
+func Test_postService_ListPosts(t *testing.T) {
+	day := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	feed := []domain.Post{
+		{ID: 3, CreatedDate: day.Add(2 * time.Hour), UpdatedDate: day},
+		{ID: 2, CreatedDate: day.Add(time.Hour), UpdatedDate: day.Add(3 * time.Hour)},
+		{ID: 1, CreatedDate: day.Add(time.Hour), UpdatedDate: day},
+	}
+
+	repo := mocks.NewPostRepo(t)
+	// the first page asks for one post more than it shows, to know there is a next one
+	repo.On("FindPosts", domain.PostFilter{Sort: domain.PostSortCreated, Limit: 3}).Return(feed, uint64(5), nil).Times(1)
+	s := NewPostService(repo, NewPolicy())
+	page, err := s.ListPosts(domain.PostFilter{Limit: 2}, "")
+	require.NoError(t, err)
+	assert.Equal(t, feed[:2], page.Posts)
+	assert.Equal(t, uint64(5), page.Total)
+	require.NotEmpty(t, page.NextCursor)
+
+	// the cursor continues after the last post shown, by its sort date and id
+	after := &domain.PostCursor{Date: feed[1].CreatedDate, ID: 2}
+	repo.On("FindPosts", domain.PostFilter{Sort: domain.PostSortCreated, After: after, Limit: 3}).Return(feed[2:], uint64(5), nil).Times(1)
+	page, err = s.ListPosts(domain.PostFilter{Limit: 2}, page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, feed[2:], page.Posts)
+	assert.Empty(t, page.NextCursor)
+
+	// a cursor only continues the feed it came from
+	_, err = s.ListPosts(domain.PostFilter{Sort: domain.PostSortUpdated, Limit: 2}, encodePostCursor(domain.PostFilter{Sort: domain.PostSortCreated}, feed[1]))
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = s.ListPosts(domain.PostFilter{Limit: 2}, "not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	repo.On("FindPosts", domain.PostFilter{Sort: domain.PostSortCreated, Limit: postsMaxPage + 1}).Return(nil, uint64(0), errors.New("upper: collection does not exist")).Times(1)
+	_, err = s.ListPosts(domain.PostFilter{Limit: 1000}, "")
+	assert.Error(t, err)
+}
+
+func Test_postService_SavePostTags(t *testing.T) {
+	repo := mocks.NewPostRepo(t)
+	post := domain.Post{UserID: 1, Title: "Title", Body: "Body", Tags: []string{"go", "web-dev"}}
+	repo.On("SavePost", publishedNow(post)).Return(post, nil).Times(1)
+	s := NewPostService(repo, NewPolicy())
+
+	saved, err := s.SavePost(requests.PostRequest{Title: "Title", Body: "Body", Tags: []string{"Web Dev", "#Go", "go"}}, token())
+	require.NoError(t, err)
+	assert.Equal(t, post.Tags, saved.Tags)
+
+	_, err = s.SavePost(requests.PostRequest{Title: "Title", Body: "Body", Tags: []string{"?"}}, token())
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
+
+func Test_setPostStatus(t *testing.T) {
+	now := time.Date(2022, 11, 19, 10, 0, 0, 0, time.UTC)
+	earlier, later := now.Add(-time.Hour), now.Add(time.Hour)
+	tests := []struct {
+		name      string
+		post      domain.Post
+		status    domain.PostStatus
+		publishAt *time.Time
+		want      domain.Post
+		wantErr   error
+	}{
+		{"publish draft now", domain.Post{Status: domain.PostStatusDraft}, domain.PostStatusPublished, nil,
+			domain.Post{Status: domain.PostStatusPublished, PublishedDate: &now}, nil},
+		{"publish at a later time schedules", domain.Post{Status: domain.PostStatusDraft}, domain.PostStatusPublished, &later,
+			domain.Post{Status: domain.PostStatusScheduled, PublishedDate: &later}, nil},
+		{"publishing again keeps the date", domain.Post{Status: domain.PostStatusPublished, PublishedDate: &earlier}, domain.PostStatusPublished, nil,
+			domain.Post{Status: domain.PostStatusPublished, PublishedDate: &earlier}, nil},
+		{"republish archived keeps the date", domain.Post{Status: domain.PostStatusArchived, PublishedDate: &earlier}, domain.PostStatusPublished, nil,
+			domain.Post{Status: domain.PostStatusPublished, PublishedDate: &earlier}, nil},
+		{"publish scheduled now", domain.Post{Status: domain.PostStatusScheduled, PublishedDate: &later}, domain.PostStatusPublished, nil,
+			domain.Post{Status: domain.PostStatusPublished, PublishedDate: &now}, nil},
+		{"schedule without time", domain.Post{Status: domain.PostStatusDraft}, domain.PostStatusScheduled, nil,
+			domain.Post{Status: domain.PostStatusDraft}, ErrInvalidSchedule},
+		{"schedule in the past", domain.Post{Status: domain.PostStatusDraft}, domain.PostStatusScheduled, &earlier,
+			domain.Post{Status: domain.PostStatusDraft}, ErrInvalidSchedule},
+		{"unpublish clears the date", domain.Post{Status: domain.PostStatusPublished, PublishedDate: &earlier}, domain.PostStatusDraft, nil,
+			domain.Post{Status: domain.PostStatusDraft}, nil},
+		{"archive keeps the date", domain.Post{Status: domain.PostStatusPublished, PublishedDate: &earlier}, domain.PostStatusArchived, nil,
+			domain.Post{Status: domain.PostStatusArchived, PublishedDate: &earlier}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := tt.post
+			err := setPostStatus(&post, tt.status, tt.publishAt, now)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, post)
+		})
+	}
+}
+
+func Test_setPostStatusUTC(t *testing.T) {
+	local := time.FixedZone("EET", 2*60*60)
+	now := time.Date(2022, 11, 19, 12, 0, 0, 0, local)
+	later := time.Date(2022, 11, 19, 14, 0, 0, 0, local)
+
+	// a column without time zone keeps the wall clock, local times would publish off by the offset
+	scheduled := domain.Post{Status: domain.PostStatusDraft}
+	require.NoError(t, setPostStatus(&scheduled, domain.PostStatusScheduled, &later, now))
+	assert.Equal(t, time.Date(2022, 11, 19, 12, 0, 0, 0, time.UTC), *scheduled.PublishedDate)
+	assert.Equal(t, time.UTC, scheduled.PublishedDate.Location())
+
+	published := domain.Post{Status: domain.PostStatusDraft}
+	require.NoError(t, setPostStatus(&published, domain.PostStatusPublished, nil, now))
+	assert.Equal(t, time.Date(2022, 11, 19, 10, 0, 0, 0, time.UTC), *published.PublishedDate)
+	assert.Equal(t, time.UTC, published.PublishedDate.Location())
+}
+
+func Test_postService_PublishPost(t *testing.T) {
+	later := time.Now().Add(time.Hour).UTC()
+	repo := mocks.NewPostRepo(t)
+	repo.On("GetPost", int64(2)).Return(domain.Post{ID: 2, UserID: 1, Status: domain.PostStatusDraft}, nil).Times(1)
+	scheduled := domain.Post{ID: 2, UserID: 1, Status: domain.PostStatusScheduled, PublishedDate: &later}
+	repo.On("UpdateStatus", scheduled).Return(nil).Times(1)
+	repo.On("GetPost", int64(3)).Return(domain.Post{ID: 3, UserID: 3, Status: domain.PostStatusDraft}, nil).Times(1)
+	s := NewPostService(repo, NewPolicy())
+
+	post, err := s.PublishPost(2, &later, token())
+	require.NoError(t, err)
+	assert.Equal(t, domain.PostStatusScheduled, post.Status)
+
+	_, err = s.PublishPost(3, nil, token())
+	var forbidden ForbiddenError
+	assert.ErrorAs(t, err, &forbidden)
+}
+
+func Test_postService_PublishScheduled(t *testing.T) {
+	repo := mocks.NewPostRepo(t)
+	utc := mock.MatchedBy(func(now time.Time) bool { return now.Location() == time.UTC })
+	repo.On("PublishDue", utc).Return(int64(2), nil).Once()
+	repo.On("PublishDue", mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("upper: collection does not exist")).Once()
+	s := NewPostService(repo, NewPolicy())
+
+	published, err := s.PublishScheduled()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), published)
+
+	_, err = s.PublishScheduled()
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+)
+
+const (
+	searchPage    = 20
+	searchMaxPage = 100
+)
+
+// hitMarks turns the hit markers of the escaped snippets into mark elements
+var hitMarks = strings.NewReplacer(database.SearchHitStart, "<mark>", database.SearchHitStop, "</mark>")
+
+//go:generate mockery --dir . --name SearchService --output ./mocks
+type SearchService interface {
+	Search(query domain.SearchQuery) ([]domain.SearchResult, error)
+}
+
+type searchService struct {
+	searchRepo database.SearchRepo
+}
+
+func NewSearchService(sr database.SearchRepo) SearchService {
+	return searchService{
+		searchRepo: sr,
+	}
+}
+
+// Search finds posts and comments, the snippets are HTML with the hits in mark elements and everything else escaped.
+func (s searchService) Search(query domain.SearchQuery) ([]domain.SearchResult, error) {
+	if query.Limit <= 0 {
+		query.Limit = searchPage
+	} else if query.Limit > searchMaxPage {
+		query.Limit = searchMaxPage
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	results, err := s.searchRepo.Search(query)
+	if err != nil {
+		return nil, fmt.Errorf("search service error search: %w", err)
+	}
+	for i := range results {
+		results[i].Snippet = hitMarks.Replace(html.EscapeString(results[i].Snippet))
+	}
+	return results, nil
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"testing"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	rmocks "trainee/internal/infra/database/mock"
+)
+
+func Test_searchService_Search(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"default page", 0, searchPage},
+		{"page size kept", 10, 10},
+		{"page size capped", 1000, searchMaxPage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := rmocks.NewSearchRepo(t)
+			repo.On("Search", domain.SearchQuery{Query: "lorem", Limit: tt.want}).Return([]domain.SearchResult{{ID: 1}}, nil).Times(1)
+			results, err := NewSearchService(repo).Search(domain.SearchQuery{Query: "lorem", Limit: tt.limit})
+			assert.NoError(t, err)
+			assert.Len(t, results, 1)
+		})
+	}
+}
+
+func Test_searchService_SearchSnippet(t *testing.T) {
+	query := domain.SearchQuery{Query: "lorem", Limit: searchPage}
+	repo := rmocks.NewSearchRepo(t)
+	repo.On("Search", query).Return([]domain.SearchResult{
+		{ID: 1, Snippet: "<b>" + database.SearchHitStart + "lorem" + database.SearchHitStop + "</b> & ipsum"},
+	}, nil).Times(1)
+	results, err := NewSearchService(repo).Search(query)
+	assert.NoError(t, err)
+	// the text of the post is escaped, only the marks are markup
+	assert.Equal(t, "&lt;b&gt;<mark>lorem</mark>&lt;/b&gt; &amp; ipsum", results[0].Snippet)
+}
+
+func Test_searchService_SearchError(t *testing.T) {
+	query := domain.SearchQuery{Query: "lorem", Limit: searchPage}
+	repo := rmocks.NewSearchRepo(t)
+	repo.On("Search", query).Return(nil, db.ErrCollectionDoesNotExist).Times(1)
+	_, err := NewSearchService(repo).Search(query)
+	assert.ErrorIs(t, err, db.ErrCollectionDoesNotExist)
+}
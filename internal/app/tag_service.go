@@ -0,0 +1,120 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/upper/db/v4"
+	"sort"
+	"strings"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	"unicode"
+)
+
+var (
+	ErrInvalidTag     = errors.New("invalid tag")
+	ErrTagNotFound    = errors.New("tag not found")
+	ErrTagExists      = errors.New("tag already exists")
+	ErrTagMergeItself = errors.New("tag can not be merged into itself")
+)
+
+//go:generate mockery --dir . --name TagService --output ./mocks
+type TagService interface {
+	FindTags() ([]domain.Tag, error)
+	RenameTag(id int64, name string) (domain.Tag, error)
+	MergeTags(fromID, intoID int64) (domain.Tag, error)
+}
+
+type tagService struct {
+	tagRepo database.TagRepo
+}
+
+func NewTagService(tr database.TagRepo) TagService {
+	return tagService{
+		tagRepo: tr,
+	}
+}
+
+func (s tagService) FindTags() ([]domain.Tag, error) {
+	tags, err := s.tagRepo.FindTags()
+	if err != nil {
+		return nil, fmt.Errorf("tag service error find tags: %w", err)
+	}
+	return tags, nil
+}
+
+// RenameTag normalizes the new name, a tag can't take the name of another one, they are merged instead.
+func (s tagService) RenameTag(id int64, name string) (domain.Tag, error) {
+	name, err := normalizeTag(name)
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("tag service error rename tag: %w", err)
+	}
+	tag, err := s.tagRepo.RenameTag(id, name)
+	if errors.Is(err, db.ErrNoMoreRows) {
+		return domain.Tag{}, fmt.Errorf("tag service error rename tag: %w", ErrTagNotFound)
+	} else if errors.Is(err, database.ErrTagExists) {
+		return domain.Tag{}, fmt.Errorf("tag service error rename tag: %w", ErrTagExists)
+	} else if err != nil {
+		return domain.Tag{}, fmt.Errorf("tag service error rename tag: %w", err)
+	}
+	return tag, nil
+}
+
+// MergeTags moves the posts of one tag to another and deletes it, the other tag is returned.
+func (s tagService) MergeTags(fromID, intoID int64) (domain.Tag, error) {
+	if fromID == intoID {
+		return domain.Tag{}, fmt.Errorf("tag service error merge tags: %w", ErrTagMergeItself)
+	}
+	tag, err := s.tagRepo.MergeTags(fromID, intoID)
+	if errors.Is(err, db.ErrNoMoreRows) {
+		return domain.Tag{}, fmt.Errorf("tag service error merge tags: %w", ErrTagNotFound)
+	} else if err != nil {
+		return domain.Tag{}, fmt.Errorf("tag service error merge tags: %w", err)
+	}
+	return tag, nil
+}
+
+// normalizeTags normalizes each tag and drops the duplicates, the tags come out sorted.
+func normalizeTags(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		tag, err := normalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// normalizeTag lower cases the tag and joins its words with dashes, leaving out a leading # and anything but
+// letters, digits and the + # . characters, so "#Go Lang", "go_lang" and "go-lang" are the same tag.
+func normalizeTag(name string) (string, error) {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.TrimPrefix(strings.TrimSpace(name), "#") {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("+#.", r):
+			if dash && b.Len() != 0 {
+				b.WriteRune('-')
+			}
+			dash = false
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			dash = true
+		}
+	}
+	tag := strings.Trim(b.String(), ".")
+	if tag == "" {
+		return "", ErrInvalidTag
+	}
+	return tag, nil
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"testing"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	rmocks "trainee/internal/infra/database/mock"
+)
+
+func Test_normalizeTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    string
+		wantErr error
+	}{
+		{"lower cased", "GoLang", "golang", nil},
+		{"words joined by dashes", "  Go   Lang ", "go-lang", nil},
+		{"underscores and dashes", "go__lang--tips", "go-lang-tips", nil},
+		{"leading hash dropped", "#golang", "golang", nil},
+		{"symbols kept", "C++", "c++", nil},
+		{"punctuation dropped", "what?!", "what", nil},
+		{"letters of any script", "Ünïcode", "ünïcode", nil},
+		{"nothing left", " #!? ", "", ErrInvalidTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := normalizeTag(tt.tag)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, tag)
+		})
+	}
+}
+
+func Test_normalizeTags(t *testing.T) {
+	tags, err := normalizeTags([]string{"Web", "#go", "go", "GO"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "web"}, tags)
+
+	tags, err = normalizeTags(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, tags)
+
+	_, err = normalizeTags([]string{"go", "-"})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
+
+func Test_tagService_RenameTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{"renamed", nil, nil},
+		{"unknown tag", db.ErrNoMoreRows, ErrTagNotFound},
+		{"name taken", database.ErrTagExists, ErrTagExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := rmocks.NewTagRepo(t)
+			repo.On("RenameTag", int64(1), "go-lang").Return(domain.Tag{ID: 1, Name: "go-lang"}, wrapTagRepoErr(tt.repoErr)).Times(1)
+			tag, err := NewTagService(repo).RenameTag(1, "Go Lang")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "go-lang", tag.Name)
+		})
+	}
+
+	_, err := NewTagService(rmocks.NewTagRepo(t)).RenameTag(1, "?")
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
+
+func Test_tagService_MergeTags(t *testing.T) {
+	repo := rmocks.NewTagRepo(t)
+	repo.On("MergeTags", int64(2), int64(1)).Return(domain.Tag{ID: 1, Name: "go", PostCount: 5}, nil).Times(1)
+	repo.On("MergeTags", int64(3), int64(1)).Return(domain.Tag{}, wrapTagRepoErr(db.ErrNoMoreRows)).Times(1)
+	s := NewTagService(repo)
+
+	tag, err := s.MergeTags(2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), tag.PostCount)
+
+	_, err = s.MergeTags(3, 1)
+	assert.ErrorIs(t, err, ErrTagNotFound)
+
+	_, err = s.MergeTags(1, 1)
+	assert.ErrorIs(t, err, ErrTagMergeItself)
+}
+
+// wrapTagRepoErr wraps err the way the repositories do
+func wrapTagRepoErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("tag repository: %w", err)
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// the SHA1 vectors of RFC 6238 appendix B, cut to 6 digits
+func Test_totpCode(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		code, err := totpCode(secret, totpCounter(time.Unix(tt.unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, code)
+	}
+}
+
+func Test_validateTOTP(t *testing.T) {
+	secret, err := newTOTPSecret()
+	assert.NoError(t, err)
+	now := time.Now()
+	current := totpCounter(now)
+
+	previous, err := totpCode(secret, current-1)
+	assert.NoError(t, err)
+	counter, ok := validateTOTP(secret, previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, current-1, counter)
+
+	tooOld, err := totpCode(secret, current-2)
+	assert.NoError(t, err)
+	_, ok = validateTOTP(secret, tooOld, now)
+	assert.False(t, ok)
+
+	_, ok = validateTOTP(secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func Test_totpURI(t *testing.T) {
+	u, err := url.Parse(totpURI("trainee", "user@email.com", "SECRET"))
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/trainee:user@email.com", u.Path)
+	assert.Equal(t, "SECRET", u.Query().Get("secret"))
+	assert.Equal(t, "trainee", u.Query().Get("issuer"))
+}
+
+func Test_secretBox(t *testing.T) {
+	box := newSecretBox("key")
+	sealed, err := box.seal("secret")
+	assert.NoError(t, err)
+	assert.NotContains(t, sealed, "secret")
+
+	plain, err := box.open(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", plain)
+
+	_, err = newSecretBox("other key").open(sealed)
+	assert.Error(t, err)
+}
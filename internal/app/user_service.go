@@ -1,8 +1,10 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"time"
 	"trainee/internal/domain"
 	"trainee/internal/infra/database"
 )
@@ -12,27 +14,45 @@ type UserService interface {
 	Save(user domain.User) (domain.User, error)
 	FindByEmail(email string) (domain.User, error)
 	FindByID(id int64) (domain.User, error)
+	UpdateRole(id int64, role domain.Role) (domain.User, error)
+	UpdatePassword(id int64, password string) (domain.User, error)
+	VerifyPassword(user domain.User, password string) bool
+	CheckPassword(user domain.User, password string) error
+	MarkEmailVerified(id int64) (domain.User, error)
+	SetMFA(id int64, secret string, enabledAt *time.Time) error
+	UseMFACounter(id int64, counter int64) error
 	Delete(id int64) error
 }
 
+var ErrInvalidRole = errors.New("invalid role")
+
 type userService struct {
 	userRepo    database.UserRepo
 	passwordGen Generator
+	policy      PasswordPolicy
 }
 
-func NewUserService(ur database.UserRepo, gs Generator) UserService {
+func NewUserService(ur database.UserRepo, gs Generator, pp PasswordPolicy) UserService {
 	return userService{
 		userRepo:    ur,
 		passwordGen: gs,
+		policy:      pp,
 	}
 }
 
 func (u userService) Save(user domain.User) (domain.User, error) {
 	var err error
 
-	user.Password, err = u.passwordGen.GeneratePasswordHash(user.Password)
-	if err != nil {
-		return domain.User{}, fmt.Errorf("user service save user, could not generate hash: %w", err)
+	// users coming from an OAuth provider have no password and can't log in with one
+	if user.Password != "" {
+		err = u.policy.Check(user.Password, user)
+		if err != nil {
+			return domain.User{}, fmt.Errorf("user service save user: %w", err)
+		}
+		user.Password, err = u.passwordGen.GeneratePasswordHash(user.Password)
+		if err != nil {
+			return domain.User{}, fmt.Errorf("user service save user, could not generate hash: %w", err)
+		}
 	}
 
 	saveUser, err := u.userRepo.Save(user)
@@ -60,6 +80,103 @@ func (u userService) FindByID(id int64) (domain.User, error) {
 	return user, nil
 }
 
+func (u userService) UpdateRole(id int64, role domain.Role) (domain.User, error) {
+	if !role.Valid() {
+		return domain.User{}, fmt.Errorf("user service update role: %w", ErrInvalidRole)
+	}
+	user, err := u.userRepo.FindByID(id)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("user service update role: %w", err)
+	}
+	user.Role = role
+	user, err = u.userRepo.Update(user)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("user service update role: %w", err)
+	}
+	return user, nil
+}
+
+func (u userService) UpdatePassword(id int64, password string) (domain.User, error) {
+	user, err := u.userRepo.FindByID(id)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("user service update password: %w", err)
+	}
+	err = u.policy.Check(password, user)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("user service update password: %w", err)
+	}
+	user.Password, err = u.passwordGen.GeneratePasswordHash(password)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("user service update password, could not generate hash: %w", err)
+	}
+	user, err = u.userRepo.Update(user)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("user service update password: %w", err)
+	}
+	return user, nil
+}
+
+// VerifyPassword checks the password against the user's hash. A hash made with an older algorithm or
+// parameters is replaced while the plain password is at hand, failing to do so doesn't fail the check.
+func (u userService) VerifyPassword(user domain.User, password string) bool {
+	if !u.passwordGen.CompareHashAndPassword(user.Password, password) {
+		return false
+	}
+	if u.passwordGen.NeedsRehash(user.Password) {
+		hash, err := u.passwordGen.GeneratePasswordHash(password)
+		if err == nil {
+			user.Password = hash
+			_, err = u.userRepo.Update(user)
+		}
+		if err != nil {
+			log.Printf("user service verify password, could not rehash: %s", err)
+		}
+	}
+	return true
+}
+
+// CheckPassword tells whether the password may be set for the user, without setting it.
+func (u userService) CheckPassword(user domain.User, password string) error {
+	err := u.policy.Check(password, user)
+	if err != nil {
+		return fmt.Errorf("user service check password: %w", err)
+	}
+	return nil
+}
+
+func (u userService) MarkEmailVerified(id int64) (domain.User, error) {
+	user, err := u.userRepo.FindByID(id)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("user service mark email verified: %w", err)
+	}
+	if user.EmailVerified() {
+		return user, nil
+	}
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	user, err = u.userRepo.Update(user)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("user service mark email verified: %w", err)
+	}
+	return user, nil
+}
+
+func (u userService) SetMFA(id int64, secret string, enabledAt *time.Time) error {
+	err := u.userRepo.UpdateMFA(id, secret, enabledAt)
+	if err != nil {
+		return fmt.Errorf("user service set mfa: %w", err)
+	}
+	return nil
+}
+
+func (u userService) UseMFACounter(id int64, counter int64) error {
+	err := u.userRepo.UseMFACounter(id, counter)
+	if err != nil {
+		return fmt.Errorf("user service use mfa counter: %w", err)
+	}
+	return nil
+}
+
 func (u userService) Delete(id int64) error {
 	err := u.userRepo.Delete(id)
 	if err != nil {
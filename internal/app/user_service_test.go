@@ -209,6 +209,44 @@ func Test_userService_Save(t *testing.T) {
 			domain.User{},
 			true,
 		},
+		{
+			"save user password breaks the policy",
+			domain.User{
+				Email:    "user@mail.com",
+				Name:     "user",
+				Password: "user-1234567",
+			},
+			func(user domain.User) database.UserRepo {
+				return repoMocks.NewUserRepo(t)
+			},
+			func(password string) Generator {
+				return smocks.NewGenerator(t)
+			},
+			domain.User{},
+			true,
+		},
+		{
+			"save user without password",
+			domain.User{
+				Email: "user@mail.com",
+				Name:  "user",
+			},
+			func(user domain.User) database.UserRepo {
+				mock := repoMocks.NewUserRepo(t)
+				mock.
+					On("Save", user).
+					Return(user, nil).Times(1)
+				return mock
+			},
+			func(password string) Generator {
+				return smocks.NewGenerator(t)
+			},
+			domain.User{
+				Email: "user@mail.com",
+				Name:  "user",
+			},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -216,7 +254,7 @@ func Test_userService_Save(t *testing.T) {
 				passwordGen: tt.generatorConstructor(tt.user.Password),
 				userRepo:    tt.repoConstructor(tt.user),
 			}
-			user, err := NewUserService(us.userRepo, us.passwordGen).Save(tt.user)
+			user, err := NewUserService(us.userRepo, us.passwordGen, passwordPolicy{}).Save(tt.user)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Equal(t, user, tt.want)
@@ -277,3 +315,210 @@ func Test_userService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_userService_UpdateRole(t *testing.T) {
+	tests := []struct {
+		name            string
+		id              int64
+		role            domain.Role
+		repoConstructor func(id int64, role domain.Role) database.UserRepo
+		want            domain.User
+		wantErr         bool
+	}{
+		{
+			"update role ok",
+			2,
+			domain.RoleModerator,
+			func(id int64, role domain.Role) database.UserRepo {
+				mock := repoMocks.NewUserRepo(t)
+				mock.
+					On("FindByID", id).
+					Return(domain.User{ID: id, Role: domain.RoleUser}, nil).Times(1).
+					On("Update", domain.User{ID: id, Role: role}).
+					Return(domain.User{ID: id, Role: role}, nil).Times(1)
+				return mock
+			},
+			domain.User{ID: 2, Role: domain.RoleModerator},
+			false,
+		},
+		{
+			"update role invalid role",
+			2,
+			domain.Role("owner"),
+			func(id int64, role domain.Role) database.UserRepo {
+				return repoMocks.NewUserRepo(t)
+			},
+			domain.User{},
+			true,
+		},
+		{
+			"update role user not exist",
+			2,
+			domain.RoleAdmin,
+			func(id int64, role domain.Role) database.UserRepo {
+				mock := repoMocks.NewUserRepo(t)
+				mock.
+					On("FindByID", id).
+					Return(domain.User{}, errors.New("upper: no more rows in this result set")).Times(1)
+				return mock
+			},
+			domain.User{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := userService{
+				userRepo: tt.repoConstructor(tt.id, tt.role),
+			}
+			user, err := u.UpdateRole(tt.id, tt.role)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, user, tt.want)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, user, tt.want)
+			}
+		})
+	}
+}
+
+func Test_userService_UpdatePassword(t *testing.T) {
+	tests := []struct {
+		name                 string
+		id                   int64
+		password             string
+		repoConstructor      func(id int64) database.UserRepo
+		generatorConstructor func(password string) Generator
+		wantErr              bool
+	}{
+		{
+			"update password ok",
+			2,
+			"new-password",
+			func(id int64) database.UserRepo {
+				mock := repoMocks.NewUserRepo(t)
+				mock.
+					On("FindByID", id).
+					Return(domain.User{ID: id, Password: "old-hash"}, nil).Times(1).
+					On("Update", domain.User{ID: id, Password: "new-hash"}).
+					Return(domain.User{ID: id, Password: "new-hash"}, nil).Times(1)
+				return mock
+			},
+			func(password string) Generator {
+				mock := smocks.NewGenerator(t)
+				mock.On("GeneratePasswordHash", password).Return("new-hash", nil).Times(1)
+				return mock
+			},
+			false,
+		},
+		{
+			"update password user not exist",
+			2,
+			"new-password",
+			func(id int64) database.UserRepo {
+				mock := repoMocks.NewUserRepo(t)
+				mock.
+					On("FindByID", id).
+					Return(domain.User{}, errors.New("upper: no more rows in this result set")).Times(1)
+				return mock
+			},
+			func(password string) Generator {
+				return smocks.NewGenerator(t)
+			},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUserService(tt.repoConstructor(tt.id), tt.generatorConstructor(tt.password), passwordPolicy{})
+			_, err := u.UpdatePassword(tt.id, tt.password)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_userService_VerifyPassword(t *testing.T) {
+	user := domain.User{ID: 2, Password: "old-hash"}
+	tests := []struct {
+		name                 string
+		password             string
+		repoConstructor      func() database.UserRepo
+		generatorConstructor func() Generator
+		want                 bool
+	}{
+		{
+			"valid current hash",
+			"password",
+			func() database.UserRepo {
+				return repoMocks.NewUserRepo(t)
+			},
+			func() Generator {
+				mock := smocks.NewGenerator(t)
+				mock.On("CompareHashAndPassword", "old-hash", "password").Return(true).Times(1)
+				mock.On("NeedsRehash", "old-hash").Return(false).Times(1)
+				return mock
+			},
+			true,
+		},
+		{
+			"valid outdated hash is replaced",
+			"password",
+			func() database.UserRepo {
+				mock := repoMocks.NewUserRepo(t)
+				mock.On("Update", domain.User{ID: 2, Password: "new-hash"}).
+					Return(domain.User{ID: 2, Password: "new-hash"}, nil).Times(1)
+				return mock
+			},
+			func() Generator {
+				mock := smocks.NewGenerator(t)
+				mock.On("CompareHashAndPassword", "old-hash", "password").Return(true).Times(1)
+				mock.On("NeedsRehash", "old-hash").Return(true).Times(1)
+				mock.On("GeneratePasswordHash", "password").Return("new-hash", nil).Times(1)
+				return mock
+			},
+			true,
+		},
+		{
+			"failed rehash still logs in",
+			"password",
+			func() database.UserRepo {
+				mock := repoMocks.NewUserRepo(t)
+				mock.On("Update", domain.User{ID: 2, Password: "new-hash"}).
+					Return(domain.User{}, errors.New("update error")).Times(1)
+				return mock
+			},
+			func() Generator {
+				mock := smocks.NewGenerator(t)
+				mock.On("CompareHashAndPassword", "old-hash", "password").Return(true).Times(1)
+				mock.On("NeedsRehash", "old-hash").Return(true).Times(1)
+				mock.On("GeneratePasswordHash", "password").Return("new-hash", nil).Times(1)
+				return mock
+			},
+			true,
+		},
+		{
+			"wrong password",
+			"wrong",
+			func() database.UserRepo {
+				return repoMocks.NewUserRepo(t)
+			},
+			func() Generator {
+				mock := smocks.NewGenerator(t)
+				mock.On("CompareHashAndPassword", "old-hash", "wrong").Return(false).Times(1)
+				return mock
+			},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUserService(tt.repoConstructor(), tt.generatorConstructor(), passwordPolicy{})
+			assert.Equal(t, tt.want, u.VerifyPassword(user, tt.password))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+	"trainee/internal/domain"
+)
+
+// newUserToken returns the plain token to mail and the record to store, which keeps only its hash.
+func newUserToken(userID int64, purpose domain.TokenPurpose, ttl time.Duration) (string, domain.UserToken, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", domain.UserToken{}, err
+	}
+	plain := base64.RawURLEncoding.EncodeToString(b)
+	return plain, domain.UserToken{
+		UserID:      userID,
+		Purpose:     purpose,
+		Hash:        hashUserToken(plain),
+		ExpiresDate: time.Now().Add(ttl),
+	}, nil
+}
+
+func hashUserToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/upper/db/v4"
+	"time"
+	"trainee/config"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	"trainee/internal/infra/mail"
+)
+
+const emailVerificationTTL = 24
+
+var ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+//go:generate mockery --dir . --name VerificationService --output ./mocks
+type VerificationService interface {
+	Send(user domain.User) error
+	Resend(email string) error
+	Verify(token string) error
+}
+
+type verificationService struct {
+	tokenRepo   database.UserTokenRepo
+	userService UserService
+	mailer      mail.Mailer
+	config      config.Configuration
+}
+
+func NewVerificationService(tr database.UserTokenRepo, us UserService, m mail.Mailer, conf config.Configuration) VerificationService {
+	return verificationService{
+		tokenRepo:   tr,
+		userService: us,
+		mailer:      m,
+		config:      conf,
+	}
+}
+
+func (v verificationService) Send(user domain.User) error {
+	plain, token, err := newUserToken(user.ID, domain.TokenEmailVerification, time.Hour*emailVerificationTTL)
+	if err != nil {
+		return fmt.Errorf("verification service error send: %w", err)
+	}
+	_, err = v.tokenRepo.Save(token)
+	if err != nil {
+		return fmt.Errorf("verification service error send: %w", err)
+	}
+
+	err = v.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Open this link to confirm your address, it expires in %d hours:\n%s/verify-email?token=%s\n",
+			emailVerificationTTL, v.config.AppURL, plain),
+	})
+	if err != nil {
+		return fmt.Errorf("verification service error send: %w", err)
+	}
+	return nil
+}
+
+// Resend is quiet about unknown and already verified addresses, like the password reset.
+func (v verificationService) Resend(email string) error {
+	user, err := v.userService.FindByEmail(email)
+	if errors.Is(err, db.ErrNoMoreRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("verification service error resend: %w", err)
+	}
+	if user.EmailVerified() {
+		return nil
+	}
+	return v.Send(user)
+}
+
+func (v verificationService) Verify(plain string) error {
+	token, err := v.tokenRepo.FindByHash(domain.TokenEmailVerification, hashUserToken(plain))
+	if errors.Is(err, db.ErrNoMoreRows) {
+		return fmt.Errorf("verification service error verify: %w", ErrInvalidVerificationToken)
+	} else if err != nil {
+		return fmt.Errorf("verification service error verify: %w", err)
+	}
+	if !token.Usable(time.Now()) {
+		return fmt.Errorf("verification service error verify: %w", ErrInvalidVerificationToken)
+	}
+
+	err = v.tokenRepo.Use(token.ID)
+	if errors.Is(err, database.ErrTokenUsed) {
+		return fmt.Errorf("verification service error verify: %w", ErrInvalidVerificationToken)
+	} else if err != nil {
+		return fmt.Errorf("verification service error verify: %w", err)
+	}
+
+	_, err = v.userService.MarkEmailVerified(token.UserID)
+	if err != nil {
+		return fmt.Errorf("verification service error verify: %w", err)
+	}
+	err = v.tokenRepo.UseAll(token.UserID, domain.TokenEmailVerification)
+	if err != nil {
+		return fmt.Errorf("verification service error verify: %w", err)
+	}
+	return nil
+}
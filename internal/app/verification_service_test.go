@@ -0,0 +1,125 @@
+package app
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/upper/db/v4"
+	"strings"
+	"testing"
+	"time"
+	"trainee/config"
+	smocks "trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/database"
+	rmocks "trainee/internal/infra/database/mock"
+	"trainee/internal/infra/mail"
+	mmocks "trainee/internal/infra/mail/mock"
+)
+
+func Test_verificationService_Send(t *testing.T) {
+	var saved domain.UserToken
+	var sent mail.Message
+
+	tr := rmocks.NewUserTokenRepo(t)
+	tr.On("Save", mock.AnythingOfType("domain.UserToken")).
+		Run(func(args mock.Arguments) { saved = args.Get(0).(domain.UserToken) }).
+		Return(domain.UserToken{ID: 1}, nil).Times(1)
+	m := mmocks.NewMailer(t)
+	m.On("Send", mock.AnythingOfType("mail.Message")).
+		Run(func(args mock.Arguments) { sent = args.Get(0).(mail.Message) }).
+		Return(nil).Times(1)
+	v := NewVerificationService(tr, smocks.NewUserService(t), m, config.Configuration{AppURL: "http://app"})
+
+	assert.NoError(t, v.Send(domain.User{ID: 2, Email: "user@email.com"}))
+
+	assert.Equal(t, "user@email.com", sent.To)
+	i := strings.Index(sent.Body, "http://app/verify-email?token=")
+	assert.True(t, i >= 0)
+	plain := strings.Fields(sent.Body[i+len("http://app/verify-email?token="):])[0]
+	assert.Equal(t, hashUserToken(plain), saved.Hash)
+	assert.Equal(t, int64(2), saved.UserID)
+	assert.Equal(t, domain.TokenEmailVerification, saved.Purpose)
+}
+
+func Test_verificationService_Resend(t *testing.T) {
+	verified := time.Now()
+
+	t.Run("unknown email sends nothing", func(t *testing.T) {
+		us := smocks.NewUserService(t)
+		us.On("FindByEmail", "nobody@email.com").Return(domain.User{}, db.ErrNoMoreRows).Times(1)
+		v := NewVerificationService(rmocks.NewUserTokenRepo(t), us, mmocks.NewMailer(t), config.Configuration{})
+
+		assert.NoError(t, v.Resend("nobody@email.com"))
+	})
+
+	t.Run("verified email sends nothing", func(t *testing.T) {
+		us := smocks.NewUserService(t)
+		us.On("FindByEmail", "user@email.com").Return(domain.User{ID: 2, EmailVerifiedAt: &verified}, nil).Times(1)
+		v := NewVerificationService(rmocks.NewUserTokenRepo(t), us, mmocks.NewMailer(t), config.Configuration{})
+
+		assert.NoError(t, v.Resend("user@email.com"))
+	})
+}
+
+func Test_verificationService_Verify(t *testing.T) {
+	hash := hashUserToken("plain")
+	valid := domain.UserToken{ID: 1, UserID: 2, Purpose: domain.TokenEmailVerification, Hash: hash, ExpiresDate: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name      string
+		tokenRepo func() database.UserTokenRepo
+		us        func() UserService
+		wantErr   error
+	}{
+		{
+			"verify ok",
+			func() database.UserTokenRepo {
+				mock := rmocks.NewUserTokenRepo(t)
+				mock.
+					On("FindByHash", domain.TokenEmailVerification, hash).Return(valid, nil).Times(1).
+					On("Use", int64(1)).Return(nil).Times(1).
+					On("UseAll", int64(2), domain.TokenEmailVerification).Return(nil).Times(1)
+				return mock
+			},
+			func() UserService {
+				mock := smocks.NewUserService(t)
+				mock.On("MarkEmailVerified", int64(2)).Return(domain.User{ID: 2}, nil).Times(1)
+				return mock
+			},
+			nil,
+		},
+		{
+			"unknown token",
+			func() database.UserTokenRepo {
+				mock := rmocks.NewUserTokenRepo(t)
+				mock.On("FindByHash", domain.TokenEmailVerification, hash).Return(domain.UserToken{}, db.ErrNoMoreRows).Times(1)
+				return mock
+			},
+			func() UserService { return smocks.NewUserService(t) },
+			ErrInvalidVerificationToken,
+		},
+		{
+			"expired token",
+			func() database.UserTokenRepo {
+				expired := valid
+				expired.ExpiresDate = time.Now().Add(-time.Minute)
+				mock := rmocks.NewUserTokenRepo(t)
+				mock.On("FindByHash", domain.TokenEmailVerification, hash).Return(expired, nil).Times(1)
+				return mock
+			},
+			func() UserService { return smocks.NewUserService(t) },
+			ErrInvalidVerificationToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerificationService(tt.tokenRepo(), tt.us(), mmocks.NewMailer(t), config.Configuration{})
+			err := v.Verify("plain")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
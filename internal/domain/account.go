@@ -0,0 +1,40 @@
+package domain
+
+import "trainee/internal/infra/http/response"
+
+// AccountExport gathers everything a user owns, for them to take along.
+type AccountExport struct {
+	User          User
+	Posts         []Post
+	PostRevisions []PostRevision
+	Comments      []Comment
+	Identities    []Identity
+	APITokens     []APIToken
+	Sessions      []Session
+	AuthEvents    []AuthEvent
+}
+
+func (e AccountExport) DomainToResponse(currentSessionID string) response.AccountExportResponse {
+	posts := make([]response.PostResponse, 0, len(e.Posts))
+	for _, p := range e.Posts {
+		posts = append(posts, p.DomainToResponse())
+	}
+	comments := make([]response.CommentResponse, 0, len(e.Comments))
+	for _, c := range e.Comments {
+		comments = append(comments, c.DomainToResponse())
+	}
+	identities := make([]response.IdentityResponse, 0, len(e.Identities))
+	for _, i := range e.Identities {
+		identities = append(identities, i.DomainToResponse())
+	}
+	return response.AccountExportResponse{
+		User:          e.User.DomainToResponse(),
+		Posts:         posts,
+		PostRevisions: PostRevision{}.AllPostRevisionsDomainToResponse(e.PostRevisions),
+		Comments:      comments,
+		Identities:    identities,
+		APITokens:     APIToken{}.AllAPITokensDomainToResponse(e.APITokens),
+		Sessions:      Session{}.AllSessionsDomainToResponse(e.Sessions, currentSessionID),
+		AuthEvents:    AuthEvent{}.AllAuthEventsDomainToResponse(e.AuthEvents),
+	}
+}
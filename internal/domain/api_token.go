@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+	"trainee/internal/infra/http/response"
+)
+
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+)
+
+// APIToken is a personal access token for machine clients. Only the hash of the token is stored.
+type APIToken struct {
+	ID           int64
+	UserID       int64
+	Name         string
+	Prefix       string
+	Hash         string
+	Scopes       []Scope
+	ExpiresDate  time.Time
+	LastUsedDate *time.Time
+	CreatedDate  time.Time
+}
+
+func (t APIToken) HasScope(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the token may be used for the scope, write implies read.
+func (t APIToken) Allows(scope Scope) bool {
+	return t.HasScope(scope) || t.HasScope(ScopeWrite)
+}
+
+func (t APIToken) DomainToResponse() response.APITokenResponse {
+	scopes := make([]string, 0, len(t.Scopes))
+	for _, s := range t.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	return response.APITokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  t.ExpiresDate,
+		LastUsedAt: t.LastUsedDate,
+		CreatedAt:  t.CreatedDate,
+	}
+}
+
+func (t APIToken) AllAPITokensDomainToResponse(tokens []APIToken) []response.APITokenResponse {
+	convertDomainTokensToResponse := make([]response.APITokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		convertDomainTokensToResponse = append(convertDomainTokensToResponse, token.DomainToResponse())
+	}
+	return convertDomainTokensToResponse
+}
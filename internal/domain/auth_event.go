@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+	"trainee/internal/infra/http/response"
+)
+
+type AuthEventType string
+
+const (
+	AuthEventLogin          AuthEventType = "login"
+	AuthEventRefresh        AuthEventType = "refresh"
+	AuthEventLogout         AuthEventType = "logout"
+	AuthEventOAuthLink      AuthEventType = "oauth_link"
+	AuthEventPasswordChange AuthEventType = "password_change"
+	AuthEventImpersonate    AuthEventType = "impersonate"
+	AuthEventTokenCreate    AuthEventType = "token_created"
+	AuthEventTokenRevoke    AuthEventType = "token_revoked"
+	AuthEventTokenScope     AuthEventType = "token_scope"
+)
+
+type AuthOutcome string
+
+const (
+	AuthSuccess AuthOutcome = "success"
+	AuthFailure AuthOutcome = "failure"
+)
+
+// AuthEvent is an entry of the authentication audit log. UserID is 0 when a login named an unknown email,
+// ActorID is the admin who acted on the user's behalf.
+type AuthEvent struct {
+	ID      int64
+	UserID  int64
+	ActorID int64
+	Email   string
+	Type    AuthEventType
+	Outcome AuthOutcome
+	// Detail says why an attempt failed, or which provider an oauth link was made with
+	Detail      string
+	IP          string
+	UserAgent   string
+	CreatedDate time.Time
+}
+
+// AuthEventFilter selects audit log entries newest first, Before is the id the previous page ended with.
+type AuthEventFilter struct {
+	UserID  int64
+	ActorID int64
+	Email   string
+	Type    AuthEventType
+	Outcome AuthOutcome
+	Before  int64
+	Limit   int
+}
+
+func NewAuthEvent(t AuthEventType, outcome AuthOutcome, userID int64, device Device) AuthEvent {
+	return AuthEvent{
+		UserID:    userID,
+		Type:      t,
+		Outcome:   outcome,
+		IP:        device.IP,
+		UserAgent: device.UserAgent,
+	}
+}
+
+func (e AuthEvent) DomainToResponse() response.AuthEventResponse {
+	return response.AuthEventResponse{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		ActorID:   e.ActorID,
+		Email:     e.Email,
+		Type:      string(e.Type),
+		Outcome:   string(e.Outcome),
+		Detail:    e.Detail,
+		IP:        e.IP,
+		UserAgent: e.UserAgent,
+		CreatedAt: e.CreatedDate,
+	}
+}
+
+func (e AuthEvent) AllAuthEventsDomainToResponse(events []AuthEvent) []response.AuthEventResponse {
+	convertDomainEventsToResponse := make([]response.AuthEventResponse, 0, len(events))
+	for _, event := range events {
+		convertDomainEventsToResponse = append(convertDomainEventsToResponse, event.DomainToResponse())
+	}
+	return convertDomainEventsToResponse
+}
@@ -8,6 +8,7 @@ import (
 type Comment struct {
 	ID          int64
 	PostID      int64
+	UserID      int64
 	Name        string
 	Email       string
 	Body        string
@@ -20,6 +21,7 @@ func (c Comment) DomainToResponse() response.CommentResponse {
 	return response.CommentResponse{
 		ID:     c.ID,
 		PostID: c.PostID,
+		UserID: c.UserID,
 		Name:   c.Name,
 		Email:  c.Email,
 		Body:   c.Body,
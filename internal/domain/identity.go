@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+	"trainee/internal/infra/http/response"
+)
+
+// Identity links an account of an external OAuth provider to a user.
+type Identity struct {
+	ID            int64
+	UserID        int64
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	CreatedDate   time.Time
+}
+
+func (i Identity) DomainToResponse() response.IdentityResponse {
+	return response.IdentityResponse{
+		Provider:  i.Provider,
+		Email:     i.Email,
+		CreatedAt: i.CreatedDate,
+	}
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+	"trainee/internal/infra/http/response"
+)
+
+// PostRevision is the title and body a post had after an edit, the first revision is the post as it was saved.
+type PostRevision struct {
+	PostID      int64
+	Revision    int
+	EditorID    int64
+	Title       string
+	Body        string
+	CreatedDate time.Time
+}
+
+// PostDiff is a unified diff between two revisions of a post, empty when they are the same.
+type PostDiff struct {
+	PostID int64
+	From   int
+	To     int
+	Diff   string
+}
+
+func (r PostRevision) DomainToResponse() response.PostRevisionResponse {
+	return response.PostRevisionResponse{
+		PostID:    r.PostID,
+		Revision:  r.Revision,
+		EditorID:  r.EditorID,
+		Title:     r.Title,
+		Body:      r.Body,
+		CreatedAt: r.CreatedDate,
+	}
+}
+
+func (r PostRevision) AllPostRevisionsDomainToResponse(revisions []PostRevision) []response.PostRevisionResponse {
+	convertDomainRevisionsToResponse := make([]response.PostRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		convertDomainRevisionsToResponse = append(convertDomainRevisionsToResponse, revision.DomainToResponse())
+	}
+	return convertDomainRevisionsToResponse
+}
+
+func (d PostDiff) DomainToResponse() response.PostDiffResponse {
+	return response.PostDiffResponse{
+		PostID: d.PostID,
+		From:   d.From,
+		To:     d.To,
+		Diff:   d.Diff,
+	}
+}
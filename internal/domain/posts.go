@@ -6,25 +6,97 @@ import (
 )
 
 type Post struct {
-	UserID      int64
-	ID          int64
-	Title       string
-	Body        string
-	Comments    []response.CommentResponse
-	CreatedDate time.Time
-	UpdatedDate time.Time
-	DeletedDate *time.Time
+	UserID        int64
+	ID            int64
+	Title         string
+	Body          string
+	Tags          []string
+	Status        PostStatus
+	Comments      []response.CommentResponse
+	CreatedDate   time.Time
+	UpdatedDate   time.Time
+	DeletedDate   *time.Time
+	PublishedDate *time.Time
+}
+
+// PostStatus is where a post is in its lifecycle. Only published posts are visible to everyone, the others only
+// to their author. A scheduled post gets published at its PublishedDate.
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusScheduled PostStatus = "scheduled"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+// PostSort is the date the post feed is ordered by.
+type PostSort string
+
+const (
+	PostSortCreated PostSort = "created"
+	PostSortUpdated PostSort = "updated"
+)
+
+// PostFilter selects a page of the post feed, newest first unless Ascending. From and To bound the date
+// the feed is sorted by, To excluded. Posts must carry all the Tags. After is where the previous page ended.
+// The feed holds the published posts and the drafts and scheduled posts of the viewer, a Status other than
+// published only selects posts of the viewer.
+type PostFilter struct {
+	ViewerID  int64
+	UserID    int64
+	Status    PostStatus
+	Tags      []string
+	From      time.Time
+	To        time.Time
+	Sort      PostSort
+	Ascending bool
+	After     *PostCursor
+	Limit     int
+}
+
+// PostCursor is the last post of a feed page, by its sort date and id.
+type PostCursor struct {
+	Date time.Time
+	ID   int64
+}
+
+// PostPage is a page of the feed, Total counts every post of the filter and NextCursor is empty on the last page.
+type PostPage struct {
+	Posts      []Post
+	NextCursor string
+	Total      uint64
 }
 
 func (p Post) DomainToResponse() response.PostResponse {
 	resp := response.PostResponse{
-		ID:     p.ID,
-		UserID: p.UserID,
-		Title:  p.Title,
-		Body:   p.Body,
+		ID:          p.ID,
+		UserID:      p.UserID,
+		Title:       p.Title,
+		Body:        p.Body,
+		Tags:        p.Tags,
+		Status:      string(p.Status),
+		CreatedDate: p.CreatedDate,
+		UpdatedDate: p.UpdatedDate,
+		PublishedAt: p.PublishedDate,
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
 	}
 	if len(p.Comments) != 0 {
 		resp.Comments = p.Comments
 	}
 	return resp
 }
+
+func (p PostPage) DomainToResponse() response.PostPageResponse {
+	posts := make([]response.PostResponse, 0, len(p.Posts))
+	for _, post := range p.Posts {
+		posts = append(posts, post.DomainToResponse())
+	}
+	return response.PostPageResponse{
+		Posts:      posts,
+		NextCursor: p.NextCursor,
+		Total:      p.Total,
+	}
+}
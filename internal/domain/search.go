@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+	"trainee/internal/infra/http/response"
+)
+
+// SearchResultType tells a matching post from a matching comment.
+type SearchResultType string
+
+const (
+	SearchResultPost    SearchResultType = "post"
+	SearchResultComment SearchResultType = "comment"
+)
+
+// SearchQuery is a web search style query: words, "quoted phrases", or between alternatives and -excluded words.
+// An empty Type searches posts and comments.
+type SearchQuery struct {
+	Query  string
+	Type   SearchResultType
+	Limit  int
+	Offset int
+}
+
+// SearchResult is a post or comment matching a search, best match first. Title is the title of the post
+// and Snippet the matching part of the body, with the hits highlighted.
+type SearchResult struct {
+	Type        SearchResultType
+	ID          int64
+	PostID      int64
+	UserID      int64
+	Title       string
+	Snippet     string
+	Rank        float64
+	CreatedDate time.Time
+}
+
+func (r SearchResult) DomainToResponse() response.SearchResultResponse {
+	return response.SearchResultResponse{
+		Type:      string(r.Type),
+		ID:        r.ID,
+		PostID:    r.PostID,
+		UserID:    r.UserID,
+		Title:     r.Title,
+		Snippet:   r.Snippet,
+		Rank:      r.Rank,
+		CreatedAt: r.CreatedDate,
+	}
+}
+
+func (r SearchResult) AllSearchResultsDomainToResponse(results []SearchResult) []response.SearchResultResponse {
+	convertDomainResultsToResponse := make([]response.SearchResultResponse, 0, len(results))
+	for _, result := range results {
+		convertDomainResultsToResponse = append(convertDomainResultsToResponse, result.DomainToResponse())
+	}
+	return convertDomainResultsToResponse
+}
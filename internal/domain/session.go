@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+	"trainee/internal/infra/http/response"
+)
+
+type Session struct {
+	ID     string
+	UserID int64
+	// ActorID is the admin impersonating the user in this session, 0 for the user's own sessions
+	ActorID    int64
+	AccessID   string
+	RefreshID  string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	// ExpiresAt is the end of the session however active it is, the stores never keep it longer
+	ExpiresAt time.Time
+}
+
+// Device describes the client a session was opened from.
+type Device struct {
+	UserAgent string
+	IP        string
+}
+
+func (s Session) DomainToResponse(currentID string) response.SessionResponse {
+	return response.SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    s.ID == currentID,
+		ActorID:    s.ActorID,
+	}
+}
+
+func (s Session) AllSessionsDomainToResponse(sessions []Session, currentID string) []response.SessionResponse {
+	convertDomainSessionsToResponse := make([]response.SessionResponse, 0, len(sessions))
+	for _, sess := range sessions {
+		convertDomainSessionsToResponse = append(convertDomainSessionsToResponse, sess.DomainToResponse(currentID))
+	}
+	return convertDomainSessionsToResponse
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "trainee/internal/infra/http/response"
+
+// Tag labels posts, PostCount counts the published posts carrying it.
+type Tag struct {
+	ID        int64
+	Name      string
+	PostCount int64
+}
+
+func (t Tag) DomainToResponse() response.TagResponse {
+	return response.TagResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		PostCount: t.PostCount,
+	}
+}
+
+func (t Tag) AllTagsDomainToResponse(tags []Tag) []response.TagResponse {
+	convertDomainTagsToResponse := make([]response.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		convertDomainTagsToResponse = append(convertDomainTagsToResponse, tag.DomainToResponse())
+	}
+	return convertDomainTagsToResponse
+}
@@ -5,20 +5,88 @@ import (
 	"trainee/internal/infra/http/response"
 )
 
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Permission is what a role may do beyond acting on its own content.
+type Permission string
+
+const (
+	PermissionDeleteAnyComment Permission = "comments:delete-any"
+	PermissionManageUsers      Permission = "users:manage"
+	PermissionManageTags       Permission = "tags:manage"
+	PermissionViewAuthEvents   Permission = "auth-events:view"
+)
+
+// rolePermissions is the one place roles get their rights, routes and policies both ask it through Can.
+var rolePermissions = map[Role][]Permission{
+	RoleModerator: {PermissionDeleteAnyComment},
+	RoleAdmin:     {PermissionDeleteAnyComment, PermissionManageUsers, PermissionManageTags, PermissionViewAuthEvents},
+}
+
+func (r Role) Can(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RolesWith lists the roles granted the permission.
+func RolesWith(p Permission) []Role {
+	var roles []Role
+	for _, r := range []Role{RoleUser, RoleModerator, RoleAdmin} {
+		if r.Can(p) {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
 type User struct {
-	ID          int64
-	Email       string
-	Name        string
-	Password    string
-	CreatedDate time.Time
-	UpdatedDate time.Time
-	DeletedDate *time.Time
+	ID       int64
+	Email    string
+	Name     string
+	Password string
+	Role     Role
+	// EmailVerifiedAt is nil until the user confirms the address
+	EmailVerifiedAt *time.Time
+	// MFASecret is the encrypted TOTP secret, MFAEnabledAt is set once the user confirmed it with a code
+	MFASecret    string
+	MFAEnabledAt *time.Time
+	CreatedDate  time.Time
+	UpdatedDate  time.Time
+	DeletedDate  *time.Time
 }
 
 func (u User) DomainToResponse() response.UserResponse {
 	return response.UserResponse{
-		ID:    u.ID,
-		Email: u.Email,
-		Name:  u.Name,
+		ID:            u.ID,
+		Email:         u.Email,
+		Name:          u.Name,
+		Role:          string(u.Role),
+		EmailVerified: u.EmailVerified(),
 	}
 }
+
+func (u User) MFAEnabled() bool {
+	return u.MFAEnabledAt != nil
+}
+
+func (u User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
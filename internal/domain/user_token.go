@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+type TokenPurpose string
+
+const (
+	TokenPasswordReset     TokenPurpose = "password_reset"
+	TokenEmailVerification TokenPurpose = "email_verification"
+)
+
+// UserToken is a single-use token mailed to a user. Only the hash of the token is stored.
+type UserToken struct {
+	ID          int64
+	UserID      int64
+	Purpose     TokenPurpose
+	Hash        string
+	ExpiresDate time.Time
+	UsedDate    *time.Time
+	CreatedDate time.Time
+}
+
+func (t UserToken) Usable(now time.Time) bool {
+	return t.UsedDate == nil && now.Before(t.ExpiresDate)
+}
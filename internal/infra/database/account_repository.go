@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+	"github.com/upper/db/v4"
+	"time"
+)
+
+// deletedAuthor is the name anonymized comments are shown with.
+const deletedAuthor = "Deleted user"
+
+//go:generate mockery --dir . --name AccountRepo --output ./mock
+type AccountRepo interface {
+	Delete(userID int64, deletePosts, deleteComments bool) error
+}
+
+type accountRepo struct {
+	sess db.Session
+}
+
+func NewAccountRepo(dbSession db.Session) AccountRepo {
+	return accountRepo{
+		sess: dbSession,
+	}
+}
+
+// Delete soft-deletes the user with their personal data wiped, also from their auth events, and drops everything that
+// lets them sign in.
+// Their posts and comments are soft-deleted too, or else kept without an author. The revisions they wrote go with
+// their posts, or lose their editor the same way. It all happens or nothing does.
+func (r accountRepo) Delete(userID int64, deletePosts, deleteComments bool) error {
+	now := time.Now()
+	err := r.sess.Tx(func(tx db.Session) error {
+		res, err := tx.SQL().
+			Update(UsersTable).
+			Set(map[string]interface{}{
+				"email":          fmt.Sprintf("deleted-%d", userID),
+				"name":           "",
+				"password":       "",
+				"mfa_secret":     "",
+				"mfa_enabled_at": nil,
+				"updated_date":   now,
+				"deleted_date":   now,
+			}).
+			Where(db.Cond{"id": userID, "deleted_date": nil}).
+			Exec()
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected != 1 {
+			return db.ErrNoMoreRows
+		}
+
+		posts := tx.Collection(PostTable).Find(db.Cond{"user_id": userID, "deleted_date": nil})
+		if deletePosts {
+			err = posts.Update(map[string]interface{}{"deleted_date": now})
+		} else {
+			err = posts.Update(map[string]interface{}{"user_id": 0, "updated_date": now})
+		}
+		if err != nil {
+			return err
+		}
+
+		// revisions hold every title and body the user ever wrote, a soft-deleted post must not keep them
+		revisions := tx.Collection(PostRevisionTable).Find(db.Cond{"editor_id": userID})
+		if deletePosts {
+			err = revisions.Delete()
+		} else {
+			err = revisions.Update(map[string]interface{}{"editor_id": 0})
+		}
+		if err != nil {
+			return err
+		}
+
+		// comments carry the author's name and email, so they are wiped from deleted comments as well
+		anonymized := map[string]interface{}{"user_id": 0, "name": deletedAuthor, "email": "", "updated_date": now}
+		if deleteComments {
+			anonymized["deleted_date"] = now
+		}
+		err = tx.Collection(CommentTable).Find(db.Cond{"user_id": userID}).Update(anonymized)
+		if err != nil {
+			return err
+		}
+
+		// the audit log keeps what happened to the account, but not where from or under which address
+		err = tx.Collection(AuthEventTable).Find(db.Cond{"user_id": userID}).
+			Update(map[string]interface{}{"email": "", "ip": "", "user_agent": ""})
+		if err != nil {
+			return err
+		}
+
+		for _, table := range []string{IdentityTable, APITokenTable, RecoveryCodeTable, UserTokenTable} {
+			err = tx.Collection(table).Find(db.Cond{"user_id": userID}).Delete()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("account repository delete account: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/upper/db/v4/adapter/postgresql"
+	"io"
+	"strings"
+	"testing"
+)
+
+// statement is a query the account deletion sent, with its whitespace collapsed.
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+// recorder is a database that takes every statement and changes one row with it, queries find nothing but a name.
+type recorder struct {
+	statements []statement
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) { return recorderConn{r}, nil }
+func (r *recorder) Driver() driver.Driver                        { return nil }
+
+type recorderConn struct{ r *recorder }
+
+func (c recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return recorderStmt{c.r, query}, nil
+}
+func (c recorderConn) Close() error              { return nil }
+func (c recorderConn) Begin() (driver.Tx, error) { return c, nil }
+func (c recorderConn) Commit() error             { return nil }
+func (c recorderConn) Rollback() error           { return nil }
+
+type recorderStmt struct {
+	r     *recorder
+	query string
+}
+
+func (s recorderStmt) Close() error  { return nil }
+func (s recorderStmt) NumInput() int { return -1 }
+
+func (s recorderStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.r.statements = append(s.r.statements, statement{strings.Join(strings.Fields(s.query), " "), args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s recorderStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &nameRows{}, nil
+}
+
+type nameRows struct{ read bool }
+
+func (r *nameRows) Columns() []string { return []string{"name"} }
+func (r *nameRows) Close() error      { return nil }
+
+func (r *nameRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = "trainee"
+	return nil
+}
+
+func (r *recorder) find(prefix string) (statement, bool) {
+	for _, s := range r.statements {
+		if strings.HasPrefix(s.query, prefix) {
+			return s, true
+		}
+	}
+	return statement{}, false
+}
+
+func Test_accountRepo_Delete(t *testing.T) {
+	tests := []struct {
+		name        string
+		deletePosts bool
+		revisions   statement
+	}{
+		{
+			"anonymize posts",
+			false,
+			statement{`UPDATE "post_revisions" SET "editor_id" = $1 WHERE ("editor_id" = $2)`, []driver.Value{int64(0), int64(2)}},
+		},
+		{
+			"delete posts",
+			true,
+			statement{`DELETE FROM "post_revisions" WHERE ("editor_id" = $1)`, []driver.Value{int64(2)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			sess, err := postgresql.New(sql.OpenDB(r))
+			require.NoError(t, err)
+
+			require.NoError(t, NewAccountRepo(sess).Delete(2, tt.deletePosts, false))
+
+			revisions, ok := r.find(`UPDATE "post_revisions"`)
+			if !ok {
+				revisions, ok = r.find(`DELETE FROM "post_revisions"`)
+			}
+			assert.True(t, ok)
+			assert.Equal(t, tt.revisions, revisions)
+
+			// the audit log keeps the events, without the address, ip and browser of the user
+			events, ok := r.find(`UPDATE "auth_events"`)
+			assert.True(t, ok)
+			assert.Equal(t, statement{
+				`UPDATE "auth_events" SET "email" = $1, "ip" = $2, "user_agent" = $3 WHERE ("user_id" = $4)`,
+				[]driver.Value{"", "", "", int64(2)},
+			}, events)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"fmt"
+	"github.com/upper/db/v4"
+	"strings"
+	"time"
+	"trainee/internal/domain"
+)
+
+const APITokenTable = "api_tokens"
+
+type apiToken struct {
+	ID           int64      `db:"id,omitempty"`
+	UserID       int64      `db:"user_id"`
+	Name         string     `db:"name"`
+	Prefix       string     `db:"prefix"`
+	Hash         string     `db:"token_hash"`
+	Scopes       string     `db:"scopes"`
+	ExpiresDate  time.Time  `db:"expires_date"`
+	LastUsedDate *time.Time `db:"last_used_date,omitempty"`
+	CreatedDate  time.Time  `db:"created_date,omitempty"`
+}
+
+//go:generate mockery --dir . --name APITokenRepo --output ./mock
+type APITokenRepo interface {
+	Save(token domain.APIToken) (domain.APIToken, error)
+	FindByHash(hash string) (domain.APIToken, error)
+	FindByUser(userID int64) ([]domain.APIToken, error)
+	Delete(id, userID int64) error
+	Touch(id int64) error
+}
+
+type apiTokenRepo struct {
+	coll db.Collection
+}
+
+func NewAPITokenRepo(dbSession db.Session) APITokenRepo {
+	return apiTokenRepo{
+		coll: dbSession.Collection(APITokenTable),
+	}
+}
+
+func (r apiTokenRepo) Save(token domain.APIToken) (domain.APIToken, error) {
+	tokenDB := r.mapDomainToModel(token)
+	tokenDB.CreatedDate = time.Now()
+	err := r.coll.InsertReturning(&tokenDB)
+	if err != nil {
+		return domain.APIToken{}, fmt.Errorf("api token repository save token: %w", err)
+	}
+	return r.mapModelToDomain(tokenDB), nil
+}
+
+func (r apiTokenRepo) FindByHash(hash string) (domain.APIToken, error) {
+	var tokenDB apiToken
+	err := r.coll.Find(db.Cond{"token_hash": hash}).One(&tokenDB)
+	if err != nil {
+		return domain.APIToken{}, fmt.Errorf("api token repository find by hash: %w", err)
+	}
+	return r.mapModelToDomain(tokenDB), nil
+}
+
+func (r apiTokenRepo) FindByUser(userID int64) ([]domain.APIToken, error) {
+	var tokensDB []apiToken
+	err := r.coll.Find(db.Cond{"user_id": userID}).OrderBy("-created_date").All(&tokensDB)
+	if err != nil {
+		return nil, fmt.Errorf("api token repository find by user: %w", err)
+	}
+	tokens := make([]domain.APIToken, 0, len(tokensDB))
+	for _, t := range tokensDB {
+		tokens = append(tokens, r.mapModelToDomain(t))
+	}
+	return tokens, nil
+}
+
+// Delete only removes a token of the given user, any other id ends in db.ErrNoMoreRows.
+func (r apiTokenRepo) Delete(id, userID int64) error {
+	res := r.coll.Find(db.Cond{"id": id, "user_id": userID})
+	exists, err := res.Exists()
+	if err != nil {
+		return fmt.Errorf("api token repository delete token: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("api token repository delete token: %w", db.ErrNoMoreRows)
+	}
+	err = res.Delete()
+	if err != nil {
+		return fmt.Errorf("api token repository delete token: %w", err)
+	}
+	return nil
+}
+
+func (r apiTokenRepo) Touch(id int64) error {
+	err := r.coll.Find(db.Cond{"id": id}).Update(map[string]interface{}{"last_used_date": time.Now()})
+	if err != nil {
+		return fmt.Errorf("api token repository touch token: %w", err)
+	}
+	return nil
+}
+
+func (r apiTokenRepo) mapDomainToModel(d domain.APIToken) apiToken {
+	scopes := make([]string, 0, len(d.Scopes))
+	for _, s := range d.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	return apiToken{
+		ID:           d.ID,
+		UserID:       d.UserID,
+		Name:         d.Name,
+		Prefix:       d.Prefix,
+		Hash:         d.Hash,
+		Scopes:       strings.Join(scopes, ","),
+		ExpiresDate:  d.ExpiresDate,
+		LastUsedDate: d.LastUsedDate,
+	}
+}
+
+func (r apiTokenRepo) mapModelToDomain(d apiToken) domain.APIToken {
+	var scopes []domain.Scope
+	for _, s := range strings.Split(d.Scopes, ",") {
+		if s != "" {
+			scopes = append(scopes, domain.Scope(s))
+		}
+	}
+	return domain.APIToken{
+		ID:           d.ID,
+		UserID:       d.UserID,
+		Name:         d.Name,
+		Prefix:       d.Prefix,
+		Hash:         d.Hash,
+		Scopes:       scopes,
+		ExpiresDate:  d.ExpiresDate,
+		LastUsedDate: d.LastUsedDate,
+		CreatedDate:  d.CreatedDate,
+	}
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"fmt"
+	"github.com/upper/db/v4"
+	"strings"
+	"time"
+	"trainee/internal/domain"
+)
+
+const AuthEventTable = "auth_events"
+
+type authEvent struct {
+	ID          int64     `db:"id,omitempty"`
+	UserID      *int64    `db:"user_id,omitempty"`
+	ActorID     *int64    `db:"actor_id,omitempty"`
+	Email       string    `db:"email"`
+	Event       string    `db:"event"`
+	Outcome     string    `db:"outcome"`
+	Detail      string    `db:"detail"`
+	IP          string    `db:"ip"`
+	UserAgent   string    `db:"user_agent"`
+	CreatedDate time.Time `db:"created_date"`
+}
+
+//go:generate mockery --dir . --name AuthEventRepo --output ./mock
+type AuthEventRepo interface {
+	Save(event domain.AuthEvent) (domain.AuthEvent, error)
+	Find(filter domain.AuthEventFilter) ([]domain.AuthEvent, error)
+}
+
+type authEventRepo struct {
+	coll db.Collection
+}
+
+func NewAuthEventRepo(dbSession db.Session) AuthEventRepo {
+	return authEventRepo{
+		coll: dbSession.Collection(AuthEventTable),
+	}
+}
+
+func (r authEventRepo) Save(event domain.AuthEvent) (domain.AuthEvent, error) {
+	eventDB := r.mapDomainToModel(event)
+	eventDB.CreatedDate = time.Now()
+	err := r.coll.InsertReturning(&eventDB)
+	if err != nil {
+		return domain.AuthEvent{}, fmt.Errorf("auth event repository save event: %w", err)
+	}
+	return r.mapModelToDomain(eventDB), nil
+}
+
+func (r authEventRepo) Find(filter domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	cond := db.Cond{}
+	if filter.UserID != 0 {
+		cond["user_id"] = filter.UserID
+	}
+	if filter.ActorID != 0 {
+		cond["actor_id"] = filter.ActorID
+	}
+	if filter.Email != "" {
+		cond["email"] = strings.ToLower(filter.Email)
+	}
+	if filter.Type != "" {
+		cond["event"] = filter.Type
+	}
+	if filter.Outcome != "" {
+		cond["outcome"] = filter.Outcome
+	}
+	if filter.Before != 0 {
+		cond["id <"] = filter.Before
+	}
+	var eventsDB []authEvent
+	err := r.coll.Find(cond).OrderBy("-id").Limit(filter.Limit).All(&eventsDB)
+	if err != nil {
+		return nil, fmt.Errorf("auth event repository find events: %w", err)
+	}
+	events := make([]domain.AuthEvent, 0, len(eventsDB))
+	for _, e := range eventsDB {
+		events = append(events, r.mapModelToDomain(e))
+	}
+	return events, nil
+}
+
+// mapDomainToModel fits the values into their columns, failed logins carry whatever the client sent and are
+// the last events that should be lost to an oversized value.
+func (r authEventRepo) mapDomainToModel(d domain.AuthEvent) authEvent {
+	return authEvent{
+		ID:        d.ID,
+		UserID:    optionalID(d.UserID),
+		ActorID:   optionalID(d.ActorID),
+		Email:     truncate(strings.ToLower(d.Email), 100),
+		Event:     string(d.Type),
+		Outcome:   string(d.Outcome),
+		Detail:    truncate(d.Detail, 100),
+		IP:        truncate(d.IP, 45),
+		UserAgent: truncate(d.UserAgent, 255),
+	}
+}
+
+func (r authEventRepo) mapModelToDomain(d authEvent) domain.AuthEvent {
+	return domain.AuthEvent{
+		ID:          d.ID,
+		UserID:      idOrZero(d.UserID),
+		ActorID:     idOrZero(d.ActorID),
+		Email:       d.Email,
+		Type:        domain.AuthEventType(d.Event),
+		Outcome:     domain.AuthOutcome(d.Outcome),
+		Detail:      d.Detail,
+		IP:          d.IP,
+		UserAgent:   d.UserAgent,
+		CreatedDate: d.CreatedDate,
+	}
+}
+
+// optionalID stores 0 as null, so the foreign key only applies to real users.
+func optionalID(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func idOrZero(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// truncate keeps client supplied text within the n characters of its column.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"trainee/internal/domain"
+)
+
+func Test_authEventRepo_mapDomainToModel(t *testing.T) {
+	event := domain.AuthEvent{
+		Type:      domain.AuthEventLogin,
+		Outcome:   domain.AuthFailure,
+		Email:     strings.Repeat("É", 150) + "@example.com",
+		Detail:    strings.Repeat("d", 150),
+		IP:        strings.Repeat("1.", 40),
+		UserAgent: strings.Repeat("ü", 300),
+	}
+	model := authEventRepo{}.mapDomainToModel(event)
+	assert.Equal(t, strings.Repeat("é", 100), model.Email)
+	assert.Equal(t, strings.Repeat("d", 100), model.Detail)
+	assert.Equal(t, strings.Repeat("1.", 22)+"1", model.IP)
+	assert.Equal(t, strings.Repeat("ü", 255), model.UserAgent)
+
+	short := authEventRepo{}.mapDomainToModel(domain.AuthEvent{Email: "User@Example.com", IP: "::1"})
+	assert.Equal(t, "user@example.com", short.Email)
+	assert.Equal(t, "::1", short.IP)
+}
@@ -12,12 +12,15 @@ const CommentTable = "commentses"
 type comments struct {
 	ID          int64      `db:"id,omitempty"`
 	PostID      int64      `db:"post_id"`
+	UserID      int64      `db:"user_id"`
 	Name        string     `db:"name"`
 	Email       string     `db:"email"`
 	Body        string     `db:"body"`
 	CreatedDate time.Time  `db:"created_date,omitempty"`
 	UpdatedDate time.Time  `db:"updated_date"`
 	DeletedDate *time.Time `db:"deleted_date,omitempty"`
+	// Language is the text search configuration of the comment, it is only set on insert
+	Language string `db:"language,omitempty"`
 }
 
 //go:generate mockery --dir . --name CommentRepo --output ./mock
@@ -27,15 +30,18 @@ type CommentRepo interface {
 	UpdateComment(comment domain.Comment) (domain.Comment, error)
 	DeleteComment(id int64) error
 	GetCommentsByPostID(postID int64, offset int) ([]domain.Comment, error)
+	GetCommentsByUser(userID int64) ([]domain.Comment, error)
 }
 
 type commentsRepository struct {
-	coll db.Collection
+	coll           db.Collection
+	searchLanguage string
 }
 
-func NewCommentRepository(dbSession db.Session) CommentRepo {
+func NewCommentRepository(dbSession db.Session, searchLanguage string) CommentRepo {
 	return commentsRepository{
-		coll: dbSession.Collection(CommentTable),
+		coll:           dbSession.Collection(CommentTable),
+		searchLanguage: searchLanguage,
 	}
 }
 
@@ -43,6 +49,7 @@ func (r commentsRepository) SaveComment(comment domain.Comment) (domain.Comment,
 	commentsDB := r.mapCommentDBModel(comment)
 	commentsDB.CreatedDate = time.Now()
 	commentsDB.UpdatedDate = time.Now()
+	commentsDB.Language = r.searchLanguage
 	err := r.coll.InsertReturning(&commentsDB)
 	if err != nil {
 		return domain.Comment{}, fmt.Errorf("comment repository save comment: %w", err)
@@ -96,10 +103,21 @@ func (r commentsRepository) GetCommentsByPostID(postID int64, offset int) ([]dom
 	return r.mapCommentCollection(comment), nil
 }
 
+func (r commentsRepository) GetCommentsByUser(userID int64) ([]domain.Comment, error) {
+	var comment []comments
+
+	err := r.coll.Find(db.Cond{"user_id": userID}).OrderBy("id").All(&comment)
+	if err != nil {
+		return []domain.Comment{}, fmt.Errorf("comment repository get comments by user: %w", err)
+	}
+	return r.mapCommentCollection(comment), nil
+}
+
 func (r commentsRepository) mapCommentDBModel(comment domain.Comment) comments {
 	return comments{
 		ID:     comment.ID,
 		PostID: comment.PostID,
+		UserID: comment.UserID,
 		Name:   comment.Name,
 		Email:  comment.Email,
 		Body:   comment.Body,
@@ -110,6 +128,7 @@ func (r commentsRepository) mapCommentDbModelToDomain(comment comments) domain.C
 	return domain.Comment{
 		ID:          comment.ID,
 		PostID:      comment.PostID,
+		UserID:      comment.UserID,
 		Name:        comment.Name,
 		Email:       comment.Email,
 		Body:        comment.Body,
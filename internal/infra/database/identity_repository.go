@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+	"github.com/upper/db/v4"
+	"strings"
+	"time"
+	"trainee/internal/domain"
+)
+
+const IdentityTable = "user_identities"
+
+type identity struct {
+	ID          int64     `db:"id,omitempty"`
+	UserID      int64     `db:"user_id"`
+	Provider    string    `db:"provider"`
+	Subject     string    `db:"subject"`
+	Email       string    `db:"email"`
+	CreatedDate time.Time `db:"created_date,omitempty"`
+}
+
+//go:generate mockery --dir . --name IdentityRepo --output ./mock
+type IdentityRepo interface {
+	Save(identity domain.Identity) (domain.Identity, error)
+	FindByProviderSubject(provider, subject string) (domain.Identity, error)
+	FindByUser(userID int64) ([]domain.Identity, error)
+}
+
+type identityRepo struct {
+	coll db.Collection
+}
+
+func NewIdentityRepo(dbSession db.Session) IdentityRepo {
+	return identityRepo{
+		coll: dbSession.Collection(IdentityTable),
+	}
+}
+
+func (r identityRepo) Save(identity domain.Identity) (domain.Identity, error) {
+	identityDB := r.mapDomainToModel(identity)
+	identityDB.CreatedDate = time.Now()
+	err := r.coll.InsertReturning(&identityDB)
+	if err != nil {
+		return domain.Identity{}, fmt.Errorf("identity repository save identity: %w", err)
+	}
+	return r.mapModelToDomain(identityDB), nil
+}
+
+func (r identityRepo) FindByProviderSubject(provider, subject string) (domain.Identity, error) {
+	var identityDB identity
+	err := r.coll.Find(db.Cond{
+		"provider": provider,
+		"subject":  subject,
+	}).One(&identityDB)
+	if err != nil {
+		return domain.Identity{}, fmt.Errorf("identity repository find by provider subject: %w", err)
+	}
+	return r.mapModelToDomain(identityDB), nil
+}
+
+func (r identityRepo) FindByUser(userID int64) ([]domain.Identity, error) {
+	var identitiesDB []identity
+	err := r.coll.Find(db.Cond{"user_id": userID}).OrderBy("created_date").All(&identitiesDB)
+	if err != nil {
+		return nil, fmt.Errorf("identity repository find by user: %w", err)
+	}
+	identities := make([]domain.Identity, 0, len(identitiesDB))
+	for _, i := range identitiesDB {
+		identities = append(identities, r.mapModelToDomain(i))
+	}
+	return identities, nil
+}
+
+func (r identityRepo) mapDomainToModel(d domain.Identity) identity {
+	return identity{
+		ID:       d.ID,
+		UserID:   d.UserID,
+		Provider: d.Provider,
+		Subject:  d.Subject,
+		Email:    strings.ToLower(d.Email),
+	}
+}
+
+func (r identityRepo) mapModelToDomain(d identity) domain.Identity {
+	return domain.Identity{
+		ID:          d.ID,
+		UserID:      d.UserID,
+		Provider:    d.Provider,
+		Subject:     d.Subject,
+		Email:       d.Email,
+		CreatedDate: d.CreatedDate,
+	}
+}
@@ -0,0 +1,122 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APITokenRepo is an autogenerated mock type for the APITokenRepo type
+type APITokenRepo struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: id, userID
+func (_m *APITokenRepo) Delete(id int64, userID int64) error {
+	ret := _m.Called(id, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, int64) error); ok {
+		r0 = rf(id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByHash provides a mock function with given fields: hash
+func (_m *APITokenRepo) FindByHash(hash string) (domain.APIToken, error) {
+	ret := _m.Called(hash)
+
+	var r0 domain.APIToken
+	if rf, ok := ret.Get(0).(func(string) domain.APIToken); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Get(0).(domain.APIToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByUser provides a mock function with given fields: userID
+func (_m *APITokenRepo) FindByUser(userID int64) ([]domain.APIToken, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.APIToken
+	if rf, ok := ret.Get(0).(func(int64) []domain.APIToken); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.APIToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: token
+func (_m *APITokenRepo) Save(token domain.APIToken) (domain.APIToken, error) {
+	ret := _m.Called(token)
+
+	var r0 domain.APIToken
+	if rf, ok := ret.Get(0).(func(domain.APIToken) domain.APIToken); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(domain.APIToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.APIToken) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Touch provides a mock function with given fields: id
+func (_m *APITokenRepo) Touch(id int64) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAPITokenRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPITokenRepo creates a new instance of APITokenRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPITokenRepo(t mockConstructorTestingTNewAPITokenRepo) *APITokenRepo {
+	mock := &APITokenRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,39 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// AccountRepo is an autogenerated mock type for the AccountRepo type
+type AccountRepo struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: userID, deletePosts, deleteComments
+func (_m *AccountRepo) Delete(userID int64, deletePosts bool, deleteComments bool) error {
+	ret := _m.Called(userID, deletePosts, deleteComments)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, bool, bool) error); ok {
+		r0 = rf(userID, deletePosts, deleteComments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAccountRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAccountRepo creates a new instance of AccountRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAccountRepo(t mockConstructorTestingTNewAccountRepo) *AccountRepo {
+	mock := &AccountRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
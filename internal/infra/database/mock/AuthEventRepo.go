@@ -0,0 +1,73 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuthEventRepo is an autogenerated mock type for the AuthEventRepo type
+type AuthEventRepo struct {
+	mock.Mock
+}
+
+// Find provides a mock function with given fields: filter
+func (_m *AuthEventRepo) Find(filter domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	ret := _m.Called(filter)
+
+	var r0 []domain.AuthEvent
+	if rf, ok := ret.Get(0).(func(domain.AuthEventFilter) []domain.AuthEvent); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuthEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.AuthEventFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: event
+func (_m *AuthEventRepo) Save(event domain.AuthEvent) (domain.AuthEvent, error) {
+	ret := _m.Called(event)
+
+	var r0 domain.AuthEvent
+	if rf, ok := ret.Get(0).(func(domain.AuthEvent) domain.AuthEvent); ok {
+		r0 = rf(event)
+	} else {
+		r0 = ret.Get(0).(domain.AuthEvent)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.AuthEvent) error); ok {
+		r1 = rf(event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAuthEventRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuthEventRepo creates a new instance of AuthEventRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuthEventRepo(t mockConstructorTestingTNewAuthEventRepo) *AuthEventRepo {
+	mock := &AuthEventRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
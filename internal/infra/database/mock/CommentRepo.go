@@ -71,6 +71,29 @@ func (_m *CommentRepo) GetCommentsByPostID(postID int64, offset int) ([]domain.C
 	return r0, r1
 }
 
+// GetCommentsByUser provides a mock function with given fields: userID
+func (_m *CommentRepo) GetCommentsByUser(userID int64) ([]domain.Comment, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.Comment
+	if rf, ok := ret.Get(0).(func(int64) []domain.Comment); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveComment provides a mock function with given fields: comment
 func (_m *CommentRepo) SaveComment(comment domain.Comment) (domain.Comment, error) {
 	ret := _m.Called(comment)
@@ -0,0 +1,94 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IdentityRepo is an autogenerated mock type for the IdentityRepo type
+type IdentityRepo struct {
+	mock.Mock
+}
+
+// FindByProviderSubject provides a mock function with given fields: provider, subject
+func (_m *IdentityRepo) FindByProviderSubject(provider string, subject string) (domain.Identity, error) {
+	ret := _m.Called(provider, subject)
+
+	var r0 domain.Identity
+	if rf, ok := ret.Get(0).(func(string, string) domain.Identity); ok {
+		r0 = rf(provider, subject)
+	} else {
+		r0 = ret.Get(0).(domain.Identity)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByUser provides a mock function with given fields: userID
+func (_m *IdentityRepo) FindByUser(userID int64) ([]domain.Identity, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.Identity
+	if rf, ok := ret.Get(0).(func(int64) []domain.Identity); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Identity)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: identity
+func (_m *IdentityRepo) Save(identity domain.Identity) (domain.Identity, error) {
+	ret := _m.Called(identity)
+
+	var r0 domain.Identity
+	if rf, ok := ret.Get(0).(func(domain.Identity) domain.Identity); ok {
+		r0 = rf(identity)
+	} else {
+		r0 = ret.Get(0).(domain.Identity)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.Identity) error); ok {
+		r1 = rf(identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewIdentityRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewIdentityRepo creates a new instance of IdentityRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewIdentityRepo(t mockConstructorTestingTNewIdentityRepo) *IdentityRepo {
+	mock := &IdentityRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
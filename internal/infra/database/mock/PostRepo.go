@@ -1,8 +1,9 @@
-// Code generated by mockery v2.14.0. DO NOT EDIT.
+// Code generated by mockery v2.15.0. DO NOT EDIT.
 
 package mocks
 
 import (
+	time "time"
 	domain "trainee/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -27,6 +28,36 @@ func (_m *PostRepo) DeletePost(id int64) error {
 	return r0
 }
 
+// FindPosts provides a mock function with given fields: filter
+func (_m *PostRepo) FindPosts(filter domain.PostFilter) ([]domain.Post, uint64, error) {
+	ret := _m.Called(filter)
+
+	var r0 []domain.Post
+	if rf, ok := ret.Get(0).(func(domain.PostFilter) []domain.Post); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Post)
+		}
+	}
+
+	var r1 uint64
+	if rf, ok := ret.Get(1).(func(domain.PostFilter) uint64); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(domain.PostFilter) error); ok {
+		r2 = rf(filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetPost provides a mock function with given fields: id
 func (_m *PostRepo) GetPost(id int64) (domain.Post, error) {
 	ret := _m.Called(id)
@@ -71,6 +102,27 @@ func (_m *PostRepo) GetPostsByUser(userID int64) ([]domain.Post, error) {
 	return r0, r1
 }
 
+// PublishDue provides a mock function with given fields: now
+func (_m *PostRepo) PublishDue(now time.Time) (int64, error) {
+	ret := _m.Called(now)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SavePost provides a mock function with given fields: post
 func (_m *PostRepo) SavePost(post domain.Post) (domain.Post, error) {
 	ret := _m.Called(post)
@@ -92,20 +144,20 @@ func (_m *PostRepo) SavePost(post domain.Post) (domain.Post, error) {
 	return r0, r1
 }
 
-// UpdatePost provides a mock function with given fields: post
-func (_m *PostRepo) UpdatePost(post domain.Post) (domain.Post, error) {
-	ret := _m.Called(post)
+// UpdatePost provides a mock function with given fields: post, editorID
+func (_m *PostRepo) UpdatePost(post domain.Post, editorID int64) (domain.Post, error) {
+	ret := _m.Called(post, editorID)
 
 	var r0 domain.Post
-	if rf, ok := ret.Get(0).(func(domain.Post) domain.Post); ok {
-		r0 = rf(post)
+	if rf, ok := ret.Get(0).(func(domain.Post, int64) domain.Post); ok {
+		r0 = rf(post, editorID)
 	} else {
 		r0 = ret.Get(0).(domain.Post)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(domain.Post) error); ok {
-		r1 = rf(post)
+	if rf, ok := ret.Get(1).(func(domain.Post, int64) error); ok {
+		r1 = rf(post, editorID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -113,6 +165,20 @@ func (_m *PostRepo) UpdatePost(post domain.Post) (domain.Post, error) {
 	return r0, r1
 }
 
+// UpdateStatus provides a mock function with given fields: post
+func (_m *PostRepo) UpdateStatus(post domain.Post) error {
+	ret := _m.Called(post)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.Post) error); ok {
+		r0 = rf(post)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPostRepo interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,96 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PostRevisionRepo is an autogenerated mock type for the PostRevisionRepo type
+type PostRevisionRepo struct {
+	mock.Mock
+}
+
+// FindRevisions provides a mock function with given fields: postID
+func (_m *PostRevisionRepo) FindRevisions(postID int64) ([]domain.PostRevision, error) {
+	ret := _m.Called(postID)
+
+	var r0 []domain.PostRevision
+	if rf, ok := ret.Get(0).(func(int64) []domain.PostRevision); ok {
+		r0 = rf(postID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PostRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRevisionsByEditor provides a mock function with given fields: editorID
+func (_m *PostRevisionRepo) FindRevisionsByEditor(editorID int64) ([]domain.PostRevision, error) {
+	ret := _m.Called(editorID)
+
+	var r0 []domain.PostRevision
+	if rf, ok := ret.Get(0).(func(int64) []domain.PostRevision); ok {
+		r0 = rf(editorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PostRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(editorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevision provides a mock function with given fields: postID, revision
+func (_m *PostRevisionRepo) GetRevision(postID int64, revision int) (domain.PostRevision, error) {
+	ret := _m.Called(postID, revision)
+
+	var r0 domain.PostRevision
+	if rf, ok := ret.Get(0).(func(int64, int) domain.PostRevision); ok {
+		r0 = rf(postID, revision)
+	} else {
+		r0 = ret.Get(0).(domain.PostRevision)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int) error); ok {
+		r1 = rf(postID, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewPostRevisionRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPostRevisionRepo creates a new instance of PostRevisionRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPostRevisionRepo(t mockConstructorTestingTNewPostRevisionRepo) *PostRevisionRepo {
+	mock := &PostRevisionRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
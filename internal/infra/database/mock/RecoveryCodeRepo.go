@@ -0,0 +1,67 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// RecoveryCodeRepo is an autogenerated mock type for the RecoveryCodeRepo type
+type RecoveryCodeRepo struct {
+	mock.Mock
+}
+
+// DeleteAll provides a mock function with given fields: userID
+func (_m *RecoveryCodeRepo) DeleteAll(userID int64) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Replace provides a mock function with given fields: userID, hashes
+func (_m *RecoveryCodeRepo) Replace(userID int64, hashes []string) error {
+	ret := _m.Called(userID, hashes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, []string) error); ok {
+		r0 = rf(userID, hashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Use provides a mock function with given fields: userID, hash
+func (_m *RecoveryCodeRepo) Use(userID int64, hash string) error {
+	ret := _m.Called(userID, hash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string) error); ok {
+		r0 = rf(userID, hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewRecoveryCodeRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRecoveryCodeRepo creates a new instance of RecoveryCodeRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRecoveryCodeRepo(t mockConstructorTestingTNewRecoveryCodeRepo) *RecoveryCodeRepo {
+	mock := &RecoveryCodeRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
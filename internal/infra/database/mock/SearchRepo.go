@@ -0,0 +1,52 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SearchRepo is an autogenerated mock type for the SearchRepo type
+type SearchRepo struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: query
+func (_m *SearchRepo) Search(query domain.SearchQuery) ([]domain.SearchResult, error) {
+	ret := _m.Called(query)
+
+	var r0 []domain.SearchResult
+	if rf, ok := ret.Get(0).(func(domain.SearchQuery) []domain.SearchResult); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.SearchResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.SearchQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewSearchRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSearchRepo creates a new instance of SearchRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSearchRepo(t mockConstructorTestingTNewSearchRepo) *SearchRepo {
+	mock := &SearchRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,115 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TagRepo is an autogenerated mock type for the TagRepo type
+type TagRepo struct {
+	mock.Mock
+}
+
+// FindTags provides a mock function with given fields:
+func (_m *TagRepo) FindTags() ([]domain.Tag, error) {
+	ret := _m.Called()
+
+	var r0 []domain.Tag
+	if rf, ok := ret.Get(0).(func() []domain.Tag); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Tag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTag provides a mock function with given fields: id
+func (_m *TagRepo) GetTag(id int64) (domain.Tag, error) {
+	ret := _m.Called(id)
+
+	var r0 domain.Tag
+	if rf, ok := ret.Get(0).(func(int64) domain.Tag); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(domain.Tag)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MergeTags provides a mock function with given fields: fromID, intoID
+func (_m *TagRepo) MergeTags(fromID int64, intoID int64) (domain.Tag, error) {
+	ret := _m.Called(fromID, intoID)
+
+	var r0 domain.Tag
+	if rf, ok := ret.Get(0).(func(int64, int64) domain.Tag); ok {
+		r0 = rf(fromID, intoID)
+	} else {
+		r0 = ret.Get(0).(domain.Tag)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(fromID, intoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenameTag provides a mock function with given fields: id, name
+func (_m *TagRepo) RenameTag(id int64, name string) (domain.Tag, error) {
+	ret := _m.Called(id, name)
+
+	var r0 domain.Tag
+	if rf, ok := ret.Get(0).(func(int64, string) domain.Tag); ok {
+		r0 = rf(id, name)
+	} else {
+		r0 = ret.Get(0).(domain.Tag)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
+		r1 = rf(id, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTagRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTagRepo creates a new instance of TagRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTagRepo(t mockConstructorTestingTNewTagRepo) *TagRepo {
+	mock := &TagRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
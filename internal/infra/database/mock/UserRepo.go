@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	time "time"
 	domain "trainee/internal/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -90,6 +91,55 @@ func (_m *UserRepo) Save(user domain.User) (domain.User, error) {
 	return r0, r1
 }
 
+// Update provides a mock function with given fields: user
+func (_m *UserRepo) Update(user domain.User) (domain.User, error) {
+	ret := _m.Called(user)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(domain.User) domain.User); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateMFA provides a mock function with given fields: id, secret, enabledAt
+func (_m *UserRepo) UpdateMFA(id int64, secret string, enabledAt *time.Time) error {
+	ret := _m.Called(id, secret, enabledAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string, *time.Time) error); ok {
+		r0 = rf(id, secret, enabledAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseMFACounter provides a mock function with given fields: id, counter
+func (_m *UserRepo) UseMFACounter(id int64, counter int64) error {
+	ret := _m.Called(id, counter)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, int64) error); ok {
+		r0 = rf(id, counter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewUserRepo interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,99 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserTokenRepo is an autogenerated mock type for the UserTokenRepo type
+type UserTokenRepo struct {
+	mock.Mock
+}
+
+// FindByHash provides a mock function with given fields: purpose, hash
+func (_m *UserTokenRepo) FindByHash(purpose domain.TokenPurpose, hash string) (domain.UserToken, error) {
+	ret := _m.Called(purpose, hash)
+
+	var r0 domain.UserToken
+	if rf, ok := ret.Get(0).(func(domain.TokenPurpose, string) domain.UserToken); ok {
+		r0 = rf(purpose, hash)
+	} else {
+		r0 = ret.Get(0).(domain.UserToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.TokenPurpose, string) error); ok {
+		r1 = rf(purpose, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: token
+func (_m *UserTokenRepo) Save(token domain.UserToken) (domain.UserToken, error) {
+	ret := _m.Called(token)
+
+	var r0 domain.UserToken
+	if rf, ok := ret.Get(0).(func(domain.UserToken) domain.UserToken); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(domain.UserToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.UserToken) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Use provides a mock function with given fields: id
+func (_m *UserTokenRepo) Use(id int64) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseAll provides a mock function with given fields: userID, purpose
+func (_m *UserTokenRepo) UseAll(userID int64, purpose domain.TokenPurpose) error {
+	ret := _m.Called(userID, purpose)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, domain.TokenPurpose) error); ok {
+		r0 = rf(userID, purpose)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewUserTokenRepo interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewUserTokenRepo creates a new instance of UserTokenRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewUserTokenRepo(t mockConstructorTestingTNewUserTokenRepo) *UserTokenRepo {
+	mock := &UserTokenRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
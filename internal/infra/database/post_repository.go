@@ -17,6 +17,11 @@ type posts struct {
 	CreatedDate time.Time  `db:"created_date,omitempty"`
 	UpdatedDate time.Time  `db:"updated_date"`
 	DeletedDate *time.Time `db:"deleted_date,omitempty"`
+	Status      string     `db:"status"`
+	// PublishedDate is when the post got published, or is going to be when it is scheduled
+	PublishedDate *time.Time `db:"published_date"`
+	// Language is the text search configuration of the post, it is only set on insert
+	Language string `db:"language,omitempty"`
 }
 
 //go:generate mockery --dir . --name PostRepo --output ./mock
@@ -24,29 +29,50 @@ type PostRepo interface {
 	SavePost(post domain.Post) (domain.Post, error)
 	GetPost(id int64) (domain.Post, error)
 	GetPostsByUser(userID int64) ([]domain.Post, error)
-	UpdatePost(post domain.Post) (domain.Post, error)
+	FindPosts(filter domain.PostFilter) ([]domain.Post, uint64, error)
+	PublishDue(now time.Time) (int64, error)
+	UpdatePost(post domain.Post, editorID int64) (domain.Post, error)
+	UpdateStatus(post domain.Post) error
 	DeletePost(id int64) error
 }
 
 type postsRepository struct {
-	coll db.Collection
+	sess           db.Session
+	coll           db.Collection
+	searchLanguage string
 }
 
-func NewPostRepository(dbSession db.Session) PostRepo {
+func NewPostRepository(dbSession db.Session, searchLanguage string) PostRepo {
 	return postsRepository{
-		coll: dbSession.Collection(PostTable),
+		sess:           dbSession,
+		coll:           dbSession.Collection(PostTable),
+		searchLanguage: searchLanguage,
 	}
 }
 
+// SavePost stores the post along with its tags, as its first revision.
 func (r postsRepository) SavePost(post domain.Post) (domain.Post, error) {
 	postDB := r.mapPostDBModel(post)
 	postDB.CreatedDate = time.Now()
 	postDB.UpdatedDate = time.Now()
-	err := r.coll.InsertReturning(&postDB)
+	postDB.Language = r.searchLanguage
+	err := r.sess.Tx(func(tx db.Session) error {
+		err := tx.Collection(PostTable).InsertReturning(&postDB)
+		if err != nil {
+			return err
+		}
+		err = saveRevision(tx, postDB, postDB.UserID, postDB.CreatedDate)
+		if err != nil {
+			return err
+		}
+		return setPostTags(tx, postDB.ID, post.Tags)
+	})
 	if err != nil {
 		return domain.Post{}, fmt.Errorf("post repository save post: %w", err)
 	}
-	return r.mapPostDbModelToDomain(postDB), nil
+	saved := r.mapPostDbModelToDomain(postDB)
+	saved.Tags = post.Tags
+	return saved, nil
 }
 
 func (r postsRepository) GetPost(id int64) (domain.Post, error) {
@@ -59,19 +85,49 @@ func (r postsRepository) GetPost(id int64) (domain.Post, error) {
 	if err != nil {
 		return domain.Post{}, fmt.Errorf("post repository get post: %w", err)
 	}
-	return r.mapPostDbModelToDomain(post), nil
+	result, err := r.withTags([]posts{post})
+	if err != nil {
+		return domain.Post{}, fmt.Errorf("post repository get post: %w", err)
+	}
+	return result[0], nil
 }
 
-func (r postsRepository) UpdatePost(post domain.Post) (domain.Post, error) {
+// UpdatePost saves the post as a new revision by the editor, its tags are replaced with post.Tags.
+func (r postsRepository) UpdatePost(post domain.Post, editorID int64) (domain.Post, error) {
 	updatePost := r.mapPostDBModel(post)
 	updatePost.UpdatedDate = time.Now()
-	err := r.coll.Find(db.Cond{
-		"id": updatePost.ID,
-	}).Update(&updatePost)
+	err := r.sess.Tx(func(tx db.Session) error {
+		err := tx.Collection(PostTable).Find(db.Cond{
+			"id": updatePost.ID,
+		}).Update(&updatePost)
+		if err != nil {
+			return err
+		}
+		err = saveRevision(tx, updatePost, editorID, updatePost.UpdatedDate)
+		if err != nil {
+			return err
+		}
+		return setPostTags(tx, updatePost.ID, post.Tags)
+	})
 	if err != nil {
 		return domain.Post{}, fmt.Errorf("post repository update post: %w", err)
 	}
-	return r.mapPostDbModelToDomain(updatePost), err
+	updated := r.mapPostDbModelToDomain(updatePost)
+	updated.CreatedDate = post.CreatedDate
+	updated.Tags = post.Tags
+	return updated, err
+}
+
+// UpdateStatus saves the status and publish date of the post, leaving its content and revisions as they are.
+func (r postsRepository) UpdateStatus(post domain.Post) error {
+	err := r.coll.Find(db.Cond{"id": post.ID}).Update(map[string]interface{}{
+		"status":         post.Status,
+		"published_date": post.PublishedDate,
+	})
+	if err != nil {
+		return fmt.Errorf("post repository update status: %w", err)
+	}
+	return nil
 }
 
 func (r postsRepository) DeletePost(id int64) error {
@@ -88,40 +144,136 @@ func (r postsRepository) DeletePost(id int64) error {
 func (r postsRepository) GetPostsByUser(userID int64) ([]domain.Post, error) {
 	var post []posts
 
-	err := r.coll.Find(db.Cond{"user_id": userID}).All(&post)
+	err := r.coll.Find(db.Cond{"user_id": userID, "deleted_date": nil}).All(&post)
+	if err != nil {
+		return []domain.Post{}, fmt.Errorf("post repository get post by user: %w", err)
+	}
+	result, err := r.withTags(post)
 	if err != nil {
 		return []domain.Post{}, fmt.Errorf("post repository get post by user: %w", err)
 	}
-	return r.mapPostCollection(post), nil
+	return result, nil
+}
+
+// FindPosts pages through the feed by keyset on the sort date and id, so pages stay stable while posts are added.
+// The total ignores the cursor.
+func (r postsRepository) FindPosts(filter domain.PostFilter) ([]domain.Post, uint64, error) {
+	column := "created_date"
+	if filter.Sort == domain.PostSortUpdated {
+		column = "updated_date"
+	}
+	cond := db.Cond{"deleted_date": nil}
+	if filter.UserID != 0 {
+		cond["user_id"] = filter.UserID
+	}
+	if !filter.From.IsZero() {
+		cond[column+" >="] = filter.From
+	}
+	if !filter.To.IsZero() {
+		cond[column+" <"] = filter.To
+	}
+	if len(filter.Tags) != 0 {
+		cond["id IN"] = db.Raw(`(select pt.post_id from post_tags pt join tags t on t.id = pt.tag_id
+			where t.name in ? group by pt.post_id having count(*) = ?)`, filter.Tags, len(filter.Tags))
+	}
+	if filter.Status != "" {
+		cond["status"] = filter.Status
+		if filter.Status != domain.PostStatusPublished {
+			cond["user_id"] = filter.ViewerID
+		}
+	}
+	where := db.And(cond)
+	if filter.Status == "" {
+		where = where.And(db.Or(
+			db.Cond{"status": domain.PostStatusPublished},
+			db.Cond{"user_id": filter.ViewerID, "status IN": []domain.PostStatus{domain.PostStatusDraft, domain.PostStatusScheduled}},
+		))
+	}
+	total, err := r.coll.Find(where).Count()
+	if err != nil {
+		return nil, 0, fmt.Errorf("post repository find posts: %w", err)
+	}
+
+	op, order := "<", []interface{}{"-" + column, "-id"}
+	if filter.Ascending {
+		op, order = ">", []interface{}{column, "id"}
+	}
+	page := where
+	if filter.After != nil {
+		page = page.And(db.Or(
+			db.Cond{column + " " + op: filter.After.Date},
+			db.Cond{column: filter.After.Date, "id " + op: filter.After.ID},
+		))
+	}
+	var postsDB []posts
+	err = r.coll.Find(page).OrderBy(order...).Limit(filter.Limit).All(&postsDB)
+	if err != nil {
+		return nil, 0, fmt.Errorf("post repository find posts: %w", err)
+	}
+	result, err := r.withTags(postsDB)
+	if err != nil {
+		return nil, 0, fmt.Errorf("post repository find posts: %w", err)
+	}
+	return result, total, nil
+}
+
+// PublishDue publishes the scheduled posts whose time has come, and tells how many there were.
+func (r postsRepository) PublishDue(now time.Time) (int64, error) {
+	res, err := r.sess.SQL().
+		Update(PostTable).
+		Set("status", domain.PostStatusPublished).
+		Where(db.Cond{"status": domain.PostStatusScheduled, "published_date <=": now, "deleted_date": nil}).
+		Exec()
+	if err != nil {
+		return 0, fmt.Errorf("post repository publish due posts: %w", err)
+	}
+	published, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("post repository publish due posts: %w", err)
+	}
+	return published, nil
+}
 
+// withTags maps the posts to the domain along with their tags.
+func (r postsRepository) withTags(postsDB []posts) ([]domain.Post, error) {
+	ids := make([]int64, 0, len(postsDB))
+	for _, p := range postsDB {
+		ids = append(ids, p.ID)
+	}
+	tags, err := findPostTags(r.sess, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]domain.Post, 0, len(postsDB))
+	for _, p := range postsDB {
+		post := r.mapPostDbModelToDomain(p)
+		post.Tags = tags[p.ID]
+		result = append(result, post)
+	}
+	return result, nil
 }
 
 func (r postsRepository) mapPostDBModel(p domain.Post) posts {
 	return posts{
-		UserID: p.UserID,
-		ID:     p.ID,
-		Title:  p.Title,
-		Body:   p.Body,
+		UserID:        p.UserID,
+		ID:            p.ID,
+		Title:         p.Title,
+		Body:          p.Body,
+		Status:        string(p.Status),
+		PublishedDate: p.PublishedDate,
 	}
 }
 
 func (r postsRepository) mapPostDbModelToDomain(p posts) domain.Post {
 	return domain.Post{
-		UserID:      p.UserID,
-		ID:          p.ID,
-		Title:       p.Title,
-		Body:        p.Body,
-		CreatedDate: p.CreatedDate,
-		UpdatedDate: p.UpdatedDate,
-		DeletedDate: p.DeletedDate,
-	}
-}
-
-func (r postsRepository) mapPostCollection(post []posts) []domain.Post {
-	var result []domain.Post
-	for _, coll := range post {
-		newPost := r.mapPostDbModelToDomain(coll)
-		result = append(result, newPost)
+		UserID:        p.UserID,
+		ID:            p.ID,
+		Title:         p.Title,
+		Body:          p.Body,
+		CreatedDate:   p.CreatedDate,
+		UpdatedDate:   p.UpdatedDate,
+		DeletedDate:   p.DeletedDate,
+		Status:        domain.PostStatus(p.Status),
+		PublishedDate: p.PublishedDate,
 	}
-	return result
 }
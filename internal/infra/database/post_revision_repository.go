@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+	"github.com/upper/db/v4"
+	"time"
+	"trainee/internal/domain"
+)
+
+const PostRevisionTable = "post_revisions"
+
+type postRevision struct {
+	ID          int64     `db:"id,omitempty"`
+	PostID      int64     `db:"post_id"`
+	Revision    int       `db:"revision"`
+	EditorID    int64     `db:"editor_id"`
+	Title       string    `db:"title"`
+	Body        string    `db:"body"`
+	CreatedDate time.Time `db:"created_date"`
+}
+
+//go:generate mockery --dir . --name PostRevisionRepo --output ./mock
+type PostRevisionRepo interface {
+	FindRevisions(postID int64) ([]domain.PostRevision, error)
+	GetRevision(postID int64, revision int) (domain.PostRevision, error)
+	FindRevisionsByEditor(editorID int64) ([]domain.PostRevision, error)
+}
+
+type postRevisionRepo struct {
+	coll db.Collection
+}
+
+func NewPostRevisionRepo(dbSession db.Session) PostRevisionRepo {
+	return postRevisionRepo{
+		coll: dbSession.Collection(PostRevisionTable),
+	}
+}
+
+// FindRevisions lists the revisions of the post, the latest first.
+func (r postRevisionRepo) FindRevisions(postID int64) ([]domain.PostRevision, error) {
+	var revisionsDB []postRevision
+	err := r.coll.Find(db.Cond{"post_id": postID}).OrderBy("-revision").All(&revisionsDB)
+	if err != nil {
+		return nil, fmt.Errorf("post revision repository find revisions: %w", err)
+	}
+	revisions := make([]domain.PostRevision, 0, len(revisionsDB))
+	for _, rev := range revisionsDB {
+		revisions = append(revisions, r.mapModelToDomain(rev))
+	}
+	return revisions, nil
+}
+
+func (r postRevisionRepo) GetRevision(postID int64, revision int) (domain.PostRevision, error) {
+	var rev postRevision
+	err := r.coll.Find(db.Cond{"post_id": postID, "revision": revision}).One(&rev)
+	if err != nil {
+		return domain.PostRevision{}, fmt.Errorf("post revision repository get revision: %w", err)
+	}
+	return r.mapModelToDomain(rev), nil
+}
+
+// FindRevisionsByEditor lists the revisions the user wrote on any post, oldest first.
+func (r postRevisionRepo) FindRevisionsByEditor(editorID int64) ([]domain.PostRevision, error) {
+	var revisionsDB []postRevision
+	err := r.coll.Find(db.Cond{"editor_id": editorID}).OrderBy("id").All(&revisionsDB)
+	if err != nil {
+		return nil, fmt.Errorf("post revision repository find revisions by editor: %w", err)
+	}
+	revisions := make([]domain.PostRevision, 0, len(revisionsDB))
+	for _, rev := range revisionsDB {
+		revisions = append(revisions, r.mapModelToDomain(rev))
+	}
+	return revisions, nil
+}
+
+// saveRevision adds the title and body the post has now as its next revision. It is called after the post row is
+// written in the same transaction, the row lock keeps concurrent edits from taking the same number.
+func saveRevision(sess db.Session, post posts, editorID int64, now time.Time) error {
+	row, err := sess.SQL().QueryRow("select coalesce(max(revision), 0) + 1 from post_revisions where post_id = ?", post.ID)
+	if err != nil {
+		return err
+	}
+	var next int
+	err = row.Scan(&next)
+	if err != nil {
+		return err
+	}
+	_, err = sess.Collection(PostRevisionTable).Insert(postRevision{
+		PostID:      post.ID,
+		Revision:    next,
+		EditorID:    editorID,
+		Title:       post.Title,
+		Body:        post.Body,
+		CreatedDate: now,
+	})
+	return err
+}
+
+func (r postRevisionRepo) mapModelToDomain(m postRevision) domain.PostRevision {
+	return domain.PostRevision{
+		PostID:      m.PostID,
+		Revision:    m.Revision,
+		EditorID:    m.EditorID,
+		Title:       m.Title,
+		Body:        m.Body,
+		CreatedDate: m.CreatedDate,
+	}
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"github.com/upper/db/v4"
+	"time"
+)
+
+const RecoveryCodeTable = "mfa_recovery_codes"
+
+var ErrRecoveryCodeInvalid = errors.New("recovery code is unknown or already used")
+
+type recoveryCode struct {
+	ID          int64      `db:"id,omitempty"`
+	UserID      int64      `db:"user_id"`
+	Hash        string     `db:"code_hash"`
+	UsedDate    *time.Time `db:"used_date,omitempty"`
+	CreatedDate time.Time  `db:"created_date,omitempty"`
+}
+
+//go:generate mockery --dir . --name RecoveryCodeRepo --output ./mock
+type RecoveryCodeRepo interface {
+	Replace(userID int64, hashes []string) error
+	Use(userID int64, hash string) error
+	DeleteAll(userID int64) error
+}
+
+type recoveryCodeRepo struct {
+	sess db.Session
+}
+
+func NewRecoveryCodeRepo(dbSession db.Session) RecoveryCodeRepo {
+	return recoveryCodeRepo{
+		sess: dbSession,
+	}
+}
+
+// Replace drops the codes the user had and stores the new ones.
+func (r recoveryCodeRepo) Replace(userID int64, hashes []string) error {
+	err := r.sess.Tx(func(tx db.Session) error {
+		coll := tx.Collection(RecoveryCodeTable)
+		err := coll.Find(db.Cond{"user_id": userID}).Delete()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, hash := range hashes {
+			_, err = coll.Insert(recoveryCode{UserID: userID, Hash: hash, CreatedDate: now})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("recovery code repository replace codes: %w", err)
+	}
+	return nil
+}
+
+func (r recoveryCodeRepo) Use(userID int64, hash string) error {
+	res, err := r.sess.SQL().
+		Update(RecoveryCodeTable).
+		Set("used_date", time.Now()).
+		Where(db.Cond{"user_id": userID, "code_hash": hash, "used_date": nil}).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("recovery code repository use code: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("recovery code repository use code: %w", err)
+	}
+	if affected != 1 {
+		return fmt.Errorf("recovery code repository use code: %w", ErrRecoveryCodeInvalid)
+	}
+	return nil
+}
+
+func (r recoveryCodeRepo) DeleteAll(userID int64) error {
+	err := r.sess.Collection(RecoveryCodeTable).Find(db.Cond{"user_id": userID}).Delete()
+	if err != nil {
+		return fmt.Errorf("recovery code repository delete codes: %w", err)
+	}
+	return nil
+}
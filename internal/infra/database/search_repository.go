@@ -0,0 +1,111 @@
+package database
+
+import (
+	"fmt"
+	"github.com/upper/db/v4"
+	"strings"
+	"time"
+	"trainee/internal/domain"
+)
+
+// SearchHitStart and SearchHitStop enclose the hits in a snippet. They are private use characters,
+// so they don't turn up in the text and survive HTML escaping.
+const (
+	SearchHitStart = "\ue000"
+	SearchHitStop  = "\ue001"
+)
+
+var headlineOptions = fmt.Sprintf(`StartSel=%s, StopSel=%s, MinWords=15, MaxWords=35, MaxFragments=2, FragmentDelimiter=" ... "`,
+	SearchHitStart, SearchHitStop)
+
+// only published posts and their comments are found, the hits are ranked and paged first, so only the returned page gets its snippets made
+const (
+	searchPostHits = `
+	select 'post' as type, p.id, p.id as post_id, p.user_id, p.title, p.body, p.language,
+		ts_rank_cd(p.search_vector, q.query) as rank, p.created_date
+	from posts p, q
+	where p.deleted_date is null and p.status = 'published' and p.search_vector @@ q.query`
+	searchCommentHits = `
+	select 'comment' as type, c.id, c.post_id, c.user_id, p.title, c.body, c.language,
+		ts_rank_cd(c.search_vector, q.query) as rank, c.created_date
+	from commentses c
+	join posts p on p.id = c.post_id and p.deleted_date is null and p.status = 'published', q
+	where c.deleted_date is null and c.search_vector @@ q.query`
+	searchQuery = `
+with q as (select websearch_to_tsquery(?::regconfig, ?) as query),
+hits as (%s
+	order by rank desc, created_date desc, id desc
+	limit ? offset ?
+)
+select hits.type, hits.id, hits.post_id, hits.user_id, hits.title, hits.rank, hits.created_date,
+	ts_headline(hits.language, hits.body, q.query, ?) as snippet
+from hits, q
+order by hits.rank desc, hits.created_date desc, hits.id desc`
+)
+
+type searchResult struct {
+	Type        string    `db:"type"`
+	ID          int64     `db:"id"`
+	PostID      int64     `db:"post_id"`
+	UserID      int64     `db:"user_id"`
+	Title       string    `db:"title"`
+	Snippet     string    `db:"snippet"`
+	Rank        float64   `db:"rank"`
+	CreatedDate time.Time `db:"created_date"`
+}
+
+//go:generate mockery --dir . --name SearchRepo --output ./mock
+type SearchRepo interface {
+	Search(query domain.SearchQuery) ([]domain.SearchResult, error)
+}
+
+type searchRepo struct {
+	sess     db.Session
+	language string
+}
+
+// NewSearchRepo parses queries with the language text search configuration, the one new posts and comments get.
+func NewSearchRepo(dbSession db.Session, language string) SearchRepo {
+	return searchRepo{
+		sess:     dbSession,
+		language: language,
+	}
+}
+
+// Search ranks the posts and comments matching the query, with snippets of their bodies.
+func (r searchRepo) Search(query domain.SearchQuery) ([]domain.SearchResult, error) {
+	var hits []string
+	if query.Type != domain.SearchResultComment {
+		hits = append(hits, searchPostHits)
+	}
+	if query.Type != domain.SearchResultPost {
+		hits = append(hits, searchCommentHits)
+	}
+	sql := fmt.Sprintf(searchQuery, strings.Join(hits, "\n\tunion all"))
+
+	var resultsDB []searchResult
+	err := r.sess.SQL().
+		Iterator(sql, r.language, query.Query, query.Limit, query.Offset, headlineOptions).
+		All(&resultsDB)
+	if err != nil {
+		return nil, fmt.Errorf("search repository search: %w", err)
+	}
+	results := make([]domain.SearchResult, 0, len(resultsDB))
+	for _, res := range resultsDB {
+		results = append(results, r.mapModelToDomain(res))
+	}
+	return results, nil
+}
+
+func (r searchRepo) mapModelToDomain(m searchResult) domain.SearchResult {
+	return domain.SearchResult{
+		Type:        domain.SearchResultType(m.Type),
+		ID:          m.ID,
+		PostID:      m.PostID,
+		UserID:      m.UserID,
+		Title:       m.Title,
+		Snippet:     m.Snippet,
+		Rank:        m.Rank,
+		CreatedDate: m.CreatedDate,
+	}
+}
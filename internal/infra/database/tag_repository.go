@@ -0,0 +1,212 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"github.com/upper/db/v4"
+	"time"
+	"trainee/internal/domain"
+)
+
+const (
+	TagTable     = "tags"
+	PostTagTable = "post_tags"
+)
+
+var ErrTagExists = errors.New("tag name is taken")
+
+const findTags = `
+select t.id, t.name, count(p.id) as post_count
+from tags t
+left join post_tags pt on pt.tag_id = t.id
+left join posts p on p.id = pt.post_id and p.deleted_date is null and p.status = 'published'
+%s
+group by t.id, t.name
+order by post_count desc, t.name`
+
+type tag struct {
+	ID          int64     `db:"id,omitempty"`
+	Name        string    `db:"name"`
+	CreatedDate time.Time `db:"created_date,omitempty"`
+}
+
+type tagCount struct {
+	ID        int64  `db:"id"`
+	Name      string `db:"name"`
+	PostCount int64  `db:"post_count"`
+}
+
+type postTag struct {
+	PostID int64  `db:"post_id"`
+	TagID  int64  `db:"tag_id,omitempty"`
+	Name   string `db:"name,omitempty"`
+}
+
+//go:generate mockery --dir . --name TagRepo --output ./mock
+type TagRepo interface {
+	FindTags() ([]domain.Tag, error)
+	GetTag(id int64) (domain.Tag, error)
+	RenameTag(id int64, name string) (domain.Tag, error)
+	MergeTags(fromID, intoID int64) (domain.Tag, error)
+}
+
+type tagRepo struct {
+	sess db.Session
+}
+
+func NewTagRepo(dbSession db.Session) TagRepo {
+	return tagRepo{
+		sess: dbSession,
+	}
+}
+
+// FindTags lists every tag, the most used first.
+func (r tagRepo) FindTags() ([]domain.Tag, error) {
+	var tagsDB []tagCount
+	err := r.sess.SQL().Iterator(fmt.Sprintf(findTags, "")).All(&tagsDB)
+	if err != nil {
+		return nil, fmt.Errorf("tag repository find tags: %w", err)
+	}
+	tags := make([]domain.Tag, 0, len(tagsDB))
+	for _, t := range tagsDB {
+		tags = append(tags, r.mapModelToDomain(t))
+	}
+	return tags, nil
+}
+
+func (r tagRepo) GetTag(id int64) (domain.Tag, error) {
+	t, err := getTag(r.sess, id)
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("tag repository get tag: %w", err)
+	}
+	return t, nil
+}
+
+// RenameTag gives the tag a name no other tag has, ErrTagExists otherwise.
+func (r tagRepo) RenameTag(id int64, name string) (domain.Tag, error) {
+	var renamed domain.Tag
+	err := r.sess.Tx(func(tx db.Session) error {
+		coll := tx.Collection(TagTable)
+		taken, err := coll.Find(db.Cond{"name": name, "id <>": id}).Exists()
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrTagExists
+		}
+		res, err := tx.SQL().Update(TagTable).Set("name", name).Where(db.Cond{"id": id}).Exec()
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected != 1 {
+			return db.ErrNoMoreRows
+		}
+		renamed, err = getTag(tx, id)
+		return err
+	})
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("tag repository rename tag: %w", err)
+	}
+	return renamed, nil
+}
+
+// MergeTags moves the posts of one tag to another and deletes the first one.
+func (r tagRepo) MergeTags(fromID, intoID int64) (domain.Tag, error) {
+	var merged domain.Tag
+	err := r.sess.Tx(func(tx db.Session) error {
+		for _, id := range []int64{fromID, intoID} {
+			exists, err := tx.Collection(TagTable).Find(db.Cond{"id": id}).Exists()
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return db.ErrNoMoreRows
+			}
+		}
+		_, err := tx.SQL().Exec(`
+			insert into post_tags (post_id, tag_id)
+			select post_id, ? from post_tags where tag_id = ?
+			on conflict do nothing`, intoID, fromID)
+		if err != nil {
+			return err
+		}
+		err = tx.Collection(TagTable).Find(db.Cond{"id": fromID}).Delete()
+		if err != nil {
+			return err
+		}
+		merged, err = getTag(tx, intoID)
+		return err
+	})
+	if err != nil {
+		return domain.Tag{}, fmt.Errorf("tag repository merge tags: %w", err)
+	}
+	return merged, nil
+}
+
+func getTag(sess db.Session, id int64) (domain.Tag, error) {
+	var tagDB tagCount
+	err := sess.SQL().Iterator(fmt.Sprintf(findTags, "where t.id = ?"), id).One(&tagDB)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+	return tagRepo{}.mapModelToDomain(tagDB), nil
+}
+
+// setPostTags makes names the tags of the post, creating the tags that don't exist yet.
+func setPostTags(sess db.Session, postID int64, names []string) error {
+	err := sess.Collection(PostTagTable).Find(db.Cond{"post_id": postID}).Delete()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		_, err = sess.SQL().Exec("insert into tags (name) values (?) on conflict (name) do nothing", name)
+		if err != nil {
+			return err
+		}
+		var t tag
+		err = sess.Collection(TagTable).Find(db.Cond{"name": name}).One(&t)
+		if err != nil {
+			return err
+		}
+		_, err = sess.Collection(PostTagTable).Insert(postTag{PostID: postID, TagID: t.ID})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findPostTags gives the tag names of each post, sorted.
+func findPostTags(sess db.Session, postIDs []int64) (map[int64][]string, error) {
+	tags := make(map[int64][]string, len(postIDs))
+	if len(postIDs) == 0 {
+		return tags, nil
+	}
+	var rows []postTag
+	err := sess.SQL().
+		Select("pt.post_id", "t.name").
+		From(PostTagTable + " as pt").
+		Join(TagTable + " as t").On("t.id = pt.tag_id").
+		Where(db.Cond{"pt.post_id IN": postIDs}).
+		OrderBy("t.name").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		tags[row.PostID] = append(tags[row.PostID], row.Name)
+	}
+	return tags, nil
+}
+
+func (r tagRepo) mapModelToDomain(m tagCount) domain.Tag {
+	return domain.Tag{
+		ID:        m.ID,
+		Name:      m.Name,
+		PostCount: m.PostCount,
+	}
+}
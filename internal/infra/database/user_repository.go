@@ -1,6 +1,7 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"github.com/upper/db/v4"
 	"strings"
@@ -11,13 +12,17 @@ import (
 const UsersTable = "users"
 
 type user struct {
-	ID          int64      `db:"id,omitempty"`
-	Email       string     `db:"email"`
-	Name        string     `db:"name"`
-	Password    string     `db:"password,omitempty"`
-	CreatedDate time.Time  `db:"created_date,omitempty"`
-	UpdatedDate time.Time  `db:"updated_date"`
-	DeletedDate *time.Time `db:"deleted_date,omitempty"`
+	ID              int64      `db:"id,omitempty"`
+	Email           string     `db:"email"`
+	Name            string     `db:"name"`
+	Password        string     `db:"password,omitempty"`
+	Role            string     `db:"role,omitempty"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at,omitempty"`
+	MFASecret       string     `db:"mfa_secret,omitempty"`
+	MFAEnabledAt    *time.Time `db:"mfa_enabled_at,omitempty"`
+	CreatedDate     time.Time  `db:"created_date,omitempty"`
+	UpdatedDate     time.Time  `db:"updated_date"`
+	DeletedDate     *time.Time `db:"deleted_date,omitempty"`
 }
 
 //go:generate mockery --dir . --name UserRepo --output ./mock
@@ -25,15 +30,22 @@ type UserRepo interface {
 	Save(user domain.User) (domain.User, error)
 	FindByEmail(email string) (domain.User, error)
 	FindByID(id int64) (domain.User, error)
+	Update(user domain.User) (domain.User, error)
 	Delete(id int64) error
+	UpdateMFA(id int64, secret string, enabledAt *time.Time) error
+	UseMFACounter(id int64, counter int64) error
 }
 
+var ErrMFACodeUsed = errors.New("mfa code is already used")
+
 type userRepo struct {
+	sess db.Session
 	coll db.Collection
 }
 
 func NewUSerRepo(dbSession db.Session) UserRepo {
 	return userRepo{
+		sess: dbSession,
 		coll: dbSession.Collection(UsersTable),
 	}
 }
@@ -75,34 +87,101 @@ func (u userRepo) FindByID(id int64) (domain.User, error) {
 	return u.mapModelToDomain(domainUser), nil
 }
 
+func (u userRepo) Update(user domain.User) (domain.User, error) {
+	updateUser := u.mapDomainToModel(user)
+	updateUser.UpdatedDate = time.Now()
+	err := u.coll.Find(db.Cond{
+		"id":           updateUser.ID,
+		"deleted_date": nil,
+	}).Update(&updateUser)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("user repository update user: %w", err)
+	}
+	return u.FindByID(user.ID)
+}
+
+// Delete soft deletes the user, an unknown or already deleted one ends in db.ErrNoMoreRows.
 func (u userRepo) Delete(id int64) error {
+	res, err := u.sess.SQL().
+		Update(UsersTable).
+		Set("deleted_date", time.Now()).
+		Where(db.Cond{"id": id, "deleted_date": nil}).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("user repository delete user: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("user repository delete user: %w", err)
+	}
+	if affected != 1 {
+		return fmt.Errorf("user repository delete user: %w", db.ErrNoMoreRows)
+	}
+	return nil
+}
+
+// UpdateMFA sets the mfa columns as given, so an empty secret and nil date turn mfa off.
+func (u userRepo) UpdateMFA(id int64, secret string, enabledAt *time.Time) error {
 	err := u.coll.Find(db.Cond{
 		"id":           id,
 		"deleted_date": nil,
-	}).Update(map[string]interface{}{"deleted_date": time.Now()})
+	}).Update(map[string]interface{}{
+		"mfa_secret":       secret,
+		"mfa_enabled_at":   enabledAt,
+		"mfa_last_counter": 0,
+		"updated_date":     time.Now(),
+	})
 	if err != nil {
-		return fmt.Errorf("user repository delete user: %w", err)
+		return fmt.Errorf("user repository update mfa: %w", err)
+	}
+	return nil
+}
+
+// UseMFACounter records the time step of an accepted code, a code of the same or an earlier step is refused.
+func (u userRepo) UseMFACounter(id int64, counter int64) error {
+	res, err := u.sess.SQL().
+		Update(UsersTable).
+		Set("mfa_last_counter", counter).
+		Where(db.Cond{"id": id, "mfa_last_counter <": counter}).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("user repository use mfa counter: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("user repository use mfa counter: %w", err)
+	}
+	if affected != 1 {
+		return fmt.Errorf("user repository use mfa counter: %w", ErrMFACodeUsed)
 	}
 	return nil
 }
 
 func (u userRepo) mapDomainToModel(d domain.User) user {
 	return user{
-		ID:       d.ID,
-		Email:    strings.ToLower(d.Email),
-		Password: d.Password,
-		Name:     d.Name,
+		ID:              d.ID,
+		Email:           strings.ToLower(d.Email),
+		Password:        d.Password,
+		Name:            d.Name,
+		Role:            string(d.Role),
+		EmailVerifiedAt: d.EmailVerifiedAt,
+		MFASecret:       d.MFASecret,
+		MFAEnabledAt:    d.MFAEnabledAt,
 	}
 }
 
 func (u userRepo) mapModelToDomain(d user) domain.User {
 	return domain.User{
-		ID:          d.ID,
-		Email:       d.Email,
-		Password:    d.Password,
-		Name:        d.Name,
-		CreatedDate: d.CreatedDate,
-		UpdatedDate: d.UpdatedDate,
-		DeletedDate: d.DeletedDate,
+		ID:              d.ID,
+		Email:           d.Email,
+		Password:        d.Password,
+		Name:            d.Name,
+		Role:            domain.Role(d.Role),
+		EmailVerifiedAt: d.EmailVerifiedAt,
+		MFASecret:       d.MFASecret,
+		MFAEnabledAt:    d.MFAEnabledAt,
+		CreatedDate:     d.CreatedDate,
+		UpdatedDate:     d.UpdatedDate,
+		DeletedDate:     d.DeletedDate,
 	}
 }
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"github.com/upper/db/v4"
+	"time"
+	"trainee/internal/domain"
+)
+
+const UserTokenTable = "user_tokens"
+
+var ErrTokenUsed = errors.New("token is already used")
+
+type userToken struct {
+	ID          int64      `db:"id,omitempty"`
+	UserID      int64      `db:"user_id"`
+	Purpose     string     `db:"purpose"`
+	Hash        string     `db:"token_hash"`
+	ExpiresDate time.Time  `db:"expires_date"`
+	UsedDate    *time.Time `db:"used_date,omitempty"`
+	CreatedDate time.Time  `db:"created_date,omitempty"`
+}
+
+//go:generate mockery --dir . --name UserTokenRepo --output ./mock
+type UserTokenRepo interface {
+	Save(token domain.UserToken) (domain.UserToken, error)
+	FindByHash(purpose domain.TokenPurpose, hash string) (domain.UserToken, error)
+	Use(id int64) error
+	UseAll(userID int64, purpose domain.TokenPurpose) error
+}
+
+type userTokenRepo struct {
+	sess db.Session
+	coll db.Collection
+}
+
+func NewUserTokenRepo(dbSession db.Session) UserTokenRepo {
+	return userTokenRepo{
+		sess: dbSession,
+		coll: dbSession.Collection(UserTokenTable),
+	}
+}
+
+func (r userTokenRepo) Save(token domain.UserToken) (domain.UserToken, error) {
+	tokenDB := r.mapDomainToModel(token)
+	tokenDB.CreatedDate = time.Now()
+	err := r.coll.InsertReturning(&tokenDB)
+	if err != nil {
+		return domain.UserToken{}, fmt.Errorf("user token repository save token: %w", err)
+	}
+	return r.mapModelToDomain(tokenDB), nil
+}
+
+func (r userTokenRepo) FindByHash(purpose domain.TokenPurpose, hash string) (domain.UserToken, error) {
+	var tokenDB userToken
+	err := r.coll.Find(db.Cond{
+		"purpose":    string(purpose),
+		"token_hash": hash,
+	}).One(&tokenDB)
+	if err != nil {
+		return domain.UserToken{}, fmt.Errorf("user token repository find by hash: %w", err)
+	}
+	return r.mapModelToDomain(tokenDB), nil
+}
+
+// Use marks the token as used. Only one of concurrent callers succeeds, the others get ErrTokenUsed.
+func (r userTokenRepo) Use(id int64) error {
+	res, err := r.sess.SQL().
+		Update(UserTokenTable).
+		Set("used_date", time.Now()).
+		Where(db.Cond{"id": id, "used_date": nil}).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("user token repository use token: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("user token repository use token: %w", err)
+	}
+	if affected != 1 {
+		return fmt.Errorf("user token repository use token: %w", ErrTokenUsed)
+	}
+	return nil
+}
+
+func (r userTokenRepo) UseAll(userID int64, purpose domain.TokenPurpose) error {
+	err := r.coll.Find(db.Cond{
+		"user_id":   userID,
+		"purpose":   string(purpose),
+		"used_date": nil,
+	}).Update(map[string]interface{}{"used_date": time.Now()})
+	if err != nil {
+		return fmt.Errorf("user token repository use all tokens: %w", err)
+	}
+	return nil
+}
+
+func (r userTokenRepo) mapDomainToModel(d domain.UserToken) userToken {
+	return userToken{
+		ID:          d.ID,
+		UserID:      d.UserID,
+		Purpose:     string(d.Purpose),
+		Hash:        d.Hash,
+		ExpiresDate: d.ExpiresDate,
+		UsedDate:    d.UsedDate,
+	}
+}
+
+func (r userTokenRepo) mapModelToDomain(d userToken) domain.UserToken {
+	return domain.UserToken{
+		ID:          d.ID,
+		UserID:      d.UserID,
+		Purpose:     domain.TokenPurpose(d.Purpose),
+		Hash:        d.Hash,
+		ExpiresDate: d.ExpiresDate,
+		UsedDate:    d.UsedDate,
+		CreatedDate: d.CreatedDate,
+	}
+}
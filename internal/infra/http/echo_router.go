@@ -1,44 +1,120 @@
 package http
 
 import (
+	"github.com/labstack/echo/v4"
 	MW "github.com/labstack/echo/v4/middleware"
 	"github.com/swaggo/echo-swagger"
 	"trainee/config"
 	"trainee/config/container"
 	_ "trainee/docs"
+	"trainee/internal/domain"
 	"trainee/internal/infra/http/validators"
 )
 
 func EchoRouter(s *Server, cont container.Container) {
 
 	e := s.Echo
-	e.GET("/auth/google/login", cont.OauthHandler.GetInfo)
-	e.GET("/auth/google/callback", cont.OauthHandler.CallBackRegister)
+	e.GET("/auth/:provider/login", cont.OauthHandler.GetInfo)
+	e.GET("/auth/:provider/callback", cont.OauthHandler.CallBackRegister)
 	e.Use(MW.Logger())
 	e.Validator = validators.NewValidator()
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 	e.POST("/register", cont.RegisterHandler.Register)
 	e.POST("/login", cont.RegisterHandler.Login)
+	e.POST("/login/mfa", cont.RegisterHandler.LoginMFA)
+	e.POST("/refresh", cont.RegisterHandler.Refresh)
+	e.POST("/password/forgot", cont.PasswordHandler.Forgot)
+	e.GET("/password/reset", cont.PasswordHandler.ResetPage)
+	e.POST("/password/reset", cont.PasswordHandler.Reset)
+	e.GET("/verify-email", cont.VerificationHandler.Verify)
+	e.POST("/verify-email/resend", cont.VerificationHandler.Resend)
+	e.GET("/.well-known/jwks.json", cont.JWKSHandler.GetKeys)
+
+	conf := config.GetConfiguration()
+	authMW := cont.AuthMiddleware.JWT()
+	validToken := cont.AuthMiddleware.ValidateJWT()
+	// content routes also accept personal access tokens, account management needs a login
+	apiKey := cont.AuthMiddleware.APIKey()
+
+	// creating content needs a verified address only when configured so
+	var verifiedEmail []echo.MiddlewareFunc
+	if conf.EmailVerification == config.EmailVerificationContent {
+		verifiedEmail = append(verifiedEmail, cont.AuthMiddleware.RequireVerifiedEmail())
+	}
+
+	e.POST("/logout", cont.RegisterHandler.Logout, authMW, validToken)
+	// impersonation tokens are refused wherever the user's credentials, sessions or account change
+	userOnly := cont.AuthMiddleware.DenyImpersonation()
+
+	e.POST("/logout/all", cont.RegisterHandler.LogoutAll, authMW, validToken, userOnly)
 
 	v1 := e.Group("/api/v1")
 	v1.GET("", PingHandler)
 
-	authMW := cont.AuthMiddleware.JWT(config.GetConfiguration().AccessSecret)
-	validToken := cont.AuthMiddleware.ValidateJWT()
+	// admin routes each declare the permission they need, RequirePermission turns it into RequireRole for the roles
+	// domain.Permission grants it to
+	manageUsers := cont.AuthMiddleware.RequirePermission(domain.PermissionManageUsers)
+	manageTags := cont.AuthMiddleware.RequirePermission(domain.PermissionManageTags)
+	viewAuthEvents := cont.AuthMiddleware.RequirePermission(domain.PermissionViewAuthEvents)
+
+	meRouter := v1.Group("/me")
+	sessRouter := v1.Group("/sessions")
+	mfaRouter := v1.Group("/mfa")
+	tokenRouter := v1.Group("/tokens")
+	adminRouter := v1.Group("/admin/")
 	commRouter := v1.Group("/comments/")
 	postRouter := v1.Group("/posts/")
 
-	commRouter.Use(authMW, validToken)
-	postRouter.Use(authMW, validToken)
+	meRouter.Use(authMW, validToken)
+	sessRouter.Use(authMW, validToken)
+	mfaRouter.Use(authMW, validToken, userOnly)
+	tokenRouter.Use(authMW, validToken)
+	adminRouter.Use(authMW, validToken)
+	commRouter.Use(apiKey, authMW, validToken)
+	postRouter.Use(apiKey, authMW, validToken)
+
+	meRouter.DELETE("", cont.AccountHandler.DeleteAccount, userOnly)
+	meRouter.GET("/export", cont.AccountHandler.Export, userOnly)
+	meRouter.PUT("/password", cont.PasswordHandler.Change, userOnly)
+	meRouter.GET("/auth-events", cont.AuthEventHandler.GetMyEvents)
+
+	sessRouter.GET("", cont.SessionHandler.GetSessions)
+	sessRouter.DELETE("/:id", cont.SessionHandler.DeleteSession, userOnly)
+
+	mfaRouter.POST("/enroll", cont.MFAHandler.Enroll)
+	mfaRouter.POST("/confirm", cont.MFAHandler.Confirm)
+	mfaRouter.POST("/disable", cont.MFAHandler.Disable)
+
+	tokenRouter.GET("", cont.APITokenHandler.GetTokens)
+	tokenRouter.POST("", cont.APITokenHandler.CreateToken, userOnly)
+	tokenRouter.DELETE("/:id", cont.APITokenHandler.DeleteToken, userOnly)
+
+	adminRouter.GET("auth-events", cont.AuthEventHandler.GetEvents, viewAuthEvents)
+	adminRouter.GET("users/:id", cont.UserHandler.GetUser, manageUsers)
+	adminRouter.PUT("users/:id/role", cont.UserHandler.UpdateRole, manageUsers)
+	adminRouter.POST("users/:id/unlock", cont.UserHandler.Unlock, manageUsers)
+	adminRouter.POST("users/:id/impersonate", cont.UserHandler.Impersonate, manageUsers)
+	adminRouter.DELETE("users/:id", cont.UserHandler.DeleteUser, manageUsers)
+	adminRouter.PUT("tags/:id", cont.TagHandler.RenameTag, manageTags)
+	adminRouter.POST("tags/:id/merge", cont.TagHandler.MergeTags, manageTags)
 
-	commRouter.POST("save/:post_id", cont.CommentHandler.SaveComment)
+	commRouter.POST("save/:post_id", cont.CommentHandler.SaveComment, verifiedEmail...)
 	commRouter.GET("comment/:id", cont.CommentHandler.GetComment)
 	commRouter.PUT("update/:id", cont.CommentHandler.UpdateComment)
 	commRouter.DELETE("delete/:id", cont.CommentHandler.DeleteComment)
 
-	postRouter.POST("save", cont.PostHandler.SavePost)
+	v1.GET("/posts", cont.PostHandler.GetPosts, apiKey, authMW, validToken)
+	v1.GET("/search", cont.SearchHandler.Search, apiKey, authMW, validToken)
+	v1.GET("/tags", cont.TagHandler.GetTags, apiKey, authMW, validToken)
+	postRouter.POST("save", cont.PostHandler.SavePost, verifiedEmail...)
 	postRouter.GET("post/:id", cont.PostHandler.GetPost)
 	postRouter.PUT("update/:id", cont.PostHandler.UpdatePost)
 	postRouter.DELETE("delete/:id", cont.PostHandler.DeletePost)
+	postRouter.POST("publish/:id", cont.PostHandler.PublishPost)
+	postRouter.POST("unpublish/:id", cont.PostHandler.UnpublishPost)
+	postRouter.POST("archive/:id", cont.PostHandler.ArchivePost)
+	postRouter.GET(":id/revisions", cont.PostRevisionHandler.GetRevisions)
+	postRouter.GET(":id/revisions/diff", cont.PostRevisionHandler.Diff)
+	postRouter.POST(":id/revisions/:rev/restore", cont.PostRevisionHandler.Restore)
 }
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"time"
+	"trainee/internal/app"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+type AccountHandler struct {
+	as app.AccountService
+}
+
+func NewAccountHandler(a app.AccountService) AccountHandler {
+	return AccountHandler{
+		as: a,
+	}
+}
+
+// DeleteAccount 	godoc
+// @Summary 		Delete my account
+// @Description 	Delete the account of the current user, its posts and comments are deleted or anonymized as configured
+// @Tags			Account Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.DeleteAccount true "current password"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			401 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/me [delete]
+func (a AccountHandler) DeleteAccount(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	var deleteAccount requests.DeleteAccount
+	if err := ctx.Bind(&deleteAccount); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode user data")
+	}
+	err := a.as.Delete(claims.ID, deleteAccount.Password, device(ctx))
+	if err != nil {
+		if errors.Is(err, app.ErrWrongPassword) {
+			return response.ErrorResponse(ctx, http.StatusForbidden, "Current password is wrong")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not delete account: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "Account deleted")
+}
+
+// Export 			godoc
+// @Summary 		Export my data
+// @Description 	ZIP archive with a JSON file for the profile, posts, comments, linked identities, api tokens, sessions and auth events of the current user
+// @Tags			Account Actions
+// @Produce 		application/zip
+// @Success 		200 {file} binary
+// @Failure			401 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/me/export [get]
+func (a AccountHandler) Export(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	export, err := a.as.Export(claims.ID)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not export account: %s", err))
+	}
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "application/zip")
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"account-%d-%s.zip\"", claims.ID, time.Now().Format("20060102")))
+	res.WriteHeader(http.StatusOK)
+	return writeExportArchive(res, export.DomainToResponse(claims.SID))
+}
+
+func writeExportArchive(w io.Writer, export response.AccountExportResponse) error {
+	archive := zip.NewWriter(w)
+	for _, file := range []struct {
+		name    string
+		content interface{}
+	}{
+		{"user.json", export.User},
+		{"posts.json", export.Posts},
+		{"post_revisions.json", export.PostRevisions},
+		{"comments.json", export.Comments},
+		{"identities.json", export.Identities},
+		{"api_tokens.json", export.APITokens},
+		{"sessions.json", export.Sessions},
+		{"auth_events.json", export.AuthEvents},
+	} {
+		f, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(file.content)
+		if err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
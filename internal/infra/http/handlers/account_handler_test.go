@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/upper/db/v4"
+	"io"
+	"net/http"
+	"testing"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+	"trainee/internal/infra/http/requests"
+)
+
+func TestAccountHandler_DeleteAccount(t *testing.T) {
+	request := test_case.Request{
+		Method: http.MethodDelete,
+		Url:    "/me",
+	}
+	body := requests.DeleteAccount{Password: "01234567890"}
+
+	handle := func(err error) func(c echo.Context) error {
+		return func(c echo.Context) error {
+			mockAccount := mocks.NewAccountService(t)
+			mockAccount.On("Delete", int64(1), body.Password, loginDevice).Return(err).Times(1)
+			return handlers.NewAccountHandler(mockAccount).DeleteAccount(c)
+		}
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "DeleteAccount success",
+			Request:     request,
+			RequestBody: body,
+			HandlerFunc: handle(nil),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"Account deleted\"}\n"},
+		},
+		{
+			TestName:    "DeleteAccount wrong password",
+			Request:     request,
+			RequestBody: body,
+			HandlerFunc: handle(app.ErrWrongPassword),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "{\"code\":403,\"error\":\"Current password is wrong\"}\n"},
+		},
+		{
+			TestName:    "DeleteAccount error",
+			Request:     request,
+			RequestBody: body,
+			HandlerFunc: handle(db.ErrCollectionDoesNotExist),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not delete account: upper: collection does not exist\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAccountHandler_Export(t *testing.T) {
+	test := test_case.TestCase{
+		Request: test_case.Request{
+			Method: http.MethodGet,
+			Url:    "/me/export",
+		},
+	}
+	export := domain.AccountExport{
+		User:          domain.User{ID: 1, Email: "user@example.com", Name: "Name", Role: domain.RoleUser},
+		Posts:         []domain.Post{{ID: 2, UserID: 1, Title: "Title", Body: "Body", Tags: []string{"golang"}, Status: domain.PostStatusDraft, CreatedDate: postDate, UpdatedDate: postDate}},
+		PostRevisions: []domain.PostRevision{{PostID: 2, Revision: 1, EditorID: 1, Title: "Title", Body: "Body", CreatedDate: postDate}},
+	}
+	mockAccount := mocks.NewAccountService(t)
+	mockAccount.On("Export", int64(1)).Return(export, nil).Times(1)
+
+	c, recorder := test_case.PrepareContextFromTestCase(test)
+	c.Set("user", test_case.Token())
+	require.NoError(t, handlers.NewAccountHandler(mockAccount).Export(c))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/zip", recorder.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, recorder.Header().Get(echo.HeaderContentDisposition), "attachment; filename=\"account-1-")
+
+	archive, err := zip.NewReader(bytes.NewReader(recorder.Body.Bytes()), int64(recorder.Body.Len()))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(content)
+	}
+	assert.Len(t, files, 8)
+	assert.JSONEq(t, `{"id":1,"email":"user@example.com","name":"Name","role":"user","email_verified":false}`, files["user.json"])
+	assert.JSONEq(t, `[{"id":2,"user_id":1,"title":"Title","body":"Body","tags":["golang"],"status":"draft","created_at":"2022-11-01T10:00:00Z","updated_at":"2022-11-01T10:00:00Z","comments":null}]`, files["posts.json"])
+	assert.JSONEq(t, `[{"post_id":2,"revision":1,"editor_id":1,"title":"Title","body":"Body","created_at":"2022-11-01T10:00:00Z"}]`, files["post_revisions.json"])
+	assert.JSONEq(t, `[]`, files["auth_events.json"])
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"trainee/internal/app"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+type APITokenHandler struct {
+	ts app.APITokenService
+}
+
+func NewAPITokenHandler(t app.APITokenService) APITokenHandler {
+	return APITokenHandler{
+		ts: t,
+	}
+}
+
+// GetTokens 		godoc
+// @Summary 		List api tokens
+// @Description 	List the personal access tokens of the current user
+// @Tags			Token Actions
+// @Produce 		json
+// @Success 		200 {array} response.APITokenResponse
+// @Failure			401 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/tokens [get]
+func (t APITokenHandler) GetTokens(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	tokens, err := t.ts.List(claims.ID)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not get tokens: %s", err))
+	}
+	dom := domain.APIToken{}
+	return response.Response(ctx, http.StatusOK, dom.AllAPITokensDomainToResponse(tokens))
+}
+
+// CreateToken 		godoc
+// @Summary 		Create api token
+// @Description 	Create a personal access token, it is shown only in this response
+// @Tags			Token Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.CreateAPIToken true "name, scopes, lifetime in days"
+// @Success 		201 {object} response.CreatedAPITokenResponse
+// @Failure			400 {object} response.Error
+// @Failure			401 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/tokens [post]
+func (t APITokenHandler) CreateToken(ctx echo.Context) error {
+	var createToken requests.CreateAPIToken
+	if err := ctx.Bind(&createToken); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode token data")
+	}
+	if err := ctx.Validate(&createToken); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate token data")
+	}
+	scopes := make([]domain.Scope, 0, len(createToken.Scopes))
+	for _, s := range createToken.Scopes {
+		scopes = append(scopes, domain.Scope(s))
+	}
+
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	plain, token, err := t.ts.Create(claims.ID, createToken.Name, scopes, createToken.ExpiresInDays, device(ctx))
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not create token: %s", err))
+	}
+	return response.Response(ctx, http.StatusCreated, response.CreatedAPITokenResponse{
+		Token:            plain,
+		APITokenResponse: token.DomainToResponse(),
+	})
+}
+
+// DeleteToken 		godoc
+// @Summary 		Delete api token
+// @Description 	Revoke a personal access token of the current user
+// @Tags			Token Actions
+// @Produce 		json
+// @Param			id path int true "Token ID"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			401 {object} response.Error
+// @Failure			404 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/tokens/{id} [delete]
+func (t APITokenHandler) DeleteToken(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse token ID")
+	}
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	err = t.ts.Delete(id, claims.ID, device(ctx))
+	if err != nil {
+		if errors.Is(err, app.ErrAPITokenNotFound) {
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Could not delete token: token not found")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not delete token: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "Token deleted")
+}
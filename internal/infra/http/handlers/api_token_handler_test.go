@@ -0,0 +1,140 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"net/http"
+	"testing"
+	"time"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+	"trainee/internal/infra/http/requests"
+)
+
+func TestAPITokenHandler(t *testing.T) {
+	created := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	token := domain.APIToken{
+		ID:          3,
+		UserID:      1,
+		Name:        "ci",
+		Prefix:      "pat_abcdef",
+		Scopes:      []domain.Scope{domain.ScopeRead},
+		ExpiresDate: created.AddDate(0, 0, 30),
+		CreatedDate: created,
+	}
+	tokenJSON := "\"id\":3,\"name\":\"ci\",\"prefix\":\"pat_abcdef\",\"scopes\":[\"read\"],\"expires_at\":\"2022-12-01T10:00:00Z\",\"last_used_at\":null,\"created_at\":\"2022-11-01T10:00:00Z\""
+	createRequest := requests.CreateAPIToken{Name: "ci", Scopes: []string{"read"}}
+
+	requestList := test_case.Request{Method: http.MethodGet, Url: "/tokens"}
+	requestCreate := test_case.Request{Method: http.MethodPost, Url: "/tokens"}
+	requestDelete := test_case.Request{
+		Method: http.MethodDelete,
+		Url:    "/tokens/3",
+		PathParam: &test_case.PathParam{
+			Name:  "id",
+			Value: "3",
+		},
+	}
+
+	handleList := func(c echo.Context) error {
+		mockToken := mocks.NewAPITokenService(t)
+		mockToken.On("List", int64(1)).Return([]domain.APIToken{token}, nil).Times(1)
+		return handlers.NewAPITokenHandler(mockToken).GetTokens(c)
+	}
+
+	handleCreate := func(c echo.Context) error {
+		mockToken := mocks.NewAPITokenService(t)
+		mockToken.On("Create", int64(1), "ci", []domain.Scope{domain.ScopeRead}, 0, loginDevice).Return("pat_abcdefgh", token, nil).Times(1)
+		return handlers.NewAPITokenHandler(mockToken).CreateToken(c)
+	}
+
+	handleCreateInvalid := func(c echo.Context) error {
+		return handlers.NewAPITokenHandler(mocks.NewAPITokenService(t)).CreateToken(c)
+	}
+
+	handleDelete := func(c echo.Context) error {
+		mockToken := mocks.NewAPITokenService(t)
+		mockToken.On("Delete", int64(3), int64(1), loginDevice).Return(nil).Times(1)
+		return handlers.NewAPITokenHandler(mockToken).DeleteToken(c)
+	}
+
+	handleDeleteNotFound := func(c echo.Context) error {
+		mockToken := mocks.NewAPITokenService(t)
+		mockToken.On("Delete", int64(3), int64(1), loginDevice).Return(app.ErrAPITokenNotFound).Times(1)
+		return handlers.NewAPITokenHandler(mockToken).DeleteToken(c)
+	}
+
+	handleListError := func(c echo.Context) error {
+		mockToken := mocks.NewAPITokenService(t)
+		mockToken.On("List", int64(1)).Return(nil, db.ErrCollectionDoesNotExist).Times(1)
+		return handlers.NewAPITokenHandler(mockToken).GetTokens(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "GetTokens success",
+			Request:     requestList,
+			HandlerFunc: handleList,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "[{" + tokenJSON + "}]\n"},
+		},
+		{
+			TestName:    "GetTokens error",
+			Request:     requestList,
+			HandlerFunc: handleListError,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not get tokens: upper: collection does not exist\"}\n"},
+		},
+		{
+			TestName:    "CreateToken success",
+			Request:     requestCreate,
+			RequestBody: createRequest,
+			HandlerFunc: handleCreate,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 201,
+				BodyPart:   "{\"token\":\"pat_abcdefgh\"," + tokenJSON + "}\n"},
+		},
+		{
+			TestName:    "CreateToken unknown scope",
+			Request:     requestCreate,
+			RequestBody: requests.CreateAPIToken{Name: "ci", Scopes: []string{"admin"}},
+			HandlerFunc: handleCreateInvalid,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate token data\"}\n"},
+		},
+		{
+			TestName:    "DeleteToken success",
+			Request:     requestDelete,
+			HandlerFunc: handleDelete,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"Token deleted\"}\n"},
+		},
+		{
+			TestName:    "DeleteToken not found",
+			Request:     requestDelete,
+			HandlerFunc: handleDeleteNotFound,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not delete token: token not found\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
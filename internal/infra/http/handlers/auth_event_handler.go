@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"trainee/internal/app"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+type AuthEventHandler struct {
+	es app.AuthEventService
+}
+
+func NewAuthEventHandler(e app.AuthEventService) AuthEventHandler {
+	return AuthEventHandler{
+		es: e,
+	}
+}
+
+// GetMyEvents 		godoc
+// @Summary 		List my auth events
+// @Description 	Audit log of the current user, newest first. Pass the last id as before to get the next page
+// @Tags			Auth Events
+// @Produce 		json
+// @Param			type query string false "Event type" Enums(login, refresh, logout, oauth_link, password_change, impersonate, token_created, token_revoked, token_scope)
+// @Param			outcome query string false "success or failure"
+// @Param			before query int false "Only events with a smaller id"
+// @Param			limit query int false "Page size, 50 by default and 200 at most"
+// @Success 		200 {array} response.AuthEventResponse
+// @Failure			401 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/me/auth-events [get]
+func (a AuthEventHandler) GetMyEvents(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	var query requests.AuthEventQuery
+	if err := ctx.Bind(&query); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode query")
+	}
+	if err := ctx.Validate(&query); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate query")
+	}
+	filter := query.QueryToFilter()
+	// users only see their own account, whatever the query says
+	filter.UserID = claims.ID
+	filter.Email = ""
+	return a.find(ctx, filter)
+}
+
+// GetEvents 		godoc
+// @Summary 		List auth events
+// @Description 	Audit log of logins, refreshes, logouts, oauth links and password changes, newest first, admin only
+// @Tags			Admin Actions
+// @Produce 		json
+// @Param			user_id query int false "User ID"
+// @Param			actor_id query int false "ID of the admin who impersonated the user"
+// @Param			email query string false "Email a login was attempted with"
+// @Param			type query string false "Event type" Enums(login, refresh, logout, oauth_link, password_change, impersonate, token_created, token_revoked, token_scope)
+// @Param			outcome query string false "success or failure"
+// @Param			before query int false "Only events with a smaller id"
+// @Param			limit query int false "Page size, 50 by default and 200 at most"
+// @Success 		200 {array} response.AuthEventResponse
+// @Failure			400 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/admin/auth-events [get]
+func (a AuthEventHandler) GetEvents(ctx echo.Context) error {
+	var query requests.AuthEventQuery
+	if err := ctx.Bind(&query); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode query")
+	}
+	if err := ctx.Validate(&query); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate query")
+	}
+	return a.find(ctx, query.QueryToFilter())
+}
+
+func (a AuthEventHandler) find(ctx echo.Context, filter domain.AuthEventFilter) error {
+	events, err := a.es.Find(filter)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not get auth events: %s", err))
+	}
+	dom := domain.AuthEvent{}
+	return response.Response(ctx, http.StatusOK, dom.AllAuthEventsDomainToResponse(events))
+}
@@ -0,0 +1,133 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"net/http"
+	"testing"
+	"time"
+	"trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+)
+
+func TestAuthEventHandler(t *testing.T) {
+	created := time.Date(2022, 11, 14, 10, 0, 0, 0, time.UTC)
+	eventsMock := []domain.AuthEvent{
+		{
+			ID:          7,
+			UserID:      1,
+			Email:       "user@example.com",
+			Type:        domain.AuthEventLogin,
+			Outcome:     domain.AuthFailure,
+			Detail:      "wrong password",
+			IP:          "127.0.0.1",
+			UserAgent:   "Mozilla/5.0",
+			CreatedDate: created,
+		},
+	}
+
+	requestMine := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/me/auth-events?user_id=2&email=other@example.com&outcome=failure&before=8",
+	}
+	requestAdmin := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/admin/auth-events?email=user@example.com&type=login&limit=10",
+	}
+	requestTokenScope := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/me/auth-events?type=token_scope",
+	}
+	requestInvalid := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/admin/auth-events?type=delete",
+	}
+
+	handleMine := func(c echo.Context) error {
+		mockEvents := mocks.NewAuthEventService(t)
+		// the user's own id replaces the filters for other accounts
+		mockEvents.On("Find", domain.AuthEventFilter{UserID: 1, Outcome: domain.AuthFailure, Before: 8}).Return(eventsMock, nil).Times(1)
+		return handlers.NewAuthEventHandler(mockEvents).GetMyEvents(c)
+	}
+
+	handleTokenScope := func(c echo.Context) error {
+		mockEvents := mocks.NewAuthEventService(t)
+		mockEvents.On("Find", domain.AuthEventFilter{UserID: 1, Type: domain.AuthEventTokenScope}).Return(eventsMock, nil).Times(1)
+		return handlers.NewAuthEventHandler(mockEvents).GetMyEvents(c)
+	}
+
+	handleAdmin := func(c echo.Context) error {
+		mockEvents := mocks.NewAuthEventService(t)
+		mockEvents.On("Find", domain.AuthEventFilter{Email: "user@example.com", Type: domain.AuthEventLogin, Limit: 10}).Return(eventsMock, nil).Times(1)
+		return handlers.NewAuthEventHandler(mockEvents).GetEvents(c)
+	}
+
+	handleAdminError := func(c echo.Context) error {
+		mockEvents := mocks.NewAuthEventService(t)
+		mockEvents.On("Find", domain.AuthEventFilter{Email: "user@example.com", Type: domain.AuthEventLogin, Limit: 10}).Return(nil, db.ErrCollectionDoesNotExist).Times(1)
+		return handlers.NewAuthEventHandler(mockEvents).GetEvents(c)
+	}
+
+	handleInvalid := func(c echo.Context) error {
+		return handlers.NewAuthEventHandler(mocks.NewAuthEventService(t)).GetEvents(c)
+	}
+
+	eventJSON := "[{\"id\":7,\"user_id\":1,\"email\":\"user@example.com\",\"type\":\"login\",\"outcome\":\"failure\",\"detail\":\"wrong password\",\"ip\":\"127.0.0.1\",\"user_agent\":\"Mozilla/5.0\",\"created_at\":\"2022-11-14T10:00:00Z\"}]\n"
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "GetMyEvents success",
+			Request:     requestMine,
+			HandlerFunc: handleMine,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   eventJSON},
+		},
+		{
+			TestName:    "GetMyEvents token type",
+			Request:     requestTokenScope,
+			HandlerFunc: handleTokenScope,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   eventJSON},
+		},
+		{
+			TestName:    "GetEvents success",
+			Request:     requestAdmin,
+			HandlerFunc: handleAdmin,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   eventJSON},
+		},
+		{
+			TestName:    "GetEvents error",
+			Request:     requestAdmin,
+			HandlerFunc: handleAdminError,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not get auth events: upper: collection does not exist\"}\n"},
+		},
+		{
+			TestName:    "GetEvents unknown type",
+			Request:     requestInvalid,
+			HandlerFunc: handleInvalid,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate query\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
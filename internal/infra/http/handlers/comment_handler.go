@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
@@ -83,7 +84,8 @@ func (c CommentHandler) GetComment(ctx echo.Context) error {
 	if err != nil {
 		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse comment ID")
 	}
-	comment, err := c.service.GetComment(id)
+	token := ctx.Get("user").(*jwt.Token)
+	comment, err := c.service.GetComment(id, token)
 	if err != nil {
 		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
 			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not get comment: %s", err))
@@ -106,6 +108,7 @@ func (c CommentHandler) GetComment(ctx echo.Context) error {
 // @Success 		200 {object} response.CommentResponse
 // @Failure			400 {object} response.Error
 // @Failure			422 {object} response.Error
+// @Failure			403 {object} response.Error
 // @Failure			404 {object} response.Error
 // @Security 		ApiKeyAuth
 // @Router			/api/v1/comments/update/{id} [put]
@@ -123,9 +126,13 @@ func (c CommentHandler) UpdateComment(ctx echo.Context) error {
 	if err != nil {
 		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse comment ID")
 	}
-	comment, err := c.service.UpdateComment(commentRequest, id)
+	token := ctx.Get("user").(*jwt.Token)
+	comment, err := c.service.UpdateComment(commentRequest, id, token)
 	if err != nil {
-		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+		var forbidden app.ForbiddenError
+		if errors.As(err, &forbidden) {
+			return response.ErrorResponse(ctx, http.StatusForbidden, fmt.Sprintf("Could not update comment: %s", forbidden))
+		} else if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
 			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not update comment: %s", err))
 		} else {
 			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not update comment: %s", err))
@@ -142,6 +149,7 @@ func (c CommentHandler) UpdateComment(ctx echo.Context) error {
 // @Param			id path int true "ID"
 // @Success 		200 {object} response.Data
 // @Failure			400	{object} response.Error
+// @Failure			403 {object} response.Error
 // @Failure			404 {object} response.Error
 // @Failure			500 {object} response.Error
 // @Security 		ApiKeyAuth
@@ -151,10 +159,14 @@ func (c CommentHandler) DeleteComment(ctx echo.Context) error {
 	if err != nil {
 		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse comment ID")
 	}
-	err = c.service.DeleteComment(id)
+	token := ctx.Get("user").(*jwt.Token)
+	err = c.service.DeleteComment(id, token)
 	if err != nil {
 		log.Print(err)
-		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+		var forbidden app.ForbiddenError
+		if errors.As(err, &forbidden) {
+			return response.ErrorResponse(ctx, http.StatusForbidden, fmt.Sprintf("Could not delete comment: %s", forbidden))
+		} else if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
 			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not delete comment: %s", err))
 		} else {
 			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not delete comment: %s", err))
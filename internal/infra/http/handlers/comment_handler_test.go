@@ -27,6 +27,7 @@ var requestCommentMock = requests.CommentRequest{
 var returnDomainCommentMock = domain.Comment{
 	ID:     2,
 	PostID: 1,
+	UserID: 1,
 	Name:   "Name",
 	Email:  "test@mail.com",
 	Body:   "Test body",
@@ -108,7 +109,7 @@ func TestWalkCommentSuccess(t *testing.T) {
 	handleFuncGet := func(c echo.Context) error {
 		mock := func(id int64) app.CommentService {
 			mock := mocks.NewCommentService(t)
-			mock.On("GetComment", id).Return(returnDomainCommentMock, nil).Times(1)
+			mock.On("GetComment", id, c.Get("user")).Return(returnDomainCommentMock, nil).Times(1)
 			return mock
 		}(2)
 		return handlers.NewCommentHandler(mock).GetComment(c)
@@ -127,7 +128,7 @@ func TestWalkCommentSuccess(t *testing.T) {
 		mock := func(r requests.CommentRequest, id int64) app.CommentService {
 			mock := mocks.NewCommentService(t)
 			returnDomainCommentMock.Body = r.Body
-			mock.On("UpdateComment", r, id).Return(returnDomainCommentMock, nil).Times(1)
+			mock.On("UpdateComment", r, id, test_case.Token()).Return(returnDomainCommentMock, nil).Times(1)
 			return mock
 		}(requests.CommentRequest{Body: "Update body"}, 2)
 		return handlers.NewCommentHandler(mock).UpdateComment(c)
@@ -136,7 +137,7 @@ func TestWalkCommentSuccess(t *testing.T) {
 	handleFuncDelete := func(c echo.Context) error {
 		mock := func(id int64) app.CommentService {
 			mock := mocks.NewCommentService(t)
-			mock.On("DeleteComment", id).Return(nil).Times(1)
+			mock.On("DeleteComment", id, test_case.Token()).Return(nil).Times(1)
 			return mock
 		}(2)
 		return handlers.NewCommentHandler(mock).DeleteComment(c)
@@ -148,7 +149,7 @@ func TestWalkCommentSuccess(t *testing.T) {
 			Request:     requestGetComment,
 			RequestBody: "",
 			HandlerFunc: handleFuncGet,
-			Expected:    test_case.ExpectedResponse{StatusCode: 200, BodyPart: "{\"id\":2,\"post_id\":1,\"name\":\"Name\",\"email\":\"test@mail.com\",\"body\":\"Test body\"}\n"},
+			Expected:    test_case.ExpectedResponse{StatusCode: 200, BodyPart: "{\"id\":2,\"post_id\":1,\"user_id\":1,\"name\":\"Name\",\"email\":\"test@mail.com\",\"body\":\"Test body\"}\n"},
 		},
 		{
 			TestName:    "SaveComment Success",
@@ -157,7 +158,7 @@ func TestWalkCommentSuccess(t *testing.T) {
 			HandlerFunc: handleFuncSave,
 			Expected: test_case.ExpectedResponse{
 				StatusCode: 201,
-				BodyPart:   "{\"id\":2,\"post_id\":1,\"name\":\"Name\",\"email\":\"test@mail.com\",\"body\":\"Test body\"}\n"},
+				BodyPart:   "{\"id\":2,\"post_id\":1,\"user_id\":1,\"name\":\"Name\",\"email\":\"test@mail.com\",\"body\":\"Test body\"}\n"},
 		},
 		{
 			TestName:    "UpdateComment Success",
@@ -166,7 +167,7 @@ func TestWalkCommentSuccess(t *testing.T) {
 			HandlerFunc: handleFuncUpdate,
 			Expected: test_case.ExpectedResponse{
 				StatusCode: 200,
-				BodyPart:   "{\"id\":2,\"post_id\":1,\"name\":\"Name\",\"email\":\"test@mail.com\",\"body\":\"Update body\"}\n"},
+				BodyPart:   "{\"id\":2,\"post_id\":1,\"user_id\":1,\"name\":\"Name\",\"email\":\"test@mail.com\",\"body\":\"Update body\"}\n"},
 		},
 		{
 			TestName:    "DeleteComment Success",
@@ -319,7 +320,7 @@ func TestWalkCommentServiceErrors(t *testing.T) {
 	handleFuncGetNotFound := func(c echo.Context) error {
 		mock := func(id int64) app.CommentService {
 			mock := mocks.NewCommentService(t)
-			mock.On("GetComment", id).Return(domain.Comment{}, db.ErrNoMoreRows).Times(1)
+			mock.On("GetComment", id, c.Get("user")).Return(domain.Comment{}, db.ErrNoMoreRows).Times(1)
 			return mock
 		}(2)
 		return handlers.NewCommentHandler(mock).GetComment(c)
@@ -328,7 +329,7 @@ func TestWalkCommentServiceErrors(t *testing.T) {
 	handleFuncUpdateNotFound := func(c echo.Context) error {
 		mock := func(r requests.CommentRequest, id int64) app.CommentService {
 			mock := mocks.NewCommentService(t)
-			mock.On("UpdateComment", r, id).Return(domain.Comment{}, db.ErrNoMoreRows).Times(1)
+			mock.On("UpdateComment", r, id, test_case.Token()).Return(domain.Comment{}, db.ErrNoMoreRows).Times(1)
 			return mock
 		}(requestCommentMock, 2)
 		return handlers.NewCommentHandler(mock).UpdateComment(c)
@@ -337,7 +338,7 @@ func TestWalkCommentServiceErrors(t *testing.T) {
 	handleFuncDeleteNotFound := func(c echo.Context) error {
 		mock := func(id int64) app.CommentService {
 			mock := mocks.NewCommentService(t)
-			mock.On("DeleteComment", id).Return(db.ErrNoMoreRows).Times(1)
+			mock.On("DeleteComment", id, test_case.Token()).Return(db.ErrNoMoreRows).Times(1)
 			return mock
 		}(2)
 		return handlers.NewCommentHandler(mock).DeleteComment(c)
@@ -353,7 +354,7 @@ func TestWalkCommentServiceErrors(t *testing.T) {
 	handleFuncGetInternalServerError := func(c echo.Context) error {
 		mock := func(id int64) app.CommentService {
 			mock := mocks.NewCommentService(t)
-			mock.On("GetComment", id).Return(domain.Comment{}, db.ErrCollectionDoesNotExist).Times(1)
+			mock.On("GetComment", id, c.Get("user")).Return(domain.Comment{}, db.ErrCollectionDoesNotExist).Times(1)
 			return mock
 		}(2)
 		return handlers.NewCommentHandler(mock).GetComment(c)
@@ -362,7 +363,7 @@ func TestWalkCommentServiceErrors(t *testing.T) {
 	handleFuncUpdateInternalServerError := func(c echo.Context) error {
 		mock := func(r requests.CommentRequest, id int64) app.CommentService {
 			mock := mocks.NewCommentService(t)
-			mock.On("UpdateComment", r, id).Return(domain.Comment{}, db.ErrCollectionDoesNotExist).Times(1)
+			mock.On("UpdateComment", r, id, test_case.Token()).Return(domain.Comment{}, db.ErrCollectionDoesNotExist).Times(1)
 			return mock
 		}(requestCommentMock, 2)
 		return handlers.NewCommentHandler(mock).UpdateComment(c)
@@ -371,7 +372,7 @@ func TestWalkCommentServiceErrors(t *testing.T) {
 	handleFuncDeleteInternalServerError := func(c echo.Context) error {
 		mock := func(id int64) app.CommentService {
 			mock := mocks.NewCommentService(t)
-			mock.On("DeleteComment", id).Return(db.ErrCollectionDoesNotExist).Times(1)
+			mock.On("DeleteComment", id, test_case.Token()).Return(db.ErrCollectionDoesNotExist).Times(1)
 			return mock
 		}(2)
 		return handlers.NewCommentHandler(mock).DeleteComment(c)
@@ -386,6 +387,24 @@ func TestWalkCommentServiceErrors(t *testing.T) {
 		return handlers.NewCommentHandler(mock).SaveComment(c)
 	}
 
+	handleFuncUpdateForbidden := func(c echo.Context) error {
+		mock := func(r requests.CommentRequest, id int64) app.CommentService {
+			mock := mocks.NewCommentService(t)
+			mock.On("UpdateComment", r, id, test_case.Token()).Return(domain.Comment{}, app.ForbiddenError{Action: "modify this comment"}).Times(1)
+			return mock
+		}(requestCommentMock, 2)
+		return handlers.NewCommentHandler(mock).UpdateComment(c)
+	}
+
+	handleFuncDeleteForbidden := func(c echo.Context) error {
+		mock := func(id int64) app.CommentService {
+			mock := mocks.NewCommentService(t)
+			mock.On("DeleteComment", id, test_case.Token()).Return(app.ForbiddenError{Action: "modify this comment"}).Times(1)
+			return mock
+		}(2)
+		return handlers.NewCommentHandler(mock).DeleteComment(c)
+	}
+
 	cases := []test_case.TestCase{
 		{
 			TestName:    "GetComment NoMoreRows",
@@ -457,6 +476,24 @@ func TestWalkCommentServiceErrors(t *testing.T) {
 				StatusCode: 500,
 				BodyPart:   "{\"code\":500,\"error\":\"Could not save new comment: upper: collection does not exist\"}\n"},
 		},
+		{
+			TestName:    "UpdateComment Forbidden",
+			Request:     requestUpdateComment,
+			RequestBody: requestCommentMock,
+			HandlerFunc: handleFuncUpdateForbidden,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "{\"code\":403,\"error\":\"Could not update comment: forbidden: not allowed to modify this comment\"}\n"},
+		},
+		{
+			TestName:    "DeleteComment Forbidden",
+			Request:     requestDeleteComment,
+			RequestBody: "",
+			HandlerFunc: handleFuncDeleteForbidden,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "{\"code\":403,\"error\":\"Could not delete comment: forbidden: not allowed to modify this comment\"}\n"},
+		},
 	}
 	for _, test := range cases {
 		t.Run(test.TestName, func(t *testing.T) {
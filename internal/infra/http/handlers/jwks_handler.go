@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"trainee/internal/infra/http/response"
+	"trainee/internal/infra/signing"
+)
+
+type JWKSHandler struct {
+	keys *signing.KeySet
+}
+
+func NewJWKSHandler(k *signing.KeySet) JWKSHandler {
+	return JWKSHandler{
+		keys: k,
+	}
+}
+
+// GetKeys 			godoc
+// @Summary 		Signing keys
+// @Description 	Public keys access tokens are signed with, as a JSON Web Key Set. Clients match the kid header of a token
+// @Tags			Auth Actions
+// @Produce 		json
+// @Success 		200 {object} signing.JWKS
+// @Router			/.well-known/jwks.json [get]
+func (j JWKSHandler) GetKeys(ctx echo.Context) error {
+	ctx.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return response.Response(ctx, http.StatusOK, j.keys.JWKS())
+}
@@ -0,0 +1,36 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+	"trainee/internal/infra/signing"
+)
+
+func TestJWKSHandler_GetKeys(t *testing.T) {
+	test := test_case.TestCase{
+		TestName: "Shared secret is not published",
+		Request: test_case.Request{
+			Method: http.MethodGet,
+			Url:    "/.well-known/jwks.json",
+		},
+		HandlerFunc: func(c echo.Context) error {
+			return handlers.NewJWKSHandler(signing.NewHMACKeySet("secret")).GetKeys(c)
+		},
+		Expected: test_case.ExpectedResponse{
+			StatusCode: 200,
+			BodyPart:   "{\"keys\":[]}\n"},
+	}
+	t.Run(test.TestName, func(t *testing.T) {
+		c, recorder := test_case.PrepareContextFromTestCase(test)
+
+		if assert.NoError(t, test.HandlerFunc(c)) {
+			assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+			assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			assert.Equal(t, "public, max-age=300", recorder.Header().Get("Cache-Control"))
+		}
+	})
+}
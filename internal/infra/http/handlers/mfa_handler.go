@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"trainee/internal/app"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+type MFAHandler struct {
+	ms app.MFAService
+}
+
+func NewMFAHandler(m app.MFAService) MFAHandler {
+	return MFAHandler{
+		ms: m,
+	}
+}
+
+// Enroll 			godoc
+// @Summary 		Enroll mfa
+// @Description 	Create a TOTP secret for the current user, mfa is enabled once it is confirmed with a code
+// @Tags			MFA Actions
+// @Produce 		json
+// @Success 		200 {object} response.MFAEnrollResponse
+// @Failure			401 {object} response.Error
+// @Failure			409 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/mfa/enroll [post]
+func (m MFAHandler) Enroll(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	secret, uri, err := m.ms.Enroll(claims.ID)
+	if err != nil {
+		if errors.Is(err, app.ErrMFAAlreadyEnabled) {
+			return response.ErrorResponse(ctx, http.StatusConflict, "Mfa is already enabled")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not enroll mfa: %s", err))
+	}
+	return response.Response(ctx, http.StatusOK, response.MFAEnrollResponse{Secret: secret, URI: uri})
+}
+
+// Confirm 			godoc
+// @Summary 		Confirm mfa
+// @Description 	Enable mfa with a code from the authenticator, the recovery codes are returned only here
+// @Tags			MFA Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.MFACode true "TOTP code"
+// @Success 		200 {object} response.RecoveryCodesResponse
+// @Failure			400 {object} response.Error
+// @Failure			401 {object} response.Error
+// @Failure			409 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/mfa/confirm [post]
+func (m MFAHandler) Confirm(ctx echo.Context) error {
+	var mfaCode requests.MFACode
+	if err := ctx.Bind(&mfaCode); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode mfa data")
+	}
+	if err := ctx.Validate(&mfaCode); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate mfa data")
+	}
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	codes, err := m.ms.Confirm(claims.ID, mfaCode.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrInvalidMFACode):
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid mfa code")
+		case errors.Is(err, app.ErrMFANotEnrolled):
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Mfa is not enrolled")
+		case errors.Is(err, app.ErrMFAAlreadyEnabled):
+			return response.ErrorResponse(ctx, http.StatusConflict, "Mfa is already enabled")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not confirm mfa: %s", err))
+	}
+	return response.Response(ctx, http.StatusOK, response.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable 			godoc
+// @Summary 		Disable mfa
+// @Description 	Turn mfa off with a TOTP or recovery code
+// @Tags			MFA Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.MFACode true "TOTP or recovery code"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			401 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			429 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/mfa/disable [post]
+func (m MFAHandler) Disable(ctx echo.Context) error {
+	var mfaCode requests.MFACode
+	if err := ctx.Bind(&mfaCode); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode mfa data")
+	}
+	if err := ctx.Validate(&mfaCode); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate mfa data")
+	}
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	err := m.ms.Disable(claims.ID, mfaCode.Code, device(ctx))
+	if err != nil {
+		var locked *app.LockedError
+		switch {
+		case errors.As(err, &locked):
+			return lockedResponse(ctx, locked)
+		case errors.Is(err, app.ErrInvalidMFACode):
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid mfa code")
+		case errors.Is(err, app.ErrMFANotEnrolled):
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Mfa is not enabled")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not disable mfa: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "Mfa disabled")
+}
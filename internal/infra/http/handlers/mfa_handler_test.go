@@ -0,0 +1,136 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+	"trainee/internal/infra/http/requests"
+)
+
+func TestMFAHandler(t *testing.T) {
+	code := requests.MFACode{Code: "123456"}
+
+	handleEnrollSuccess := func(c echo.Context) error {
+		mockMFA := mocks.NewMFAService(t)
+		mockMFA.On("Enroll", int64(1)).Return("SECRET", "otpauth://totp/trainee:user?secret=SECRET", nil).Times(1)
+		return handlers.NewMFAHandler(mockMFA).Enroll(c)
+	}
+
+	handleEnrollEnabled := func(c echo.Context) error {
+		mockMFA := mocks.NewMFAService(t)
+		mockMFA.On("Enroll", int64(1)).Return("", "", app.ErrMFAAlreadyEnabled).Times(1)
+		return handlers.NewMFAHandler(mockMFA).Enroll(c)
+	}
+
+	handleConfirmSuccess := func(c echo.Context) error {
+		mockMFA := mocks.NewMFAService(t)
+		mockMFA.On("Confirm", int64(1), code.Code).Return([]string{"abcde-fghij"}, nil).Times(1)
+		return handlers.NewMFAHandler(mockMFA).Confirm(c)
+	}
+
+	handleConfirmInvalidCode := func(c echo.Context) error {
+		mockMFA := mocks.NewMFAService(t)
+		mockMFA.On("Confirm", int64(1), code.Code).Return(nil, app.ErrInvalidMFACode).Times(1)
+		return handlers.NewMFAHandler(mockMFA).Confirm(c)
+	}
+
+	handleDisableSuccess := func(c echo.Context) error {
+		mockMFA := mocks.NewMFAService(t)
+		mockMFA.On("Disable", int64(1), code.Code, loginDevice).Return(nil).Times(1)
+		return handlers.NewMFAHandler(mockMFA).Disable(c)
+	}
+
+	handleDisableLocked := func(c echo.Context) error {
+		mockMFA := mocks.NewMFAService(t)
+		mockMFA.On("Disable", int64(1), code.Code, loginDevice).Return(&app.LockedError{RetryAfter: time.Minute}).Times(1)
+		return handlers.NewMFAHandler(mockMFA).Disable(c)
+	}
+
+	handleDisableNoCode := func(c echo.Context) error {
+		return handlers.NewMFAHandler(mocks.NewMFAService(t)).Disable(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "Enroll success",
+			Request:     test_case.Request{Method: http.MethodPost, Url: "/mfa/enroll"},
+			HandlerFunc: handleEnrollSuccess,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"secret\":\"SECRET\",\"uri\":\"otpauth://totp/trainee:user?secret=SECRET\"}\n"},
+		},
+		{
+			TestName:    "Enroll already enabled",
+			Request:     test_case.Request{Method: http.MethodPost, Url: "/mfa/enroll"},
+			HandlerFunc: handleEnrollEnabled,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 409,
+				BodyPart:   "{\"code\":409,\"error\":\"Mfa is already enabled\"}\n"},
+		},
+		{
+			TestName:    "Confirm success",
+			Request:     test_case.Request{Method: http.MethodPost, Url: "/mfa/confirm"},
+			RequestBody: code,
+			HandlerFunc: handleConfirmSuccess,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"recovery_codes\":[\"abcde-fghij\"]}\n"},
+		},
+		{
+			TestName:    "Confirm invalid code",
+			Request:     test_case.Request{Method: http.MethodPost, Url: "/mfa/confirm"},
+			RequestBody: code,
+			HandlerFunc: handleConfirmInvalidCode,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Invalid mfa code\"}\n"},
+		},
+		{
+			TestName:    "Disable success",
+			Request:     test_case.Request{Method: http.MethodPost, Url: "/mfa/disable"},
+			RequestBody: code,
+			HandlerFunc: handleDisableSuccess,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"Mfa disabled\"}\n"},
+		},
+		{
+			TestName:    "Disable locked",
+			Request:     test_case.Request{Method: http.MethodPost, Url: "/mfa/disable"},
+			RequestBody: code,
+			HandlerFunc: handleDisableLocked,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 429,
+				BodyPart:   "{\"code\":429,\"error\":\"Too many failed logins, try again later\"}\n"},
+		},
+		{
+			TestName:    "Disable without code",
+			Request:     test_case.Request{Method: http.MethodPost, Url: "/mfa/disable"},
+			RequestBody: requests.MFACode{},
+			HandlerFunc: handleDisableNoCode,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate mfa data\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+			if test.Expected.StatusCode == http.StatusTooManyRequests {
+				assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
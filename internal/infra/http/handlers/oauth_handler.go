@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"trainee/internal/app"
+	"trainee/internal/infra/http/response"
+)
+
+// oauthStateCookie carries the state to the callback in the browser that started the login,
+// a state from another browser is refused so nobody can be logged in to someone else's account.
+const oauthStateCookie = "oauth_state"
+
+type OauthHandler struct {
+	os app.OAuthService
+}
+
+func NewOauthHandler(o app.OAuthService) OauthHandler {
+	return OauthHandler{
+		os: o,
+	}
+}
+
+func (o OauthHandler) GetInfo(ctx echo.Context) error {
+	url, state, err := o.os.AuthCodeURL(ctx.Param("provider"))
+	if err != nil {
+		if errors.Is(err, app.ErrUnknownProvider) {
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Unknown oauth provider")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Oauth error: %s", err))
+	}
+	ctx.SetCookie(stateCookie(ctx, state, int(app.OAuthStateTTL.Seconds())))
+	err = ctx.Redirect(http.StatusTemporaryRedirect, url)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Oauth error, could not redirect url: %s", err))
+	}
+	return nil
+}
+
+func (o OauthHandler) CallBackRegister(ctx echo.Context) error {
+	state := ctx.FormValue("state")
+	cookie, err := ctx.Cookie(oauthStateCookie)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Oauth error, invalid state")
+	}
+	// the state is used up either way
+	ctx.SetCookie(stateCookie(ctx, "", -1))
+
+	accessToken, refreshToken, exp, err := o.os.Callback(ctx.Param("provider"), state, ctx.FormValue("code"), device(ctx))
+	if err != nil {
+		var mfaRequired *app.MFARequiredError
+		switch {
+		case errors.As(err, &mfaRequired):
+			return response.Response(ctx, http.StatusAccepted, response.MFARequiredResponse{MFAToken: mfaRequired.Token, Exp: mfaRequired.Exp})
+		case errors.Is(err, app.ErrUnknownProvider):
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Unknown oauth provider")
+		case errors.Is(err, app.ErrInvalidOAuthState):
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Oauth error, invalid state")
+		case errors.Is(err, app.ErrOAuthProvider):
+			return response.ErrorResponse(ctx, http.StatusBadRequest, fmt.Sprintf("Oauth error, failed getting user info: %s", err))
+		case errors.Is(err, app.ErrUnverifiedEmail):
+			return response.ErrorResponse(ctx, http.StatusForbidden, "Oauth error, email is not verified")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not login user: %s", err))
+	}
+	res := response.NewLoginResponse(accessToken, refreshToken, exp)
+	return response.Response(ctx, http.StatusOK, res)
+}
+
+// stateCookie is only sent to the callbacks, it survives the top level redirect back from the provider
+// with SameSite=Lax. A negative maxAge deletes it.
+func stateCookie(ctx echo.Context, state string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/auth/" + ctx.Param("provider") + "/callback",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   ctx.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+}
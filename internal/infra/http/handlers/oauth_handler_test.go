@@ -0,0 +1,75 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"trainee/internal/app/mocks"
+	"trainee/internal/infra/http/handlers"
+)
+
+func TestOauthHandler_State(t *testing.T) {
+	newContext := func(method, target string, cookie *http.Cookie) (echo.Context, *httptest.ResponseRecorder) {
+		request := httptest.NewRequest(method, target, nil)
+		if cookie != nil {
+			request.AddCookie(cookie)
+		}
+		recorder := httptest.NewRecorder()
+		c := echo.New().NewContext(request, recorder)
+		c.SetParamNames("provider")
+		c.SetParamValues("github")
+		return c, recorder
+	}
+
+	t.Run("GetInfo binds the state to the browser", func(t *testing.T) {
+		mockOAuth := mocks.NewOAuthService(t)
+		mockOAuth.On("AuthCodeURL", "github").Return("https://github.com/login/oauth/authorize?state=state", "state", nil).Times(1)
+		c, recorder := newContext(http.MethodGet, "/auth/github/login", nil)
+
+		if assert.NoError(t, handlers.NewOauthHandler(mockOAuth).GetInfo(c)) {
+			assert.Equal(t, http.StatusTemporaryRedirect, recorder.Code)
+			cookies := recorder.Result().Cookies()
+			if assert.Len(t, cookies, 1) {
+				assert.Equal(t, "oauth_state", cookies[0].Name)
+				assert.Equal(t, "state", cookies[0].Value)
+				assert.Equal(t, "/auth/github/callback", cookies[0].Path)
+				assert.True(t, cookies[0].HttpOnly)
+				assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+				assert.Equal(t, 600, cookies[0].MaxAge)
+			}
+		}
+	})
+
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+		valid  bool
+	}{
+		{"Callback with the state of the browser", &http.Cookie{Name: "oauth_state", Value: "state"}, true},
+		{"Callback without a state cookie", nil, false},
+		{"Callback with the state of another browser", &http.Cookie{Name: "oauth_state", Value: "other"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOAuth := mocks.NewOAuthService(t)
+			if tt.valid {
+				mockOAuth.On("Callback", "github", "state", "code", mock.Anything).Return("access", "refresh", int64(123), nil).Times(1)
+			}
+			c, recorder := newContext(http.MethodGet, "/auth/github/callback?state=state&code=code", tt.cookie)
+
+			if assert.NoError(t, handlers.NewOauthHandler(mockOAuth).CallBackRegister(c)) {
+				if tt.valid {
+					assert.Equal(t, http.StatusOK, recorder.Code)
+					assert.Equal(t, "{\"accessToken\":\"access\",\"refreshToken\":\"refresh\",\"exp\":123}\n", recorder.Body.String())
+					assert.Equal(t, -1, recorder.Result().Cookies()[0].MaxAge)
+				} else {
+					assert.Equal(t, http.StatusBadRequest, recorder.Code)
+					assert.Equal(t, "{\"code\":400,\"error\":\"Oauth error, invalid state\"}\n", recorder.Body.String())
+				}
+			}
+		})
+	}
+}
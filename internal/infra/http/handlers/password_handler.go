@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"html/template"
+	"net/http"
+	"trainee/internal/app"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+// resetPage is where reset links lead when PASSWORD_RESET_URL doesn't point to a page of the frontend,
+// it posts the token with the new password to the reset endpoint.
+var resetPage = template.Must(template.New("reset").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Reset your password</title></head>
+<body>
+<form method="post" action="/password/reset">
+<input type="hidden" name="token" value="{{.}}">
+<label>New password <input type="password" name="password" autocomplete="new-password" required></label>
+<button type="submit">Set password</button>
+</form>
+</body>
+</html>
+`))
+
+type PasswordHandler struct {
+	ps app.PasswordService
+}
+
+func NewPasswordHandler(p app.PasswordService) PasswordHandler {
+	return PasswordHandler{
+		ps: p,
+	}
+}
+
+// Forgot 			godoc
+// @Summary 		Forgot password
+// @Description 	Mail a password reset link, the answer is the same whether the address is known or not
+// @Tags			Auth Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.ForgotPassword true "users email"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Router			/password/forgot [post]
+func (p PasswordHandler) Forgot(ctx echo.Context) error {
+	var forgot requests.ForgotPassword
+	if err := ctx.Bind(&forgot); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode user data")
+	}
+	if err := ctx.Validate(&forgot); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate user data")
+	}
+	err := p.ps.Forgot(forgot.Email)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not send reset link: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "If the address is registered, a reset link has been sent")
+}
+
+// Reset 			godoc
+// @Summary 		Reset password
+// @Description 	Set a new password with a reset token, all sessions are logged out
+// @Tags			Auth Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.ResetPassword true "reset token, new password"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			422 {object} response.FieldError
+// @Failure			500 {object} response.Error
+// @Router			/password/reset [post]
+func (p PasswordHandler) Reset(ctx echo.Context) error {
+	var reset requests.ResetPassword
+	if err := ctx.Bind(&reset); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode user data")
+	}
+	if err := ctx.Validate(&reset); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate user data")
+	}
+	err := p.ps.Reset(reset.Token, reset.Password, device(ctx))
+	if err != nil {
+		var policy *app.PasswordPolicyError
+		if errors.Is(err, app.ErrInvalidResetToken) {
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid or expired reset token")
+		} else if errors.As(err, &policy) {
+			return passwordPolicyResponse(ctx, policy)
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not reset password: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "Password has been reset")
+}
+
+// ResetPage 		godoc
+// @Summary 		Reset password page
+// @Description 	The page reset links open by default, a form for the new password that posts to /password/reset
+// @Tags			Auth Actions
+// @Produce 		html
+// @Param			token query string true "reset token"
+// @Success 		200 {string} string
+// @Failure			400 {object} response.Error
+// @Router			/password/reset [get]
+func (p PasswordHandler) ResetPage(ctx echo.Context) error {
+	token := ctx.QueryParam("token")
+	if token == "" {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Missing reset token")
+	}
+	var page bytes.Buffer
+	err := resetPage.Execute(&page, token)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not render page: %s", err))
+	}
+	ctx.Response().Header().Set("Referrer-Policy", "no-referrer")
+	return ctx.HTMLBlob(http.StatusOK, page.Bytes())
+}
+
+// Change 			godoc
+// @Summary 		Change password
+// @Description 	Set a new password by giving the current one, the other sessions are logged out
+// @Tags			Auth Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.ChangePassword true "current password, new password"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			422 {object} response.FieldError
+// @Failure			429 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/me/password [put]
+func (p PasswordHandler) Change(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	var change requests.ChangePassword
+	if err := ctx.Bind(&change); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode user data")
+	}
+	if err := ctx.Validate(&change); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate user data")
+	}
+	err := p.ps.Change(claims.ID, claims.SID, change.CurrentPassword, change.Password, device(ctx))
+	if err != nil {
+		var policy *app.PasswordPolicyError
+		var locked *app.LockedError
+		if errors.Is(err, app.ErrWrongPassword) {
+			return response.ErrorResponse(ctx, http.StatusForbidden, "Current password is wrong")
+		} else if errors.As(err, &locked) {
+			return lockedResponse(ctx, locked)
+		} else if errors.As(err, &policy) {
+			return passwordPolicyResponse(ctx, policy)
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not change password: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "Password has been changed")
+}
+
+func passwordPolicyResponse(ctx echo.Context, err *app.PasswordPolicyError) error {
+	return response.FieldErrorResponse(ctx, http.StatusUnprocessableEntity, "Password does not meet the policy",
+		map[string][]string{"password": err.Violations})
+}
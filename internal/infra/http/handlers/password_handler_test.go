@@ -0,0 +1,263 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/validators"
+)
+
+func TestPasswordHandler(t *testing.T) {
+	forgotRequest := test_case.Request{
+		Method: http.MethodPost,
+		Url:    "/password/forgot",
+	}
+	resetRequest := test_case.Request{
+		Method: http.MethodPost,
+		Url:    "/password/reset",
+	}
+	forgot := requests.ForgotPassword{Email: "example@email.com"}
+	reset := requests.ResetPassword{Token: "token", Password: "01234567890"}
+
+	handleForgotSuccess := func(c echo.Context) error {
+		mockPassword := mocks.NewPasswordService(t)
+		mockPassword.On("Forgot", forgot.Email).Return(nil).Times(1)
+		return handlers.NewPasswordHandler(mockPassword).Forgot(c)
+	}
+
+	handleForgotError := func(c echo.Context) error {
+		mockPassword := mocks.NewPasswordService(t)
+		mockPassword.On("Forgot", forgot.Email).Return(db.ErrCollectionDoesNotExist).Times(1)
+		return handlers.NewPasswordHandler(mockPassword).Forgot(c)
+	}
+
+	handleForgotInvalid := func(c echo.Context) error {
+		return handlers.NewPasswordHandler(mocks.NewPasswordService(t)).Forgot(c)
+	}
+
+	handleResetSuccess := func(c echo.Context) error {
+		mockPassword := mocks.NewPasswordService(t)
+		mockPassword.On("Reset", reset.Token, reset.Password, loginDevice).Return(nil).Times(1)
+		return handlers.NewPasswordHandler(mockPassword).Reset(c)
+	}
+
+	handleResetInvalidToken := func(c echo.Context) error {
+		mockPassword := mocks.NewPasswordService(t)
+		mockPassword.On("Reset", reset.Token, reset.Password, loginDevice).Return(app.ErrInvalidResetToken).Times(1)
+		return handlers.NewPasswordHandler(mockPassword).Reset(c)
+	}
+
+	handleResetPolicy := func(c echo.Context) error {
+		mockPassword := mocks.NewPasswordService(t)
+		mockPassword.On("Reset", reset.Token, reset.Password, loginDevice).
+			Return(&app.PasswordPolicyError{Violations: []string{"must not contain your name"}}).Times(1)
+		return handlers.NewPasswordHandler(mockPassword).Reset(c)
+	}
+
+	handleResetNoPassword := func(c echo.Context) error {
+		return handlers.NewPasswordHandler(mocks.NewPasswordService(t)).Reset(c)
+	}
+
+	handleResetPage := func(c echo.Context) error {
+		return handlers.NewPasswordHandler(mocks.NewPasswordService(t)).ResetPage(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "Forgot success",
+			Request:     forgotRequest,
+			RequestBody: forgot,
+			HandlerFunc: handleForgotSuccess,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"If the address is registered, a reset link has been sent\"}\n"},
+		},
+		{
+			TestName:    "Forgot error",
+			Request:     forgotRequest,
+			RequestBody: forgot,
+			HandlerFunc: handleForgotError,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not send reset link: upper: collection does not exist\"}\n"},
+		},
+		{
+			TestName:    "Forgot invalid email",
+			Request:     forgotRequest,
+			RequestBody: requests.ForgotPassword{Email: "example"},
+			HandlerFunc: handleForgotInvalid,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate user data\"}\n"},
+		},
+		{
+			TestName:    "Reset success",
+			Request:     resetRequest,
+			RequestBody: reset,
+			HandlerFunc: handleResetSuccess,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"Password has been reset\"}\n"},
+		},
+		{
+			TestName:    "Reset invalid token",
+			Request:     resetRequest,
+			RequestBody: reset,
+			HandlerFunc: handleResetInvalidToken,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Invalid or expired reset token\"}\n"},
+		},
+		{
+			TestName:    "Reset password breaks the policy",
+			Request:     resetRequest,
+			RequestBody: reset,
+			HandlerFunc: handleResetPolicy,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Password does not meet the policy\",\"fields\":{\"password\":[\"must not contain your name\"]}}\n"},
+		},
+		{
+			TestName:    "Reset no password",
+			Request:     resetRequest,
+			RequestBody: requests.ResetPassword{Token: "token"},
+			HandlerFunc: handleResetNoPassword,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate user data\"}\n"},
+		},
+		{
+			TestName:    "ResetPage form",
+			Request:     test_case.Request{Method: http.MethodGet, Url: "/password/reset?token=a%22b"},
+			HandlerFunc: handleResetPage,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "<input type=\"hidden\" name=\"token\" value=\"a&#34;b\">"},
+		},
+		{
+			TestName:    "ResetPage without token",
+			Request:     test_case.Request{Method: http.MethodGet, Url: "/password/reset"},
+			HandlerFunc: handleResetPage,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Missing reset token\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
+
+func TestPasswordHandler_ResetForm(t *testing.T) {
+	// the reset page posts a form instead of json
+	request := httptest.NewRequest(http.MethodPost, "/password/reset", strings.NewReader("token=token&password=01234567890"))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	request.RemoteAddr = loginDevice.IP + ":1234"
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.Validator = validators.NewValidator()
+	c := e.NewContext(request, recorder)
+
+	mockPassword := mocks.NewPasswordService(t)
+	mockPassword.On("Reset", "token", "01234567890", loginDevice).Return(nil).Times(1)
+	if assert.NoError(t, handlers.NewPasswordHandler(mockPassword).Reset(c)) {
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+}
+
+func TestPasswordHandler_Change(t *testing.T) {
+	changeRequest := test_case.Request{
+		Method: http.MethodPut,
+		Url:    "/api/v1/me/password",
+	}
+	change := requests.ChangePassword{CurrentPassword: "01234567890", Password: "correct-Horse-battery"}
+
+	handleChange := func(err error) func(c echo.Context) error {
+		return func(c echo.Context) error {
+			mockPassword := mocks.NewPasswordService(t)
+			mockPassword.On("Change", int64(1), "", change.CurrentPassword, change.Password, loginDevice).Return(err).Times(1)
+			return handlers.NewPasswordHandler(mockPassword).Change(c)
+		}
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "Change success",
+			Request:     changeRequest,
+			RequestBody: change,
+			HandlerFunc: handleChange(nil),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"Password has been changed\"}\n"},
+		},
+		{
+			TestName:    "Change wrong current password",
+			Request:     changeRequest,
+			RequestBody: change,
+			HandlerFunc: handleChange(app.ErrWrongPassword),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "{\"code\":403,\"error\":\"Current password is wrong\"}\n"},
+		},
+		{
+			TestName:    "Change locked",
+			Request:     changeRequest,
+			RequestBody: change,
+			HandlerFunc: handleChange(&app.LockedError{RetryAfter: time.Minute}),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 429,
+				BodyPart:   "{\"code\":429,\"error\":\"Too many failed logins, try again later\"}\n"},
+		},
+		{
+			TestName:    "Change password breaks the policy",
+			Request:     changeRequest,
+			RequestBody: change,
+			HandlerFunc: handleChange(&app.PasswordPolicyError{Violations: []string{"must be at least 8 characters long", "must not contain your name"}}),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Password does not meet the policy\",\"fields\":{\"password\":[\"must be at least 8 characters long\",\"must not contain your name\"]}}\n"},
+		},
+		{
+			TestName:    "Change validate error",
+			Request:     changeRequest,
+			RequestBody: requests.ChangePassword{Password: "correct-Horse-battery"},
+			HandlerFunc: func(c echo.Context) error {
+				return handlers.NewPasswordHandler(mocks.NewPasswordService(t)).Change(c)
+			},
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate user data\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+			if test.Expected.StatusCode == http.StatusTooManyRequests {
+				assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
@@ -53,15 +54,62 @@ func (p PostHandler) SavePost(ctx echo.Context) error {
 	post, err := p.service.SavePost(postRequest, token)
 	if err != nil {
 		log.Print(err)
+		if errors.Is(err, app.ErrInvalidTag) {
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Invalid tag")
+		} else if errors.Is(err, app.ErrInvalidSchedule) {
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Publish time must be in the future")
+		}
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not save new post: %s", err))
 	}
 	postResponse := domain.Post.DomainToResponse(post)
 	return response.Response(ctx, http.StatusCreated, postResponse)
 }
 
+// GetPosts  		godoc
+// @Summary 		List Posts
+// @Description 	Feed of posts, newest first. Pass next_cursor as cursor to get the next page, it is empty on the last one
+// @Tags			Posts Actions
+// @Produce 		json
+// @Param			user_id query int false "Author ID"
+// @Param			status query string false "draft, scheduled, published or archived; other statuses than published only list your own posts"
+// @Param			tag query []string false "Only posts with all these tags, up to 5" collectionFormat(multi)
+// @Param			from query string false "Only posts from this date on, RFC 3339"
+// @Param			to query string false "Only posts before this date, RFC 3339"
+// @Param			sort query string false "created or updated, the date the feed is sorted and filtered by"
+// @Param			order query string false "desc or asc, desc by default"
+// @Param			cursor query string false "next_cursor of the previous page"
+// @Param			limit query int false "Page size, 20 by default and 100 at most"
+// @Success 		200 {object} response.PostPageResponse
+// @Failure 		400 {object} response.Error
+// @Failure 		422 {object} response.Error
+// @Failure 		500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/posts [get]
+func (p PostHandler) GetPosts(ctx echo.Context) error {
+	var query requests.PostQuery
+	if err := ctx.Bind(&query); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode query")
+	}
+	if err := ctx.Validate(&query); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate query")
+	}
+	filter := query.QueryToFilter()
+	filter.ViewerID = ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim).ID
+	page, err := p.service.ListPosts(filter, query.Cursor)
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidCursor) {
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cursor")
+		} else if errors.Is(err, app.ErrInvalidTag) {
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Invalid tag")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not list posts: %s", err))
+	}
+	return response.Response(ctx, http.StatusOK, page.DomainToResponse())
+}
+
 // GetPost  		godoc
 // @Summary 		Get Post
-// @Description 	Get Post
+// @Description 	Get Post, posts that are not published are only found by their author
 // @Tags			Posts Actions
 // @Produce 		json
 // @Param			id path int true "ID"
@@ -81,7 +129,8 @@ func (p PostHandler) GetPost(ctx echo.Context) error {
 	if err != nil {
 		offset = 0
 	}
-	post, err := p.service.GetPost(id)
+	token := ctx.Get("user").(*jwt.Token)
+	post, err := p.service.GetPost(id, token)
 	if err != nil {
 		log.Print(err)
 		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
@@ -103,7 +152,7 @@ func (p PostHandler) GetPost(ctx echo.Context) error {
 
 // UpdatePost  		godoc
 // @Summary 		Update Post
-// @Description 	Update Post
+// @Description 	Update Post, its tags are replaced with the given ones
 // @Tags			Posts Actions
 // @Accept 			json
 // @Produce 		json
@@ -113,6 +162,7 @@ func (p PostHandler) GetPost(ctx echo.Context) error {
 // @Failure 		400 {object} response.Error
 // @Failure 		422 {object} response.Error
 // @Failure 		400 {object} response.Error
+// @Failure 		403 {object} response.Error
 // @Failure 		404 {object} response.Error
 // @Failure 		500 {object} response.Error
 // @Security        ApiKeyAuth
@@ -131,9 +181,17 @@ func (p PostHandler) UpdatePost(ctx echo.Context) error {
 	if err != nil {
 		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse post ID")
 	}
-	post, err := p.service.UpdatePost(postRequest, postID)
+	token := ctx.Get("user").(*jwt.Token)
+	post, err := p.service.UpdatePost(postRequest, postID, token)
 	if err != nil {
-		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+		var forbidden app.ForbiddenError
+		if errors.As(err, &forbidden) {
+			return response.ErrorResponse(ctx, http.StatusForbidden, fmt.Sprintf("Could not update post: %s", forbidden))
+		} else if errors.Is(err, app.ErrInvalidTag) {
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Invalid tag")
+		} else if errors.Is(err, app.ErrInvalidSchedule) {
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Publish time must be in the future")
+		} else if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
 			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not get post: %s", err))
 		} else {
 			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not get post: %s", err))
@@ -151,6 +209,7 @@ func (p PostHandler) UpdatePost(ctx echo.Context) error {
 // @Param			id path int true "ID"
 // @Success 		200 {object} response.Data
 // @Failure 		400 {object} response.Error
+// @Failure 		403 {object} response.Error
 // @Failure 		404 {object} response.Error
 // @Failure 		500 {object} response.Error
 // @Security        ApiKeyAuth
@@ -160,9 +219,13 @@ func (p PostHandler) DeletePost(ctx echo.Context) error {
 	if err != nil {
 		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse post ID")
 	}
-	err = p.service.DeletePost(id)
+	token := ctx.Get("user").(*jwt.Token)
+	err = p.service.DeletePost(id, token)
 	if err != nil {
-		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+		var forbidden app.ForbiddenError
+		if errors.As(err, &forbidden) {
+			return response.ErrorResponse(ctx, http.StatusForbidden, fmt.Sprintf("Could not delete post: %s", forbidden))
+		} else if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
 			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not get post: %s", err))
 		} else {
 			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not get post: %s", err))
@@ -170,3 +233,102 @@ func (p PostHandler) DeletePost(ctx echo.Context) error {
 	}
 	return response.MessageResponse(ctx, http.StatusOK, "Post successfully delete")
 }
+
+// PublishPost  	godoc
+// @Summary 		Publish Post
+// @Description 	Publish a post now, or schedule it when publish_at is in the future
+// @Tags			Posts Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Param			input body requests.PublishRequest false "publish time"
+// @Success 		200 {object} response.PostResponse
+// @Failure 		400 {object} response.Error
+// @Failure 		403 {object} response.Error
+// @Failure 		404 {object} response.Error
+// @Failure 		422 {object} response.Error
+// @Failure 		500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/posts/publish/{id} [post]
+func (p PostHandler) PublishPost(ctx echo.Context) error {
+	var publishRequest requests.PublishRequest
+	err := ctx.Bind(&publishRequest)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode publish data")
+	}
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse post ID")
+	}
+	token := ctx.Get("user").(*jwt.Token)
+	post, err := p.service.PublishPost(id, publishRequest.PublishAt, token)
+	if err != nil {
+		return statusErrorResponse(ctx, "publish", err)
+	}
+	return response.Response(ctx, http.StatusOK, post.DomainToResponse())
+}
+
+// UnpublishPost  	godoc
+// @Summary 		Unpublish Post
+// @Description 	Turn a published or scheduled post back into a draft
+// @Tags			Posts Actions
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Success 		200 {object} response.PostResponse
+// @Failure 		400 {object} response.Error
+// @Failure 		403 {object} response.Error
+// @Failure 		404 {object} response.Error
+// @Failure 		500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/posts/unpublish/{id} [post]
+func (p PostHandler) UnpublishPost(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse post ID")
+	}
+	token := ctx.Get("user").(*jwt.Token)
+	post, err := p.service.UnpublishPost(id, token)
+	if err != nil {
+		return statusErrorResponse(ctx, "unpublish", err)
+	}
+	return response.Response(ctx, http.StatusOK, post.DomainToResponse())
+}
+
+// ArchivePost  	godoc
+// @Summary 		Archive Post
+// @Description 	Take a post out of the feed, only its author sees it afterwards
+// @Tags			Posts Actions
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Success 		200 {object} response.PostResponse
+// @Failure 		400 {object} response.Error
+// @Failure 		403 {object} response.Error
+// @Failure 		404 {object} response.Error
+// @Failure 		500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/posts/archive/{id} [post]
+func (p PostHandler) ArchivePost(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse post ID")
+	}
+	token := ctx.Get("user").(*jwt.Token)
+	post, err := p.service.ArchivePost(id, token)
+	if err != nil {
+		return statusErrorResponse(ctx, "archive", err)
+	}
+	return response.Response(ctx, http.StatusOK, post.DomainToResponse())
+}
+
+// statusErrorResponse answers a failed change of the post status.
+func statusErrorResponse(ctx echo.Context, action string, err error) error {
+	var forbidden app.ForbiddenError
+	if errors.As(err, &forbidden) {
+		return response.ErrorResponse(ctx, http.StatusForbidden, fmt.Sprintf("Could not %s post: %s", action, forbidden))
+	} else if errors.Is(err, app.ErrInvalidSchedule) {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Publish time must be in the future")
+	} else if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+		return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not %s post: %s", action, err))
+	}
+	return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not %s post: %s", action, err))
+}
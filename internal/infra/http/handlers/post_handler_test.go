@@ -6,7 +6,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/upper/db/v4"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 	"trainee/internal/app"
 	"trainee/internal/app/mocks"
 	"trainee/internal/domain"
@@ -24,13 +26,21 @@ var requestPostMock = requests.PostRequest{
 	Title: "title",
 	Body:  "body",
 }
+var postDate = time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+
 var returnDomainPostMock = domain.Post{
-	UserID: 1,
-	ID:     1,
-	Title:  "title",
-	Body:   "body",
+	UserID:        1,
+	ID:            1,
+	Title:         "title",
+	Body:          "body",
+	Status:        domain.PostStatusPublished,
+	CreatedDate:   postDate,
+	UpdatedDate:   postDate,
+	PublishedDate: &postDate,
 }
 
+const postJSON = "{\"id\":1,\"user_id\":1,\"title\":\"title\",\"body\":\"body\",\"tags\":[],\"status\":\"published\",\"created_at\":\"2022-11-01T10:00:00Z\",\"updated_at\":\"2022-11-01T10:00:00Z\",\"published_at\":\"2022-11-01T10:00:00Z\",\"comments\":null}"
+
 var requestGet = test_case.Request{
 	Method: http.MethodGet,
 	Url:    "/post/" + postID,
@@ -94,7 +104,7 @@ func TestWalkPostSuccess(t *testing.T) {
 	handleFuncGet := func(c echo.Context) error {
 		mock := func(id int64) app.PostService {
 			mock := mocks.NewPostService(t)
-			mock.On("GetPost", id).Return(returnDomainPostMock, nil).Times(1)
+			mock.On("GetPost", id, c.Get("user")).Return(returnDomainPostMock, nil).Times(1)
 			return mock
 		}(1)
 		mockComment := func(id int64, offset int) app.CommentService {
@@ -118,7 +128,7 @@ func TestWalkPostSuccess(t *testing.T) {
 	handleFuncUpdate := func(c echo.Context) error {
 		mock := func(r requests.PostRequest, id int64) app.PostService {
 			mock := mocks.NewPostService(t)
-			mock.On("UpdatePost", requestPostMock, id).Return(returnDomainPostMock, nil).Times(1)
+			mock.On("UpdatePost", requestPostMock, id, test_case.Token()).Return(returnDomainPostMock, nil).Times(1)
 			return mock
 		}(requestPostMock, 1)
 		mockComment := mocks.NewCommentService(t)
@@ -128,7 +138,7 @@ func TestWalkPostSuccess(t *testing.T) {
 	handleFuncDelete := func(c echo.Context) error {
 		mock := func(id int64) app.PostService {
 			mock := mocks.NewPostService(t)
-			mock.On("DeletePost", id).Return(nil).Times(1)
+			mock.On("DeletePost", id, test_case.Token()).Return(nil).Times(1)
 			return mock
 		}(1)
 		mockComment := mocks.NewCommentService(t)
@@ -143,7 +153,7 @@ func TestWalkPostSuccess(t *testing.T) {
 			HandlerFunc: handleFuncGet,
 			Expected: test_case.ExpectedResponse{
 				StatusCode: 200,
-				BodyPart:   "{\"id\":1,\"user_id\":1,\"title\":\"title\",\"body\":\"body\",\"comments\":null}\n"},
+				BodyPart:   postJSON + "\n"},
 		},
 		{
 			TestName:    "SavePost Success",
@@ -152,7 +162,7 @@ func TestWalkPostSuccess(t *testing.T) {
 			HandlerFunc: handleFuncSave,
 			Expected: test_case.ExpectedResponse{
 				StatusCode: 201,
-				BodyPart:   "{\"id\":1,\"user_id\":1,\"title\":\"title\",\"body\":\"body\",\"comments\":null}\n"},
+				BodyPart:   postJSON + "\n"},
 		},
 		{
 			TestName:    "UpdatePost Success",
@@ -161,7 +171,7 @@ func TestWalkPostSuccess(t *testing.T) {
 			HandlerFunc: handleFuncUpdate,
 			Expected: test_case.ExpectedResponse{
 				StatusCode: 200,
-				BodyPart:   "{\"id\":1,\"user_id\":1,\"title\":\"title\",\"body\":\"body\",\"comments\":null}\n"},
+				BodyPart:   postJSON + "\n"},
 		},
 		{
 			TestName:    "DeletePost Success",
@@ -264,6 +274,18 @@ func TestWalkPostsDecodeValidationParsingErrors(t *testing.T) {
 				StatusCode: 422,
 				BodyPart:   "{\"code\":422,\"error\":\"Could not validate post data\"}\n"},
 		},
+		{
+			TestName: "SavePost body too long",
+			Request:  requestSave,
+			RequestBody: requests.PostRequest{
+				Title: "title",
+				Body:  strings.Repeat("line\n", 10001),
+			},
+			HandlerFunc: handleFuncSave,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate post data\"}\n"},
+		},
 		{
 			TestName:    "GetPost parse path param Error",
 			Request:     requestGetError,
@@ -309,7 +331,7 @@ func TestWalkPostServiceErrors(t *testing.T) {
 	handleFuncGetNotFound := func(c echo.Context) error {
 		mock := func(id int64) app.PostService {
 			mock := mocks.NewPostService(t)
-			mock.On("GetPost", id).Return(domain.Post{}, db.ErrNoMoreRows).Times(1)
+			mock.On("GetPost", id, c.Get("user")).Return(domain.Post{}, db.ErrNoMoreRows).Times(1)
 			return mock
 		}(1)
 		mockComment := mocks.NewCommentService(t)
@@ -319,7 +341,7 @@ func TestWalkPostServiceErrors(t *testing.T) {
 	handleFuncUpdateNotFound := func(c echo.Context) error {
 		mock := func(r requests.PostRequest, id int64) app.PostService {
 			mock := mocks.NewPostService(t)
-			mock.On("UpdatePost", requestPostMock, id).Return(domain.Post{}, db.ErrNoMoreRows).Times(1)
+			mock.On("UpdatePost", requestPostMock, id, test_case.Token()).Return(domain.Post{}, db.ErrNoMoreRows).Times(1)
 			return mock
 		}(requestPostMock, 1)
 		mockComment := mocks.NewCommentService(t)
@@ -329,7 +351,7 @@ func TestWalkPostServiceErrors(t *testing.T) {
 	handleFuncDeleteNotFound := func(c echo.Context) error {
 		mock := func(id int64) app.PostService {
 			mock := mocks.NewPostService(t)
-			mock.On("DeletePost", id).Return(db.ErrNoMoreRows).Times(1)
+			mock.On("DeletePost", id, test_case.Token()).Return(db.ErrNoMoreRows).Times(1)
 			return mock
 		}(1)
 		mockComment := mocks.NewCommentService(t)
@@ -338,7 +360,7 @@ func TestWalkPostServiceErrors(t *testing.T) {
 	handleFuncGetInternalServerError := func(c echo.Context) error {
 		mock := func(id int64) app.PostService {
 			mock := mocks.NewPostService(t)
-			mock.On("GetPost", id).Return(domain.Post{}, db.ErrCollectionDoesNotExist).Times(1)
+			mock.On("GetPost", id, c.Get("user")).Return(domain.Post{}, db.ErrCollectionDoesNotExist).Times(1)
 			return mock
 		}(1)
 		mockComment := mocks.NewCommentService(t)
@@ -348,7 +370,7 @@ func TestWalkPostServiceErrors(t *testing.T) {
 	handleFuncUpdateInternalServerError := func(c echo.Context) error {
 		mock := func(r requests.PostRequest, id int64) app.PostService {
 			mock := mocks.NewPostService(t)
-			mock.On("UpdatePost", requestPostMock, id).Return(domain.Post{}, db.ErrCollectionDoesNotExist).Times(1)
+			mock.On("UpdatePost", requestPostMock, id, test_case.Token()).Return(domain.Post{}, db.ErrCollectionDoesNotExist).Times(1)
 			return mock
 		}(requestPostMock, 1)
 		mockComment := mocks.NewCommentService(t)
@@ -358,7 +380,7 @@ func TestWalkPostServiceErrors(t *testing.T) {
 	handleFuncDeleteInternalServerError := func(c echo.Context) error {
 		mock := func(id int64) app.PostService {
 			mock := mocks.NewPostService(t)
-			mock.On("DeletePost", id).Return(db.ErrCollectionDoesNotExist).Times(1)
+			mock.On("DeletePost", id, test_case.Token()).Return(db.ErrCollectionDoesNotExist).Times(1)
 			return mock
 		}(1)
 		mockComment := mocks.NewCommentService(t)
@@ -375,6 +397,26 @@ func TestWalkPostServiceErrors(t *testing.T) {
 		return handlers.NewPostHandler(mock, mockComment).SavePost(c)
 	}
 
+	handleFuncUpdateForbidden := func(c echo.Context) error {
+		mock := func(r requests.PostRequest, id int64) app.PostService {
+			mock := mocks.NewPostService(t)
+			mock.On("UpdatePost", requestPostMock, id, test_case.Token()).Return(domain.Post{}, app.ForbiddenError{Action: "modify this post"}).Times(1)
+			return mock
+		}(requestPostMock, 1)
+		mockComment := mocks.NewCommentService(t)
+		return handlers.NewPostHandler(mock, mockComment).UpdatePost(c)
+	}
+
+	handleFuncDeleteForbidden := func(c echo.Context) error {
+		mock := func(id int64) app.PostService {
+			mock := mocks.NewPostService(t)
+			mock.On("DeletePost", id, test_case.Token()).Return(app.ForbiddenError{Action: "modify this post"}).Times(1)
+			return mock
+		}(1)
+		mockComment := mocks.NewCommentService(t)
+		return handlers.NewPostHandler(mock, mockComment).DeletePost(c)
+	}
+
 	cases := []test_case.TestCase{
 		{
 			TestName:    "GetPost NoMoreRows",
@@ -437,6 +479,219 @@ func TestWalkPostServiceErrors(t *testing.T) {
 				StatusCode: 500,
 				BodyPart:   "{\"code\":500,\"error\":\"Could not save new post: upper: collection does not exist\"}\n"},
 		},
+		{
+			TestName:    "UpdatePost Forbidden",
+			Request:     requestUpdate,
+			RequestBody: requestPostMock,
+			HandlerFunc: handleFuncUpdateForbidden,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "{\"code\":403,\"error\":\"Could not update post: forbidden: not allowed to modify this post\"}\n"},
+		},
+		{
+			TestName:    "DeletePost Forbidden",
+			Request:     requestDelete,
+			RequestBody: "",
+			HandlerFunc: handleFuncDeleteForbidden,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "{\"code\":403,\"error\":\"Could not delete post: forbidden: not allowed to modify this post\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
+
+func TestPostHandler_GetPosts(t *testing.T) {
+	filter := domain.PostFilter{ViewerID: 1, UserID: 1, Tags: []string{"go", "web"}, Sort: domain.PostSortUpdated, Ascending: true, Limit: 10}
+	page := domain.PostPage{Posts: []domain.Post{returnDomainPostMock}, NextCursor: "next", Total: 3}
+	requestList := func(query string) test_case.Request {
+		return test_case.Request{Method: http.MethodGet, Url: "/api/v1/posts?" + query}
+	}
+
+	handleFuncList := func(c echo.Context) error {
+		mock := mocks.NewPostService(t)
+		mock.On("ListPosts", filter, "cursor").Return(page, nil).Times(1)
+		return handlers.NewPostHandler(mock, mocks.NewCommentService(t)).GetPosts(c)
+	}
+
+	handleFuncInvalidCursor := func(c echo.Context) error {
+		mock := mocks.NewPostService(t)
+		mock.On("ListPosts", domain.PostFilter{ViewerID: 1}, "cursor").Return(domain.PostPage{}, app.ErrInvalidCursor).Times(1)
+		return handlers.NewPostHandler(mock, mocks.NewCommentService(t)).GetPosts(c)
+	}
+
+	handleFuncInvalidTag := func(c echo.Context) error {
+		mock := mocks.NewPostService(t)
+		mock.On("ListPosts", domain.PostFilter{ViewerID: 1, Tags: []string{"#"}}, "").Return(domain.PostPage{}, app.ErrInvalidTag).Times(1)
+		return handlers.NewPostHandler(mock, mocks.NewCommentService(t)).GetPosts(c)
+	}
+
+	handleMock := func(c echo.Context) error {
+		return handlers.NewPostHandler(mocks.NewPostService(t), mocks.NewCommentService(t)).GetPosts(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "GetPosts success",
+			Request:     requestList("user_id=1&tag=go&tag=web&sort=updated&order=asc&limit=10&cursor=cursor"),
+			HandlerFunc: handleFuncList,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"posts\":[" + postJSON + "],\"next_cursor\":\"next\",\"total\":3}\n"},
+		},
+		{
+			TestName:    "GetPosts invalid cursor",
+			Request:     requestList("cursor=cursor"),
+			HandlerFunc: handleFuncInvalidCursor,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Invalid cursor\"}\n"},
+		},
+		{
+			TestName:    "GetPosts invalid tag",
+			Request:     requestList("tag=%23"),
+			HandlerFunc: handleFuncInvalidTag,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Invalid tag\"}\n"},
+		},
+		{
+			TestName:    "GetPosts bad date",
+			Request:     requestList("from=yesterday"),
+			HandlerFunc: handleMock,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Could not decode query\"}\n"},
+		},
+		{
+			TestName:    "GetPosts date range reversed",
+			Request:     requestList("from=2022-12-01T00:00:00Z&to=2022-11-01T00:00:00Z"),
+			HandlerFunc: handleMock,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate query\"}\n"},
+		},
+		{
+			TestName:    "GetPosts unknown sort",
+			Request:     requestList("sort=title"),
+			HandlerFunc: handleMock,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate query\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, test.Expected.StatusCode, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestPostHandler_Status(t *testing.T) {
+	publishAt := time.Date(2022, 12, 1, 10, 0, 0, 0, time.UTC)
+	scheduled := returnDomainPostMock
+	scheduled.Status = domain.PostStatusScheduled
+	scheduled.PublishedDate = &publishAt
+	draft := returnDomainPostMock
+	draft.Status = domain.PostStatusDraft
+	draft.PublishedDate = nil
+
+	requestStatus := func(action string) test_case.Request {
+		return test_case.Request{
+			Method:    http.MethodPost,
+			Url:       "/" + action + "/" + postID,
+			PathParam: &test_case.PathParam{Name: "id", Value: postID},
+		}
+	}
+
+	handleFuncPublish := func(c echo.Context) error {
+		mock := mocks.NewPostService(t)
+		mock.On("PublishPost", int64(1), &publishAt, c.Get("user")).Return(scheduled, nil).Times(1)
+		return handlers.NewPostHandler(mock, mocks.NewCommentService(t)).PublishPost(c)
+	}
+
+	handleFuncPublishPast := func(c echo.Context) error {
+		mock := mocks.NewPostService(t)
+		mock.On("PublishPost", int64(1), (*time.Time)(nil), c.Get("user")).Return(domain.Post{}, app.ErrInvalidSchedule).Times(1)
+		return handlers.NewPostHandler(mock, mocks.NewCommentService(t)).PublishPost(c)
+	}
+
+	handleFuncUnpublish := func(c echo.Context) error {
+		mock := mocks.NewPostService(t)
+		mock.On("UnpublishPost", int64(1), c.Get("user")).Return(draft, nil).Times(1)
+		return handlers.NewPostHandler(mock, mocks.NewCommentService(t)).UnpublishPost(c)
+	}
+
+	handleFuncArchiveForbidden := func(c echo.Context) error {
+		mock := mocks.NewPostService(t)
+		mock.On("ArchivePost", int64(1), c.Get("user")).Return(domain.Post{}, app.ForbiddenError{Action: "modify this post"}).Times(1)
+		return handlers.NewPostHandler(mock, mocks.NewCommentService(t)).ArchivePost(c)
+	}
+
+	handleFuncArchiveNotFound := func(c echo.Context) error {
+		mock := mocks.NewPostService(t)
+		mock.On("ArchivePost", int64(1), c.Get("user")).Return(domain.Post{}, db.ErrNoMoreRows).Times(1)
+		return handlers.NewPostHandler(mock, mocks.NewCommentService(t)).ArchivePost(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "PublishPost scheduled",
+			Request:     requestStatus("publish"),
+			RequestBody: requests.PublishRequest{PublishAt: &publishAt},
+			HandlerFunc: handleFuncPublish,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "\"status\":\"scheduled\",\"created_at\":\"2022-11-01T10:00:00Z\",\"updated_at\":\"2022-11-01T10:00:00Z\",\"published_at\":\"2022-12-01T10:00:00Z\""},
+		},
+		{
+			TestName:    "PublishPost invalid schedule",
+			Request:     requestStatus("publish"),
+			HandlerFunc: handleFuncPublishPast,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Publish time must be in the future\"}\n"},
+		},
+		{
+			TestName:    "UnpublishPost success",
+			Request:     requestStatus("unpublish"),
+			HandlerFunc: handleFuncUnpublish,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "\"status\":\"draft\",\"created_at\":\"2022-11-01T10:00:00Z\",\"updated_at\":\"2022-11-01T10:00:00Z\",\"comments\":null}\n"},
+		},
+		{
+			TestName:    "ArchivePost forbidden",
+			Request:     requestStatus("archive"),
+			HandlerFunc: handleFuncArchiveForbidden,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "{\"code\":403,\"error\":\"Could not archive post: forbidden: not allowed to modify this post\"}\n"},
+		},
+		{
+			TestName:    "ArchivePost not found",
+			Request:     requestStatus("archive"),
+			HandlerFunc: handleFuncArchiveNotFound,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not archive post: upper: no more rows in this result set\"}\n"},
+		},
 	}
 	for _, test := range cases {
 		t.Run(test.TestName, func(t *testing.T) {
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"strings"
+	"trainee/internal/app"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+type PostRevisionHandler struct {
+	rs app.PostRevisionService
+}
+
+func NewPostRevisionHandler(r app.PostRevisionService) PostRevisionHandler {
+	return PostRevisionHandler{
+		rs: r,
+	}
+}
+
+// GetRevisions  	godoc
+// @Summary 		List Post Revisions
+// @Description 	Every revision of a post, the latest first. The first one is the post as it was saved, each
+// @Description 	update adds one. Author only
+// @Tags			Posts Actions
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Success 		200 {array} response.PostRevisionResponse
+// @Failure 		400 {object} response.Error
+// @Failure 		403 {object} response.Error
+// @Failure 		404 {object} response.Error
+// @Failure 		500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/posts/{id}/revisions [get]
+func (r PostRevisionHandler) GetRevisions(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse post ID")
+	}
+	token := ctx.Get("user").(*jwt.Token)
+	revisions, err := r.rs.FindRevisions(id, token)
+	if err != nil {
+		return revisionErrorResponse(ctx, "get revisions", err)
+	}
+	return response.Response(ctx, http.StatusOK, domain.PostRevision{}.AllPostRevisionsDomainToResponse(revisions))
+}
+
+// Diff  			godoc
+// @Summary 		Diff Post Revisions
+// @Description 	Unified diff of the title and body between two revisions of a post, empty when they are the same.
+// @Description 	Author only
+// @Tags			Posts Actions
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Param			from query int true "Revision compared from"
+// @Param			to query int true "Revision compared to"
+// @Success 		200 {object} response.PostDiffResponse
+// @Failure 		400 {object} response.Error
+// @Failure 		403 {object} response.Error
+// @Failure 		404 {object} response.Error
+// @Failure 		422 {object} response.Error
+// @Failure 		500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/posts/{id}/revisions/diff [get]
+func (r PostRevisionHandler) Diff(ctx echo.Context) error {
+	var query requests.PostDiffQuery
+	if err := ctx.Bind(&query); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode query")
+	}
+	if err := ctx.Validate(&query); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate query")
+	}
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse post ID")
+	}
+	token := ctx.Get("user").(*jwt.Token)
+	diff, err := r.rs.Diff(id, query.From, query.To, token)
+	if err != nil {
+		return revisionErrorResponse(ctx, "diff revisions", err)
+	}
+	return response.Response(ctx, http.StatusOK, diff.DomainToResponse())
+}
+
+// Restore  		godoc
+// @Summary 		Restore Post Revision
+// @Description 	Bring back the title and body of a revision, as a new revision. Author only
+// @Tags			Posts Actions
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Param			rev path int true "Revision"
+// @Success 		200 {object} response.PostResponse
+// @Failure 		400 {object} response.Error
+// @Failure 		403 {object} response.Error
+// @Failure 		404 {object} response.Error
+// @Failure 		500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/posts/{id}/revisions/{rev}/restore [post]
+func (r PostRevisionHandler) Restore(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse post ID")
+	}
+	rev, err := strconv.Atoi(ctx.Param("rev"))
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse revision")
+	}
+	token := ctx.Get("user").(*jwt.Token)
+	post, err := r.rs.Restore(id, rev, token)
+	if err != nil {
+		return revisionErrorResponse(ctx, "restore revision", err)
+	}
+	return response.Response(ctx, http.StatusOK, post.DomainToResponse())
+}
+
+func revisionErrorResponse(ctx echo.Context, action string, err error) error {
+	var forbidden app.ForbiddenError
+	if errors.As(err, &forbidden) {
+		return response.ErrorResponse(ctx, http.StatusForbidden, fmt.Sprintf("Could not %s: %s", action, forbidden))
+	} else if errors.Is(err, app.ErrRevisionNotFound) {
+		return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not %s: revision not found", action))
+	} else if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+		return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not %s: post not found", action))
+	}
+	return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not %s: %s", action, err))
+}
@@ -0,0 +1,176 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"net/http"
+	"testing"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+)
+
+func TestPostRevisionHandler(t *testing.T) {
+	revisionMock := domain.PostRevision{PostID: 1, Revision: 2, EditorID: 1, Title: "title", Body: "body", CreatedDate: postDate}
+	revisionJSON := "{\"post_id\":1,\"revision\":2,\"editor_id\":1,\"title\":\"title\",\"body\":\"body\",\"created_at\":\"2022-11-01T10:00:00Z\"}"
+	diffMock := domain.PostDiff{PostID: 1, From: 1, To: 2, Diff: "--- revision 1\n+++ revision 2\n@@ -1 +1 @@\n-old\n+title\n"}
+	diffJSON := "{\"post_id\":1,\"from\":1,\"to\":2,\"diff\":\"--- revision 1\\n+++ revision 2\\n@@ -1 +1 @@\\n-old\\n+title\\n\"}"
+
+	requestList := test_case.Request{
+		Method:    http.MethodGet,
+		Url:       "/posts/" + postID + "/revisions",
+		PathParam: &test_case.PathParam{Name: "id", Value: postID},
+	}
+	requestListError := test_case.Request{
+		Method:    http.MethodGet,
+		Url:       "/posts/" + postIDError + "/revisions",
+		PathParam: &test_case.PathParam{Name: "id", Value: postIDError},
+	}
+	requestDiff := test_case.Request{
+		Method:    http.MethodGet,
+		Url:       "/posts/" + postID + "/revisions/diff?from=1&to=2",
+		PathParam: &test_case.PathParam{Name: "id", Value: postID},
+	}
+	requestDiffNoRange := test_case.Request{
+		Method:    http.MethodGet,
+		Url:       "/posts/" + postID + "/revisions/diff?from=1",
+		PathParam: &test_case.PathParam{Name: "id", Value: postID},
+	}
+	requestRestore := test_case.Request{
+		Method: http.MethodPost,
+		Url:    "/posts/" + postID + "/revisions/1/restore",
+	}
+
+	handleList := func(err error) func(c echo.Context) error {
+		return func(c echo.Context) error {
+			mockRevisions := mocks.NewPostRevisionService(t)
+			mockRevisions.On("FindRevisions", int64(1), c.Get("user")).Return([]domain.PostRevision{revisionMock}, err).Times(1)
+			return handlers.NewPostRevisionHandler(mockRevisions).GetRevisions(c)
+		}
+	}
+
+	handleListInvalid := func(c echo.Context) error {
+		return handlers.NewPostRevisionHandler(mocks.NewPostRevisionService(t)).GetRevisions(c)
+	}
+
+	handleDiff := func(err error) func(c echo.Context) error {
+		return func(c echo.Context) error {
+			mockRevisions := mocks.NewPostRevisionService(t)
+			mockRevisions.On("Diff", int64(1), 1, 2, c.Get("user")).Return(diffMock, err).Times(1)
+			return handlers.NewPostRevisionHandler(mockRevisions).Diff(c)
+		}
+	}
+
+	handleDiffInvalid := func(c echo.Context) error {
+		return handlers.NewPostRevisionHandler(mocks.NewPostRevisionService(t)).Diff(c)
+	}
+
+	handleRestore := func(rev string, err error) func(c echo.Context) error {
+		return func(c echo.Context) error {
+			c.SetParamNames("id", "rev")
+			c.SetParamValues(postID, rev)
+			mockRevisions := mocks.NewPostRevisionService(t)
+			if rev == "1" {
+				mockRevisions.On("Restore", int64(1), 1, c.Get("user")).Return(returnDomainPostMock, err).Times(1)
+			}
+			return handlers.NewPostRevisionHandler(mockRevisions).Restore(c)
+		}
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "GetRevisions success",
+			Request:     requestList,
+			HandlerFunc: handleList(nil),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "[" + revisionJSON + "]\n"},
+		},
+		{
+			TestName:    "GetRevisions bad ID",
+			Request:     requestListError,
+			HandlerFunc: handleListInvalid,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Could not parse post ID\"}\n"},
+		},
+		{
+			TestName:    "GetRevisions not author",
+			Request:     requestList,
+			HandlerFunc: handleList(app.ForbiddenError{Action: "modify this post"}),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "Could not get revisions"},
+		},
+		{
+			TestName:    "GetRevisions post not found",
+			Request:     requestList,
+			HandlerFunc: handleList(db.ErrNoMoreRows),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not get revisions: post not found\"}\n"},
+		},
+		{
+			TestName:    "Diff success",
+			Request:     requestDiff,
+			HandlerFunc: handleDiff(nil),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   diffJSON + "\n"},
+		},
+		{
+			TestName:    "Diff revision not found",
+			Request:     requestDiff,
+			HandlerFunc: handleDiff(app.ErrRevisionNotFound),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not diff revisions: revision not found\"}\n"},
+		},
+		{
+			TestName:    "Diff without range",
+			Request:     requestDiffNoRange,
+			HandlerFunc: handleDiffInvalid,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate query\"}\n"},
+		},
+		{
+			TestName:    "Restore success",
+			Request:     requestRestore,
+			HandlerFunc: handleRestore("1", nil),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   postJSON + "\n"},
+		},
+		{
+			TestName:    "Restore revision not found",
+			Request:     requestRestore,
+			HandlerFunc: handleRestore("1", app.ErrRevisionNotFound),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not restore revision: revision not found\"}\n"},
+		},
+		{
+			TestName:    "Restore bad revision",
+			Request:     requestRestore,
+			HandlerFunc: handleRestore("a", nil),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Could not parse revision\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, test.Expected.StatusCode, recorder.Code)
+			}
+		})
+	}
+}
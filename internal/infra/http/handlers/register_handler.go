@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"trainee/internal/app"
 	"trainee/internal/domain"
@@ -31,6 +35,8 @@ func NewRegisterHandler(a app.AuthService) RegisterHandler {
 // @Param			input body requests.RegisterAuth true "users email, users password"
 // @Success 		201 {object} response.UserResponse
 // @Failure			400 {object} response.Error
+// @Failure			422 {object} response.FieldError
+// @Failure			500 {object} response.Error
 // @Router			/register [post]
 func (r RegisterHandler) Register(ctx echo.Context) error {
 	var registerUser requests.RegisterAuth
@@ -45,6 +51,10 @@ func (r RegisterHandler) Register(ctx echo.Context) error {
 
 	user, err := r.as.Register(userFromRegister)
 	if err != nil {
+		var policy *app.PasswordPolicyError
+		if errors.As(err, &policy) {
+			return passwordPolicyResponse(ctx, policy)
+		}
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not save new user: %s", err))
 	}
 	userResponse := domain.User.DomainToResponse(user)
@@ -59,8 +69,11 @@ func (r RegisterHandler) Register(ctx echo.Context) error {
 // @Produce 		json
 // @Param			input body requests.LoginAuth true "users email, users password"
 // @Success 		201 {object} response.LoginResponse
+// @Success 		202 {object} response.MFARequiredResponse
 // @Failure			400 {object} response.Error
 // @Failure			401 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			429 {object} response.Error
 // @Failure			500 {object} response.Error
 // @Router			/login [post]
 func (r RegisterHandler) Login(ctx echo.Context) error {
@@ -71,10 +84,20 @@ func (r RegisterHandler) Login(ctx echo.Context) error {
 	if err := ctx.Validate(&authUser); err != nil {
 		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate user data")
 	}
-	accessToken, refreshToken, exp, err := r.as.Login(authUser)
+	accessToken, refreshToken, exp, err := r.as.Login(authUser, device(ctx))
 	if err != nil {
+		var mfaRequired *app.MFARequiredError
+		if errors.As(err, &mfaRequired) {
+			return response.Response(ctx, http.StatusAccepted, response.MFARequiredResponse{MFAToken: mfaRequired.Token, Exp: mfaRequired.Exp})
+		}
+		var locked *app.LockedError
+		if errors.As(err, &locked) {
+			return lockedResponse(ctx, locked)
+		}
 		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
 			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not login, user not exists: %s", err))
+		} else if errors.Is(err, app.ErrEmailNotVerified) {
+			return response.ErrorResponse(ctx, http.StatusForbidden, "Could not login, email is not verified")
 		} else {
 			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not login user: %s", err))
 		}
@@ -82,3 +105,125 @@ func (r RegisterHandler) Login(ctx echo.Context) error {
 	res := response.NewLoginResponse(accessToken, refreshToken, exp)
 	return response.Response(ctx, http.StatusOK, res)
 }
+
+// LoginMFA 		godoc
+// @Summary 		Login with mfa code
+// @Description 	Finish a login answered with an mfa token, using a TOTP or recovery code
+// @Tags			Auth Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.LoginMFA true "mfa token, code"
+// @Success 		200 {object} response.LoginResponse
+// @Failure			400 {object} response.Error
+// @Failure			401 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			429 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Router			/login/mfa [post]
+func (r RegisterHandler) LoginMFA(ctx echo.Context) error {
+	var loginMFA requests.LoginMFA
+	if err := ctx.Bind(&loginMFA); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode mfa data")
+	}
+	if err := ctx.Validate(&loginMFA); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate mfa data")
+	}
+	accessToken, refreshToken, exp, err := r.as.LoginMFA(loginMFA.MFAToken, loginMFA.Code)
+	if err != nil {
+		var locked *app.LockedError
+		if errors.As(err, &locked) {
+			return lockedResponse(ctx, locked)
+		}
+		if errors.Is(err, app.ErrInvalidToken) || errors.Is(err, app.ErrInvalidMFACode) {
+			return response.ErrorResponse(ctx, http.StatusUnauthorized, "Invalid mfa token or code")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not login user: %s", err))
+	}
+	res := response.NewLoginResponse(accessToken, refreshToken, exp)
+	return response.Response(ctx, http.StatusOK, res)
+}
+
+// Refresh 			godoc
+// @Summary 		Refresh tokens
+// @Description 	Exchange a refresh token for a new access and refresh token pair
+// @Tags			Auth Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.RefreshAuth true "refresh token"
+// @Success 		200 {object} response.LoginResponse
+// @Failure			400 {object} response.Error
+// @Failure			401 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Router			/refresh [post]
+func (r RegisterHandler) Refresh(ctx echo.Context) error {
+	var refreshRequest requests.RefreshAuth
+	if err := ctx.Bind(&refreshRequest); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode token data")
+	}
+	if err := ctx.Validate(&refreshRequest); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate token data")
+	}
+	accessToken, refreshToken, exp, err := r.as.Refresh(refreshRequest.RefreshToken, device(ctx))
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidToken) {
+			return response.ErrorResponse(ctx, http.StatusUnauthorized, "Not authorized")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not refresh tokens: %s", err))
+	}
+	res := response.NewLoginResponse(accessToken, refreshToken, exp)
+	return response.Response(ctx, http.StatusOK, res)
+}
+
+// Logout 			godoc
+// @Summary 		Logout
+// @Description 	Revoke the current session
+// @Tags			Auth Actions
+// @Produce 		json
+// @Success 		200 {object} response.Data
+// @Failure			401 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/logout [post]
+func (r RegisterHandler) Logout(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	err := r.as.Logout(claims.SID, claims.ID, device(ctx))
+	if err != nil {
+		if errors.Is(err, app.ErrSessionNotFound) {
+			return response.ErrorResponse(ctx, http.StatusUnauthorized, "Not authorized")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not logout: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "Successfully logged out")
+}
+
+// LogoutAll 		godoc
+// @Summary 		Logout from all sessions
+// @Description 	Revoke every session of the current user
+// @Tags			Auth Actions
+// @Produce 		json
+// @Success 		200 {object} response.Data
+// @Failure			401 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/logout/all [post]
+func (r RegisterHandler) LogoutAll(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	err := r.as.LogoutAll(claims.ID, device(ctx))
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not logout: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "Successfully logged out from all sessions")
+}
+
+func lockedResponse(ctx echo.Context, locked *app.LockedError) error {
+	ctx.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	return response.ErrorResponse(ctx, http.StatusTooManyRequests, "Too many failed logins, try again later")
+}
+
+func device(ctx echo.Context) domain.Device {
+	return domain.Device{
+		UserAgent: ctx.Request().UserAgent(),
+		IP:        ctx.RealIP(),
+	}
+}
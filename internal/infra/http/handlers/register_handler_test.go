@@ -6,6 +6,7 @@ import (
 	"github.com/upper/db/v4"
 	"net/http"
 	"testing"
+	"time"
 	"trainee/internal/app"
 	"trainee/internal/app/mocks"
 	"trainee/internal/domain"
@@ -14,6 +15,10 @@ import (
 	"trainee/internal/infra/http/requests"
 )
 
+var loginDevice = domain.Device{
+	IP: "192.0.2.1",
+}
+
 func TestRegisterHandler_Register(t *testing.T) {
 	userMockRequest := requests.RegisterAuth{
 		Email:    "user@mail.com",
@@ -26,6 +31,7 @@ func TestRegisterHandler_Register(t *testing.T) {
 		Email:    "user@mail.com",
 		Name:     "Name",
 		Password: "qwerty1234",
+		Role:     domain.RoleUser,
 	}
 
 	requestRegister := test_case.Request{
@@ -60,6 +66,21 @@ func TestRegisterHandler_Register(t *testing.T) {
 		return handlers.NewRegisterHandler(mockAuth).Register(c)
 	}
 
+	handleErrorPolicy := func(c echo.Context) error {
+		mockAuth := func(user requests.RegisterAuth) app.AuthService {
+			mock := mocks.NewAuthService(t)
+			userDomain := domain.User{
+				Email:    user.Email,
+				Name:     user.Name,
+				Password: user.Password,
+			}
+			mock.On("Register", userDomain).
+				Return(domain.User{}, &app.PasswordPolicyError{Violations: []string{"has appeared in a data breach, choose another one"}}).Times(1)
+			return mock
+		}(userMockRequest)
+		return handlers.NewRegisterHandler(mockAuth).Register(c)
+	}
+
 	handleMock := func(c echo.Context) error {
 		mockAuth := func() app.AuthService {
 			return mocks.NewAuthService(t)
@@ -75,7 +96,7 @@ func TestRegisterHandler_Register(t *testing.T) {
 			HandlerFunc: handleSuccessCreate,
 			Expected: test_case.ExpectedResponse{
 				StatusCode: 201,
-				BodyPart:   "{\"id\":1,\"email\":\"user@mail.com\",\"name\":\"Name\"}\n"},
+				BodyPart:   "{\"id\":1,\"email\":\"user@mail.com\",\"name\":\"Name\",\"role\":\"user\",\"email_verified\":false}\n"},
 		},
 		{
 			TestName:    "RegisterUser error",
@@ -86,6 +107,15 @@ func TestRegisterHandler_Register(t *testing.T) {
 				StatusCode: 500,
 				BodyPart:   "{\"code\":500,\"error\":\"Could not save new user: upper: collection does not exist\"}\n"},
 		},
+		{
+			TestName:    "RegisterUser password breaks the policy",
+			Request:     requestRegister,
+			RequestBody: userMockRequest,
+			HandlerFunc: handleErrorPolicy,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Password does not meet the policy\",\"fields\":{\"password\":[\"has appeared in a data breach, choose another one\"]}}\n"},
+		},
 		{
 			TestName:    "Error decode user data",
 			Request:     requestRegister,
@@ -135,7 +165,7 @@ func TestRegisterHandler_Login(t *testing.T) {
 	handleSuccessLogin := func(c echo.Context) error {
 		mockAuth := func(user requests.LoginAuth) app.AuthService {
 			mock := mocks.NewAuthService(t)
-			mock.On("Login", user).Return("access", "refresh", int64(123), nil).Times(1)
+			mock.On("Login", user, loginDevice).Return("access", "refresh", int64(123), nil).Times(1)
 			return mock
 		}(userMockRequest)
 		return handlers.NewRegisterHandler(mockAuth).Login(c)
@@ -144,7 +174,7 @@ func TestRegisterHandler_Login(t *testing.T) {
 	handleErrorLoginNoMoreRows := func(c echo.Context) error {
 		mockAuth := func(user requests.LoginAuth) app.AuthService {
 			mock := mocks.NewAuthService(t)
-			mock.On("Login", user).Return("", "", int64(0), db.ErrNoMoreRows).Times(1)
+			mock.On("Login", user, loginDevice).Return("", "", int64(0), db.ErrNoMoreRows).Times(1)
 			return mock
 		}(userMockRequest)
 		return handlers.NewRegisterHandler(mockAuth).Login(c)
@@ -153,7 +183,34 @@ func TestRegisterHandler_Login(t *testing.T) {
 	handleErrorLoginInternalServerError := func(c echo.Context) error {
 		mockAuth := func(user requests.LoginAuth) app.AuthService {
 			mock := mocks.NewAuthService(t)
-			mock.On("Login", user).Return("", "", int64(0), db.ErrCollectionDoesNotExist).Times(1)
+			mock.On("Login", user, loginDevice).Return("", "", int64(0), db.ErrCollectionDoesNotExist).Times(1)
+			return mock
+		}(userMockRequest)
+		return handlers.NewRegisterHandler(mockAuth).Login(c)
+	}
+
+	handleLoginMFARequired := func(c echo.Context) error {
+		mockAuth := func(user requests.LoginAuth) app.AuthService {
+			mock := mocks.NewAuthService(t)
+			mock.On("Login", user, loginDevice).Return("", "", int64(0), &app.MFARequiredError{Token: "mfa", Exp: 123}).Times(1)
+			return mock
+		}(userMockRequest)
+		return handlers.NewRegisterHandler(mockAuth).Login(c)
+	}
+
+	handleErrorLoginNotVerified := func(c echo.Context) error {
+		mockAuth := func(user requests.LoginAuth) app.AuthService {
+			mock := mocks.NewAuthService(t)
+			mock.On("Login", user, loginDevice).Return("", "", int64(0), app.ErrEmailNotVerified).Times(1)
+			return mock
+		}(userMockRequest)
+		return handlers.NewRegisterHandler(mockAuth).Login(c)
+	}
+
+	handleErrorLoginLocked := func(c echo.Context) error {
+		mockAuth := func(user requests.LoginAuth) app.AuthService {
+			mock := mocks.NewAuthService(t)
+			mock.On("Login", user, loginDevice).Return("", "", int64(0), &app.LockedError{RetryAfter: 90 * time.Second}).Times(1)
 			return mock
 		}(userMockRequest)
 		return handlers.NewRegisterHandler(mockAuth).Login(c)
@@ -194,6 +251,33 @@ func TestRegisterHandler_Login(t *testing.T) {
 				StatusCode: 500,
 				BodyPart:   "{\"code\":500,\"error\":\"Could not login user: upper: collection does not exist\"}\n"},
 		},
+		{
+			TestName:    "LoginUser mfa required",
+			Request:     requestRegister,
+			RequestBody: userMockRequest,
+			HandlerFunc: handleLoginMFARequired,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 202,
+				BodyPart:   "{\"mfaToken\":\"mfa\",\"exp\":123}\n"},
+		},
+		{
+			TestName:    "LoginUser error email not verified",
+			Request:     requestRegister,
+			RequestBody: userMockRequest,
+			HandlerFunc: handleErrorLoginNotVerified,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "{\"code\":403,\"error\":\"Could not login, email is not verified\"}\n"},
+		},
+		{
+			TestName:    "LoginUser error locked",
+			Request:     requestRegister,
+			RequestBody: userMockRequest,
+			HandlerFunc: handleErrorLoginLocked,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 429,
+				BodyPart:   "{\"code\":429,\"error\":\"Too many failed logins, try again later\"}\n"},
+		},
 		{
 			TestName:    "Error decode user data",
 			Request:     requestRegister,
@@ -228,3 +312,266 @@ func TestRegisterHandler_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterHandler_Refresh(t *testing.T) {
+	refreshMockRequest := requests.RefreshAuth{
+		RefreshToken: "refresh",
+	}
+
+	requestRefresh := test_case.Request{
+		Method: http.MethodPost,
+		Url:    "/refresh",
+	}
+	handleSuccessRefresh := func(c echo.Context) error {
+		mockAuth := func(token string) app.AuthService {
+			mock := mocks.NewAuthService(t)
+			mock.On("Refresh", token, loginDevice).Return("newAccess", "newRefresh", int64(123), nil).Times(1)
+			return mock
+		}(refreshMockRequest.RefreshToken)
+		return handlers.NewRegisterHandler(mockAuth).Refresh(c)
+	}
+
+	handleErrorRefreshInvalidToken := func(c echo.Context) error {
+		mockAuth := func(token string) app.AuthService {
+			mock := mocks.NewAuthService(t)
+			mock.On("Refresh", token, loginDevice).Return("", "", int64(0), app.ErrInvalidToken).Times(1)
+			return mock
+		}(refreshMockRequest.RefreshToken)
+		return handlers.NewRegisterHandler(mockAuth).Refresh(c)
+	}
+
+	handleErrorRefreshInternalServerError := func(c echo.Context) error {
+		mockAuth := func(token string) app.AuthService {
+			mock := mocks.NewAuthService(t)
+			mock.On("Refresh", token, loginDevice).Return("", "", int64(0), db.ErrCollectionDoesNotExist).Times(1)
+			return mock
+		}(refreshMockRequest.RefreshToken)
+		return handlers.NewRegisterHandler(mockAuth).Refresh(c)
+	}
+
+	handleMock := func(c echo.Context) error {
+		mockAuth := func() app.AuthService {
+			return mocks.NewAuthService(t)
+		}()
+		return handlers.NewRegisterHandler(mockAuth).Refresh(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "Refresh success",
+			Request:     requestRefresh,
+			RequestBody: refreshMockRequest,
+			HandlerFunc: handleSuccessRefresh,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"accessToken\":\"newAccess\",\"refreshToken\":\"newRefresh\",\"exp\":123}\n"},
+		},
+		{
+			TestName:    "Refresh error invalid token",
+			Request:     requestRefresh,
+			RequestBody: refreshMockRequest,
+			HandlerFunc: handleErrorRefreshInvalidToken,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 401,
+				BodyPart:   "{\"code\":401,\"error\":\"Not authorized\"}\n"},
+		},
+		{
+			TestName:    "Refresh error internal server error",
+			Request:     requestRefresh,
+			RequestBody: refreshMockRequest,
+			HandlerFunc: handleErrorRefreshInternalServerError,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not refresh tokens: upper: collection does not exist\"}\n"},
+		},
+		{
+			TestName:    "Error decode token data",
+			Request:     requestRefresh,
+			RequestBody: "",
+			HandlerFunc: handleMock,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Could not decode token data\"}\n"},
+		},
+		{
+			TestName:    "Error validate token data",
+			Request:     requestRefresh,
+			RequestBody: requests.RefreshAuth{},
+			HandlerFunc: handleMock,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate token data\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRegisterHandler_Logout(t *testing.T) {
+	requestLogout := test_case.Request{
+		Method: http.MethodPost,
+		Url:    "/logout",
+	}
+	handleSuccessLogout := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("Logout", "", int64(1), loginDevice).Return(nil).Times(1)
+		return handlers.NewRegisterHandler(mockAuth).Logout(c)
+	}
+
+	handleErrorLogoutSessionNotFound := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("Logout", "", int64(1), loginDevice).Return(app.ErrSessionNotFound).Times(1)
+		return handlers.NewRegisterHandler(mockAuth).Logout(c)
+	}
+
+	handleSuccessLogoutAll := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("LogoutAll", int64(1), loginDevice).Return(nil).Times(1)
+		return handlers.NewRegisterHandler(mockAuth).LogoutAll(c)
+	}
+
+	handleErrorLogoutAll := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("LogoutAll", int64(1), loginDevice).Return(db.ErrCollectionDoesNotExist).Times(1)
+		return handlers.NewRegisterHandler(mockAuth).LogoutAll(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "Logout success",
+			Request:     requestLogout,
+			HandlerFunc: handleSuccessLogout,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"Successfully logged out\"}\n"},
+		},
+		{
+			TestName:    "Logout error session not found",
+			Request:     requestLogout,
+			HandlerFunc: handleErrorLogoutSessionNotFound,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 401,
+				BodyPart:   "{\"code\":401,\"error\":\"Not authorized\"}\n"},
+		},
+		{
+			TestName:    "LogoutAll success",
+			Request:     requestLogout,
+			HandlerFunc: handleSuccessLogoutAll,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"Successfully logged out from all sessions\"}\n"},
+		},
+		{
+			TestName:    "LogoutAll error",
+			Request:     requestLogout,
+			HandlerFunc: handleErrorLogoutAll,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not logout: upper: collection does not exist\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRegisterHandler_LoginMFA(t *testing.T) {
+	mfaMockRequest := requests.LoginMFA{
+		MFAToken: "mfa",
+		Code:     "123456",
+	}
+
+	requestLoginMFA := test_case.Request{
+		Method: http.MethodPost,
+		Url:    "/login/mfa",
+	}
+
+	handleSuccess := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("LoginMFA", "mfa", "123456").Return("access", "refresh", int64(123), nil).Times(1)
+		return handlers.NewRegisterHandler(mockAuth).LoginMFA(c)
+	}
+
+	handleInvalidCode := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("LoginMFA", "mfa", "123456").Return("", "", int64(0), app.ErrInvalidMFACode).Times(1)
+		return handlers.NewRegisterHandler(mockAuth).LoginMFA(c)
+	}
+
+	handleExpiredToken := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("LoginMFA", "mfa", "123456").Return("", "", int64(0), app.ErrInvalidToken).Times(1)
+		return handlers.NewRegisterHandler(mockAuth).LoginMFA(c)
+	}
+
+	handleLocked := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("LoginMFA", "mfa", "123456").Return("", "", int64(0), &app.LockedError{RetryAfter: time.Minute}).Times(1)
+		return handlers.NewRegisterHandler(mockAuth).LoginMFA(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "LoginMFA success",
+			Request:     requestLoginMFA,
+			RequestBody: mfaMockRequest,
+			HandlerFunc: handleSuccess,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"accessToken\":\"access\",\"refreshToken\":\"refresh\",\"exp\":123}\n"},
+		},
+		{
+			TestName:    "LoginMFA invalid code",
+			Request:     requestLoginMFA,
+			RequestBody: mfaMockRequest,
+			HandlerFunc: handleInvalidCode,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 401,
+				BodyPart:   "{\"code\":401,\"error\":\"Invalid mfa token or code\"}\n"},
+		},
+		{
+			TestName:    "LoginMFA expired token",
+			Request:     requestLoginMFA,
+			RequestBody: mfaMockRequest,
+			HandlerFunc: handleExpiredToken,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 401,
+				BodyPart:   "{\"code\":401,\"error\":\"Invalid mfa token or code\"}\n"},
+		},
+		{
+			TestName:    "LoginMFA locked",
+			Request:     requestLoginMFA,
+			RequestBody: mfaMockRequest,
+			HandlerFunc: handleLocked,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 429,
+				BodyPart:   "{\"code\":429,\"error\":\"Too many failed logins, try again later\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
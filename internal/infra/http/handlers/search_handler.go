@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"trainee/internal/app"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+type SearchHandler struct {
+	ss app.SearchService
+}
+
+func NewSearchHandler(s app.SearchService) SearchHandler {
+	return SearchHandler{
+		ss: s,
+	}
+}
+
+// Search 			godoc
+// @Summary 		Search posts and comments
+// @Description 	Full-text search over post titles and bodies and comment bodies, best match first. The query takes
+// @Description 	words, "quoted phrases", or between alternatives and -excluded words. Snippets are HTML with the
+// @Description 	hits in mark elements
+// @Tags			Posts Actions
+// @Produce 		json
+// @Param			q query string true "Search query"
+// @Param			type query string false "post or comment, both by default"
+// @Param			limit query int false "Page size, 20 by default and 100 at most"
+// @Param			offset query int false "Results to skip"
+// @Success 		200 {array} response.SearchResultResponse
+// @Failure			400 {object} response.Error
+// @Failure			401 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/search [get]
+func (s SearchHandler) Search(ctx echo.Context) error {
+	var search requests.SearchRequest
+	if err := ctx.Bind(&search); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode query")
+	}
+	if err := ctx.Validate(&search); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate query")
+	}
+	results, err := s.ss.Search(search.RequestToQuery())
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not search: %s", err))
+	}
+	return response.Response(ctx, http.StatusOK, domain.SearchResult{}.AllSearchResultsDomainToResponse(results))
+}
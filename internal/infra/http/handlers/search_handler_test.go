@@ -0,0 +1,105 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"net/http"
+	"testing"
+	"time"
+	"trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+)
+
+func TestSearchHandler(t *testing.T) {
+	resultsMock := []domain.SearchResult{
+		{
+			Type:        domain.SearchResultComment,
+			ID:          3,
+			PostID:      2,
+			UserID:      1,
+			Title:       "Lorem",
+			Snippet:     "<mark>ipsum</mark> dolor",
+			Rank:        0.5,
+			CreatedDate: time.Date(2022, 11, 17, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	query := domain.SearchQuery{Query: "ipsum -sit", Type: domain.SearchResultComment, Limit: 10, Offset: 20}
+
+	requestSearch := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/search?q=ipsum+-sit&type=comment&limit=10&offset=20",
+	}
+	requestNoQuery := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/search?type=post",
+	}
+	requestBadType := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/search?q=ipsum&type=user",
+	}
+
+	handleSearch := func(c echo.Context) error {
+		mockSearch := mocks.NewSearchService(t)
+		mockSearch.On("Search", query).Return(resultsMock, nil).Times(1)
+		return handlers.NewSearchHandler(mockSearch).Search(c)
+	}
+
+	handleSearchError := func(c echo.Context) error {
+		mockSearch := mocks.NewSearchService(t)
+		mockSearch.On("Search", query).Return(nil, db.ErrCollectionDoesNotExist).Times(1)
+		return handlers.NewSearchHandler(mockSearch).Search(c)
+	}
+
+	handleInvalid := func(c echo.Context) error {
+		return handlers.NewSearchHandler(mocks.NewSearchService(t)).Search(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "Search success",
+			Request:     requestSearch,
+			HandlerFunc: handleSearch,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "[{\"type\":\"comment\",\"id\":3,\"post_id\":2,\"user_id\":1,\"title\":\"Lorem\",\"snippet\":\"\\u003cmark\\u003eipsum\\u003c/mark\\u003e dolor\",\"rank\":0.5,\"created_at\":\"2022-11-17T10:00:00Z\"}]\n"},
+		},
+		{
+			TestName:    "Search error",
+			Request:     requestSearch,
+			HandlerFunc: handleSearchError,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not search: upper: collection does not exist\"}\n"},
+		},
+		{
+			TestName:    "Search without query",
+			Request:     requestNoQuery,
+			HandlerFunc: handleInvalid,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate query\"}\n"},
+		},
+		{
+			TestName:    "Search unknown type",
+			Request:     requestBadType,
+			HandlerFunc: handleInvalid,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate query\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
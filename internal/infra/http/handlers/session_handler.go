@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"trainee/internal/app"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/response"
+)
+
+type SessionHandler struct {
+	as app.AuthService
+}
+
+func NewSessionHandler(a app.AuthService) SessionHandler {
+	return SessionHandler{
+		as: a,
+	}
+}
+
+// GetSessions 		godoc
+// @Summary 		List sessions
+// @Description 	List active sessions of the current user
+// @Tags			Sessions Actions
+// @Produce 		json
+// @Success 		200 {array} response.SessionResponse
+// @Failure			401 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/sessions [get]
+func (s SessionHandler) GetSessions(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	sessions, err := s.as.GetSessions(claims.ID)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not get sessions: %s", err))
+	}
+	dom := domain.Session{}
+	return response.Response(ctx, http.StatusOK, dom.AllSessionsDomainToResponse(sessions, claims.SID))
+}
+
+// DeleteSession 	godoc
+// @Summary 		Revoke session
+// @Description 	Revoke one session of the current user
+// @Tags			Sessions Actions
+// @Produce 		json
+// @Param			id path string true "Session ID"
+// @Success 		200 {object} response.Data
+// @Failure			401 {object} response.Error
+// @Failure			404 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/sessions/{id} [delete]
+func (s SessionHandler) DeleteSession(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	err := s.as.RevokeSession(ctx.Param("id"), claims.ID)
+	if err != nil {
+		if errors.Is(err, app.ErrSessionNotFound) {
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Could not revoke session: session not found")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not revoke session: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "Session revoked")
+}
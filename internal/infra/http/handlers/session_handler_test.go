@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"net/http"
+	"testing"
+	"time"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+)
+
+const sessionID = "6f1c0e5e-8a0e-4f43-9d0c-3e1f3f6b7f1a"
+
+func TestSessionHandler(t *testing.T) {
+	seen := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	sessionsMock := []domain.Session{
+		{
+			ID:         sessionID,
+			UserID:     1,
+			UserAgent:  "Mozilla/5.0",
+			IP:         "127.0.0.1",
+			CreatedAt:  seen,
+			LastSeenAt: seen,
+			ExpiresAt:  seen.Add(30 * 24 * time.Hour),
+		},
+	}
+
+	requestList := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/sessions",
+	}
+
+	requestDelete := test_case.Request{
+		Method: http.MethodDelete,
+		Url:    "/sessions/" + sessionID,
+		PathParam: &test_case.PathParam{
+			Name:  "id",
+			Value: sessionID,
+		},
+	}
+
+	handleSuccessList := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("GetSessions", int64(1)).Return(sessionsMock, nil).Times(1)
+		return handlers.NewSessionHandler(mockAuth).GetSessions(c)
+	}
+
+	handleErrorList := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("GetSessions", int64(1)).Return(nil, db.ErrCollectionDoesNotExist).Times(1)
+		return handlers.NewSessionHandler(mockAuth).GetSessions(c)
+	}
+
+	handleSuccessDelete := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("RevokeSession", sessionID, int64(1)).Return(nil).Times(1)
+		return handlers.NewSessionHandler(mockAuth).DeleteSession(c)
+	}
+
+	handleErrorDeleteNotFound := func(c echo.Context) error {
+		mockAuth := mocks.NewAuthService(t)
+		mockAuth.On("RevokeSession", sessionID, int64(1)).Return(app.ErrSessionNotFound).Times(1)
+		return handlers.NewSessionHandler(mockAuth).DeleteSession(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "GetSessions success",
+			Request:     requestList,
+			HandlerFunc: handleSuccessList,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "[{\"id\":\"" + sessionID + "\",\"user_agent\":\"Mozilla/5.0\",\"ip\":\"127.0.0.1\",\"created_at\":\"2022-11-01T10:00:00Z\",\"last_seen_at\":\"2022-11-01T10:00:00Z\",\"expires_at\":\"2022-12-01T10:00:00Z\",\"current\":false}]\n"},
+		},
+		{
+			TestName:    "GetSessions error",
+			Request:     requestList,
+			HandlerFunc: handleErrorList,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not get sessions: upper: collection does not exist\"}\n"},
+		},
+		{
+			TestName:    "DeleteSession success",
+			Request:     requestDelete,
+			HandlerFunc: handleSuccessDelete,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"Session revoked\"}\n"},
+		},
+		{
+			TestName:    "DeleteSession not found",
+			Request:     requestDelete,
+			HandlerFunc: handleErrorDeleteNotFound,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not revoke session: session not found\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"trainee/internal/app"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+type TagHandler struct {
+	ts app.TagService
+}
+
+func NewTagHandler(t app.TagService) TagHandler {
+	return TagHandler{
+		ts: t,
+	}
+}
+
+// GetTags 			godoc
+// @Summary 		List Tags
+// @Description 	Every tag with the number of posts carrying it, the most used first
+// @Tags			Posts Actions
+// @Produce 		json
+// @Success 		200 {array} response.TagResponse
+// @Failure			401 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/tags [get]
+func (t TagHandler) GetTags(ctx echo.Context) error {
+	tags, err := t.ts.FindTags()
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not get tags: %s", err))
+	}
+	return response.Response(ctx, http.StatusOK, domain.Tag{}.AllTagsDomainToResponse(tags))
+}
+
+// RenameTag 		godoc
+// @Summary 		Rename Tag
+// @Description 	Rename a tag, the name is normalized like the tags of posts. A name another tag has is refused,
+// @Description 	merge the tags instead. Admin only
+// @Tags			Admin Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Param			input body requests.TagRequest true "tag name"
+// @Success 		200 {object} response.TagResponse
+// @Failure			400 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			404 {object} response.Error
+// @Failure			409 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/admin/tags/{id} [put]
+func (t TagHandler) RenameTag(ctx echo.Context) error {
+	var tagRequest requests.TagRequest
+	if err := ctx.Bind(&tagRequest); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode tag data")
+	}
+	if err := ctx.Validate(&tagRequest); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate tag data")
+	}
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse tag ID")
+	}
+	tag, err := t.ts.RenameTag(id, tagRequest.Name)
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidTag) {
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Invalid tag")
+		} else if errors.Is(err, app.ErrTagNotFound) {
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Could not rename tag: tag not found")
+		} else if errors.Is(err, app.ErrTagExists) {
+			return response.ErrorResponse(ctx, http.StatusConflict, "Could not rename tag: tag already exists")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not rename tag: %s", err))
+	}
+	return response.Response(ctx, http.StatusOK, tag.DomainToResponse())
+}
+
+// MergeTags 		godoc
+// @Summary 		Merge Tags
+// @Description 	Move the posts of a tag to another one and delete it, admin only
+// @Tags			Admin Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			id path int true "ID of the tag merged and deleted"
+// @Param			input body requests.MergeTagsRequest true "tag kept"
+// @Success 		200 {object} response.TagResponse
+// @Failure			400 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			404 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/admin/tags/{id}/merge [post]
+func (t TagHandler) MergeTags(ctx echo.Context) error {
+	var mergeRequest requests.MergeTagsRequest
+	if err := ctx.Bind(&mergeRequest); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode merge data")
+	}
+	if err := ctx.Validate(&mergeRequest); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate merge data")
+	}
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse tag ID")
+	}
+	tag, err := t.ts.MergeTags(id, mergeRequest.Into)
+	if err != nil {
+		if errors.Is(err, app.ErrTagMergeItself) {
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not merge a tag into itself")
+		} else if errors.Is(err, app.ErrTagNotFound) {
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Could not merge tags: tag not found")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not merge tags: %s", err))
+	}
+	return response.Response(ctx, http.StatusOK, tag.DomainToResponse())
+}
@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"net/http"
+	"testing"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+	"trainee/internal/infra/http/requests"
+)
+
+func TestTagHandler(t *testing.T) {
+	tagMock := domain.Tag{ID: 1, Name: "golang", PostCount: 12}
+	tagJSON := "{\"id\":1,\"name\":\"golang\",\"post_count\":12}"
+
+	requestList := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/tags",
+	}
+	requestRename := test_case.Request{
+		Method:    http.MethodPut,
+		Url:       "/admin/tags/1",
+		PathParam: &test_case.PathParam{Name: "id", Value: "1"},
+	}
+	requestMerge := test_case.Request{
+		Method:    http.MethodPost,
+		Url:       "/admin/tags/2/merge",
+		PathParam: &test_case.PathParam{Name: "id", Value: "2"},
+	}
+
+	handleList := func(c echo.Context) error {
+		mockTags := mocks.NewTagService(t)
+		mockTags.On("FindTags").Return([]domain.Tag{tagMock}, nil).Times(1)
+		return handlers.NewTagHandler(mockTags).GetTags(c)
+	}
+
+	handleListError := func(c echo.Context) error {
+		mockTags := mocks.NewTagService(t)
+		mockTags.On("FindTags").Return(nil, db.ErrCollectionDoesNotExist).Times(1)
+		return handlers.NewTagHandler(mockTags).GetTags(c)
+	}
+
+	handleRename := func(err error) func(c echo.Context) error {
+		return func(c echo.Context) error {
+			mockTags := mocks.NewTagService(t)
+			mockTags.On("RenameTag", int64(1), "GoLang").Return(tagMock, err).Times(1)
+			return handlers.NewTagHandler(mockTags).RenameTag(c)
+		}
+	}
+
+	handleMerge := func(err error) func(c echo.Context) error {
+		return func(c echo.Context) error {
+			mockTags := mocks.NewTagService(t)
+			mockTags.On("MergeTags", int64(2), int64(1)).Return(tagMock, err).Times(1)
+			return handlers.NewTagHandler(mockTags).MergeTags(c)
+		}
+	}
+
+	handleInvalid := func(c echo.Context) error {
+		return handlers.NewTagHandler(mocks.NewTagService(t)).RenameTag(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "GetTags success",
+			Request:     requestList,
+			HandlerFunc: handleList,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "[" + tagJSON + "]\n"},
+		},
+		{
+			TestName:    "GetTags error",
+			Request:     requestList,
+			HandlerFunc: handleListError,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not get tags: upper: collection does not exist\"}\n"},
+		},
+		{
+			TestName:    "RenameTag success",
+			Request:     requestRename,
+			RequestBody: requests.TagRequest{Name: "GoLang"},
+			HandlerFunc: handleRename(nil),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   tagJSON + "\n"},
+		},
+		{
+			TestName:    "RenameTag name taken",
+			Request:     requestRename,
+			RequestBody: requests.TagRequest{Name: "GoLang"},
+			HandlerFunc: handleRename(fmt.Errorf("tag service error rename tag: %w", app.ErrTagExists)),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 409,
+				BodyPart:   "{\"code\":409,\"error\":\"Could not rename tag: tag already exists\"}\n"},
+		},
+		{
+			TestName:    "RenameTag not found",
+			Request:     requestRename,
+			RequestBody: requests.TagRequest{Name: "GoLang"},
+			HandlerFunc: handleRename(fmt.Errorf("tag service error rename tag: %w", app.ErrTagNotFound)),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not rename tag: tag not found\"}\n"},
+		},
+		{
+			TestName:    "RenameTag without name",
+			Request:     requestRename,
+			RequestBody: requests.TagRequest{},
+			HandlerFunc: handleInvalid,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate tag data\"}\n"},
+		},
+		{
+			TestName:    "MergeTags success",
+			Request:     requestMerge,
+			RequestBody: requests.MergeTagsRequest{Into: 1},
+			HandlerFunc: handleMerge(nil),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   tagJSON + "\n"},
+		},
+		{
+			TestName:    "MergeTags not found",
+			Request:     requestMerge,
+			RequestBody: requests.MergeTagsRequest{Into: 1},
+			HandlerFunc: handleMerge(fmt.Errorf("tag service error merge tags: %w", app.ErrTagNotFound)),
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not merge tags: tag not found\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"strings"
+	"trainee/internal/app"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+type UserHandler struct {
+	us app.UserService
+	ls app.LockoutService
+	as app.AuthService
+}
+
+func NewUserHandler(u app.UserService, l app.LockoutService, a app.AuthService) UserHandler {
+	return UserHandler{
+		us: u,
+		ls: l,
+		as: a,
+	}
+}
+
+// GetUser 			godoc
+// @Summary 		Get User
+// @Description 	Get User, admin only
+// @Tags			Admin Actions
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Success 		200 {object} response.UserResponse
+// @Failure			400 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			404 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/admin/users/{id} [get]
+func (u UserHandler) GetUser(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse user ID")
+	}
+	user, err := u.us.FindByID(id)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not get user: %s", err))
+		} else {
+			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not get user: %s", err))
+		}
+	}
+	return response.Response(ctx, http.StatusOK, domain.User.DomainToResponse(user))
+}
+
+// UpdateRole 		godoc
+// @Summary 		Update User Role
+// @Description 	Change the role of a user, admin only
+// @Tags			Admin Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Param			input body requests.RoleRequest true "role"
+// @Success 		200 {object} response.UserResponse
+// @Failure			400 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			404 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/admin/users/{id}/role [put]
+func (u UserHandler) UpdateRole(ctx echo.Context) error {
+	var roleRequest requests.RoleRequest
+	if err := ctx.Bind(&roleRequest); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode role data")
+	}
+	if err := ctx.Validate(&roleRequest); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate role data")
+	}
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse user ID")
+	}
+	user, err := u.us.UpdateRole(id, domain.Role(roleRequest.Role))
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidRole) {
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate role data")
+		} else if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not update user: %s", err))
+		} else {
+			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not update user: %s", err))
+		}
+	}
+	return response.Response(ctx, http.StatusOK, domain.User.DomainToResponse(user))
+}
+
+// Unlock 			godoc
+// @Summary 		Unlock User
+// @Description 	Lift the login lockout of a user after too many failed logins, admin only
+// @Tags			Admin Actions
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			404 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/admin/users/{id}/unlock [post]
+func (u UserHandler) Unlock(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse user ID")
+	}
+	user, err := u.us.FindByID(id)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not unlock user: %s", err))
+		} else {
+			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not unlock user: %s", err))
+		}
+	}
+	err = u.ls.Unlock(user.Email)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not unlock user: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "User successfully unlocked")
+}
+
+// Impersonate 		godoc
+// @Summary 		Impersonate User
+// @Description 	Issue a short-lived access token acting as the user, admin only. The token can't be refreshed
+// @Description 	and is refused for password, mfa, token, session and account changes
+// @Tags			Admin Actions
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Success 		201 {object} response.ImpersonationResponse
+// @Failure			400 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			404 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/admin/users/{id}/impersonate [post]
+func (u UserHandler) Impersonate(ctx echo.Context) error {
+	claims := ctx.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse user ID")
+	}
+	accessToken, exp, err := u.as.Impersonate(claims.ID, id, device(ctx))
+	if err != nil {
+		if errors.Is(err, app.ErrCantImpersonate) {
+			return response.ErrorResponse(ctx, http.StatusForbidden, "User can not be impersonated")
+		} else if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not impersonate user: %s", err))
+		} else {
+			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not impersonate user: %s", err))
+		}
+	}
+	return response.Response(ctx, http.StatusCreated, response.ImpersonationResponse{AccessToken: accessToken, Exp: exp})
+}
+
+// DeleteUser 		godoc
+// @Summary 		Delete User
+// @Description 	Delete User, admin only
+// @Tags			Admin Actions
+// @Produce 		json
+// @Param			id path int true "ID"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			403 {object} response.Error
+// @Failure			404 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Security        ApiKeyAuth
+// @Router			/api/v1/admin/users/{id} [delete]
+func (u UserHandler) DeleteUser(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not parse user ID")
+	}
+	err = u.us.Delete(id)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "upper: no more rows in this result set") {
+			return response.ErrorResponse(ctx, http.StatusNotFound, fmt.Sprintf("Could not delete user: %s", err))
+		} else {
+			return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not delete user: %s", err))
+		}
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "User successfully delete")
+}
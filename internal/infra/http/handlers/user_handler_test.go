@@ -0,0 +1,221 @@
+package handlers_test
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"net/http"
+	"testing"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+	"trainee/internal/infra/http/requests"
+)
+
+const userID = "2"
+
+var returnDomainUserMock = domain.User{
+	ID:    2,
+	Email: "user@mail.com",
+	Name:  "Name",
+	Role:  domain.RoleModerator,
+}
+
+var requestUser = test_case.Request{
+	Method: http.MethodGet,
+	Url:    "/admin/users/" + userID,
+	PathParam: &test_case.PathParam{
+		Name:  "id",
+		Value: userID,
+	},
+}
+
+func TestUserHandler(t *testing.T) {
+	handleFuncGet := func(c echo.Context) error {
+		mock := mocks.NewUserService(t)
+		mock.On("FindByID", int64(2)).Return(returnDomainUserMock, nil).Times(1)
+		return handlers.NewUserHandler(mock, mocks.NewLockoutService(t), mocks.NewAuthService(t)).GetUser(c)
+	}
+
+	handleFuncGetNotFound := func(c echo.Context) error {
+		mock := mocks.NewUserService(t)
+		mock.On("FindByID", int64(2)).Return(domain.User{}, db.ErrNoMoreRows).Times(1)
+		return handlers.NewUserHandler(mock, mocks.NewLockoutService(t), mocks.NewAuthService(t)).GetUser(c)
+	}
+
+	handleFuncUpdateRole := func(c echo.Context) error {
+		mock := mocks.NewUserService(t)
+		mock.On("UpdateRole", int64(2), domain.RoleModerator).Return(returnDomainUserMock, nil).Times(1)
+		return handlers.NewUserHandler(mock, mocks.NewLockoutService(t), mocks.NewAuthService(t)).UpdateRole(c)
+	}
+
+	handleFuncUpdateRoleInvalid := func(c echo.Context) error {
+		mock := mocks.NewUserService(t)
+		mock.On("UpdateRole", int64(2), domain.RoleModerator).Return(domain.User{}, app.ErrInvalidRole).Times(1)
+		return handlers.NewUserHandler(mock, mocks.NewLockoutService(t), mocks.NewAuthService(t)).UpdateRole(c)
+	}
+
+	handleFuncDelete := func(c echo.Context) error {
+		mock := mocks.NewUserService(t)
+		mock.On("Delete", int64(2)).Return(nil).Times(1)
+		return handlers.NewUserHandler(mock, mocks.NewLockoutService(t), mocks.NewAuthService(t)).DeleteUser(c)
+	}
+
+	handleFuncDeleteNotFound := func(c echo.Context) error {
+		mock := mocks.NewUserService(t)
+		mock.On("Delete", int64(2)).Return(fmt.Errorf("user service delete user: %w", db.ErrNoMoreRows)).Times(1)
+		return handlers.NewUserHandler(mock, mocks.NewLockoutService(t), mocks.NewAuthService(t)).DeleteUser(c)
+	}
+
+	handleFuncUnlock := func(c echo.Context) error {
+		mock := mocks.NewUserService(t)
+		mock.On("FindByID", int64(2)).Return(returnDomainUserMock, nil).Times(1)
+		lockout := mocks.NewLockoutService(t)
+		lockout.On("Unlock", returnDomainUserMock.Email).Return(nil).Times(1)
+		return handlers.NewUserHandler(mock, lockout, mocks.NewAuthService(t)).Unlock(c)
+	}
+
+	handleFuncUnlockNotFound := func(c echo.Context) error {
+		mock := mocks.NewUserService(t)
+		mock.On("FindByID", int64(2)).Return(domain.User{}, db.ErrNoMoreRows).Times(1)
+		return handlers.NewUserHandler(mock, mocks.NewLockoutService(t), mocks.NewAuthService(t)).Unlock(c)
+	}
+
+	handleFuncImpersonate := func(c echo.Context) error {
+		auth := mocks.NewAuthService(t)
+		auth.On("Impersonate", int64(1), int64(2), loginDevice).Return("access", int64(1668000000), nil).Times(1)
+		return handlers.NewUserHandler(mocks.NewUserService(t), mocks.NewLockoutService(t), auth).Impersonate(c)
+	}
+
+	handleFuncImpersonateAdmin := func(c echo.Context) error {
+		auth := mocks.NewAuthService(t)
+		auth.On("Impersonate", int64(1), int64(2), loginDevice).Return("", int64(0), app.ErrCantImpersonate).Times(1)
+		return handlers.NewUserHandler(mocks.NewUserService(t), mocks.NewLockoutService(t), auth).Impersonate(c)
+	}
+
+	handleFuncImpersonateNotFound := func(c echo.Context) error {
+		auth := mocks.NewAuthService(t)
+		auth.On("Impersonate", int64(1), int64(2), loginDevice).Return("", int64(0), db.ErrNoMoreRows).Times(1)
+		return handlers.NewUserHandler(mocks.NewUserService(t), mocks.NewLockoutService(t), auth).Impersonate(c)
+	}
+
+	handleMock := func(c echo.Context) error {
+		return handlers.NewUserHandler(mocks.NewUserService(t), mocks.NewLockoutService(t), mocks.NewAuthService(t)).UpdateRole(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "GetUser success",
+			Request:     requestUser,
+			HandlerFunc: handleFuncGet,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"id\":2,\"email\":\"user@mail.com\",\"name\":\"Name\",\"role\":\"moderator\",\"email_verified\":false}\n"},
+		},
+		{
+			TestName:    "GetUser not found",
+			Request:     requestUser,
+			HandlerFunc: handleFuncGetNotFound,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not get user: upper: no more rows in this result set\"}\n"},
+		},
+		{
+			TestName:    "UpdateRole success",
+			Request:     requestUser,
+			RequestBody: requests.RoleRequest{Role: "moderator"},
+			HandlerFunc: handleFuncUpdateRole,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"id\":2,\"email\":\"user@mail.com\",\"name\":\"Name\",\"role\":\"moderator\",\"email_verified\":false}\n"},
+		},
+		{
+			TestName:    "UpdateRole invalid role",
+			Request:     requestUser,
+			RequestBody: requests.RoleRequest{Role: "moderator"},
+			HandlerFunc: handleFuncUpdateRoleInvalid,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate role data\"}\n"},
+		},
+		{
+			TestName:    "UpdateRole validate error",
+			Request:     requestUser,
+			RequestBody: requests.RoleRequest{Role: "owner"},
+			HandlerFunc: handleMock,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 422,
+				BodyPart:   "{\"code\":422,\"error\":\"Could not validate role data\"}\n"},
+		},
+		{
+			TestName:    "Unlock success",
+			Request:     requestUser,
+			HandlerFunc: handleFuncUnlock,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"User successfully unlocked\"}\n"},
+		},
+		{
+			TestName:    "Unlock not found",
+			Request:     requestUser,
+			HandlerFunc: handleFuncUnlockNotFound,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not unlock user: upper: no more rows in this result set\"}\n"},
+		},
+		{
+			TestName:    "Impersonate success",
+			Request:     requestUser,
+			HandlerFunc: handleFuncImpersonate,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 201,
+				BodyPart:   "{\"accessToken\":\"access\",\"exp\":1668000000}\n"},
+		},
+		{
+			TestName:    "Impersonate admin",
+			Request:     requestUser,
+			HandlerFunc: handleFuncImpersonateAdmin,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 403,
+				BodyPart:   "{\"code\":403,\"error\":\"User can not be impersonated\"}\n"},
+		},
+		{
+			TestName:    "Impersonate not found",
+			Request:     requestUser,
+			HandlerFunc: handleFuncImpersonateNotFound,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not impersonate user: upper: no more rows in this result set\"}\n"},
+		},
+		{
+			TestName:    "DeleteUser success",
+			Request:     requestUser,
+			HandlerFunc: handleFuncDelete,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"User successfully delete\"}\n"},
+		},
+		{
+			TestName:    "DeleteUser not found",
+			Request:     requestUser,
+			HandlerFunc: handleFuncDeleteNotFound,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 404,
+				BodyPart:   "{\"code\":404,\"error\":\"Could not delete user: user service delete user: upper: no more rows in this result set\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+			c.Set("user", test_case.Token())
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
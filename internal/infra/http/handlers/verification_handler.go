@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"trainee/internal/app"
+	"trainee/internal/infra/http/requests"
+	"trainee/internal/infra/http/response"
+)
+
+type VerificationHandler struct {
+	vs app.VerificationService
+}
+
+func NewVerificationHandler(v app.VerificationService) VerificationHandler {
+	return VerificationHandler{
+		vs: v,
+	}
+}
+
+// Verify 			godoc
+// @Summary 		Verify email
+// @Description 	Confirm the email address with the token from the verification mail
+// @Tags			Auth Actions
+// @Produce 		json
+// @Param			token query string true "verification token"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Router			/verify-email [get]
+func (v VerificationHandler) Verify(ctx echo.Context) error {
+	token := ctx.QueryParam("token")
+	if token == "" {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid or expired verification token")
+	}
+	err := v.vs.Verify(token)
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidVerificationToken) {
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid or expired verification token")
+		}
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not verify email: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "Email has been verified")
+}
+
+// Resend 			godoc
+// @Summary 		Resend verification
+// @Description 	Mail a new verification link, the answer is the same whether the address is known or not
+// @Tags			Auth Actions
+// @Accept 			json
+// @Produce 		json
+// @Param			input body requests.ResendVerification true "users email"
+// @Success 		200 {object} response.Data
+// @Failure			400 {object} response.Error
+// @Failure			422 {object} response.Error
+// @Failure			500 {object} response.Error
+// @Router			/verify-email/resend [post]
+func (v VerificationHandler) Resend(ctx echo.Context) error {
+	var resend requests.ResendVerification
+	if err := ctx.Bind(&resend); err != nil {
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "Could not decode user data")
+	}
+	if err := ctx.Validate(&resend); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Could not validate user data")
+	}
+	err := v.vs.Resend(resend.Email)
+	if err != nil {
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, fmt.Sprintf("Could not send verification link: %s", err))
+	}
+	return response.MessageResponse(ctx, http.StatusOK, "If the address needs verification, a link has been sent")
+}
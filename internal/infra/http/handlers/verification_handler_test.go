@@ -0,0 +1,112 @@
+package handlers_test
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/upper/db/v4"
+	"net/http"
+	"testing"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/infra/http/handlers"
+	"trainee/internal/infra/http/handlers/test_case"
+	"trainee/internal/infra/http/requests"
+)
+
+func TestVerificationHandler(t *testing.T) {
+	verifyRequest := test_case.Request{
+		Method: http.MethodGet,
+		Url:    "/verify-email?token=token",
+	}
+	resendRequest := test_case.Request{
+		Method: http.MethodPost,
+		Url:    "/verify-email/resend",
+	}
+	resend := requests.ResendVerification{Email: "example@email.com"}
+
+	handleVerifySuccess := func(c echo.Context) error {
+		mockVerification := mocks.NewVerificationService(t)
+		mockVerification.On("Verify", "token").Return(nil).Times(1)
+		return handlers.NewVerificationHandler(mockVerification).Verify(c)
+	}
+
+	handleVerifyInvalidToken := func(c echo.Context) error {
+		mockVerification := mocks.NewVerificationService(t)
+		mockVerification.On("Verify", "token").Return(app.ErrInvalidVerificationToken).Times(1)
+		return handlers.NewVerificationHandler(mockVerification).Verify(c)
+	}
+
+	handleVerifyNoToken := func(c echo.Context) error {
+		return handlers.NewVerificationHandler(mocks.NewVerificationService(t)).Verify(c)
+	}
+
+	handleResendSuccess := func(c echo.Context) error {
+		mockVerification := mocks.NewVerificationService(t)
+		mockVerification.On("Resend", resend.Email).Return(nil).Times(1)
+		return handlers.NewVerificationHandler(mockVerification).Resend(c)
+	}
+
+	handleResendError := func(c echo.Context) error {
+		mockVerification := mocks.NewVerificationService(t)
+		mockVerification.On("Resend", resend.Email).Return(db.ErrCollectionDoesNotExist).Times(1)
+		return handlers.NewVerificationHandler(mockVerification).Resend(c)
+	}
+
+	cases := []test_case.TestCase{
+		{
+			TestName:    "Verify success",
+			Request:     verifyRequest,
+			HandlerFunc: handleVerifySuccess,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"Email has been verified\"}\n"},
+		},
+		{
+			TestName:    "Verify invalid token",
+			Request:     verifyRequest,
+			HandlerFunc: handleVerifyInvalidToken,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Invalid or expired verification token\"}\n"},
+		},
+		{
+			TestName: "Verify no token",
+			Request: test_case.Request{
+				Method: http.MethodGet,
+				Url:    "/verify-email",
+			},
+			HandlerFunc: handleVerifyNoToken,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 400,
+				BodyPart:   "{\"code\":400,\"error\":\"Invalid or expired verification token\"}\n"},
+		},
+		{
+			TestName:    "Resend success",
+			Request:     resendRequest,
+			RequestBody: resend,
+			HandlerFunc: handleResendSuccess,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 200,
+				BodyPart:   "{\"code\":200,\"message\":\"If the address needs verification, a link has been sent\"}\n"},
+		},
+		{
+			TestName:    "Resend error",
+			Request:     resendRequest,
+			RequestBody: resend,
+			HandlerFunc: handleResendError,
+			Expected: test_case.ExpectedResponse{
+				StatusCode: 500,
+				BodyPart:   "{\"code\":500,\"error\":\"Could not send verification link: upper: collection does not exist\"}\n"},
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.TestName, func(t *testing.T) {
+			c, recorder := test_case.PrepareContextFromTestCase(test)
+
+			if assert.NoError(t, test.HandlerFunc(c)) {
+				assert.Contains(t, recorder.Body.String(), test.Expected.BodyPart)
+				assert.Equal(t, recorder.Code, test.Expected.StatusCode)
+			}
+		})
+	}
+}
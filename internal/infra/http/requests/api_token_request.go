@@ -0,0 +1,7 @@
+package requests
+
+type CreateAPIToken struct {
+	Name          string   `json:"name" validate:"required,max=100" example:"ci"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=read write" example:"read"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365" example:"30"`
+}
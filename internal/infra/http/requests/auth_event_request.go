@@ -0,0 +1,26 @@
+package requests
+
+import "trainee/internal/domain"
+
+// AuthEventQuery filters the audit log, pages go back in time with before set to the last id of the previous page.
+type AuthEventQuery struct {
+	UserID  int64  `query:"user_id" validate:"omitempty,min=1" example:"1"`
+	ActorID int64  `query:"actor_id" validate:"omitempty,min=1" example:"2"`
+	Email   string `query:"email" validate:"omitempty,email" example:"user@example.com"`
+	Type    string `query:"type" validate:"omitempty,oneof=login refresh logout oauth_link password_change impersonate token_created token_revoked token_scope" example:"login"`
+	Outcome string `query:"outcome" validate:"omitempty,oneof=success failure" example:"failure"`
+	Before  int64  `query:"before" validate:"omitempty,min=1" example:"100"`
+	Limit   int    `query:"limit" validate:"omitempty,min=1,max=200" example:"50"`
+}
+
+func (q AuthEventQuery) QueryToFilter() domain.AuthEventFilter {
+	return domain.AuthEventFilter{
+		UserID:  q.UserID,
+		ActorID: q.ActorID,
+		Email:   q.Email,
+		Type:    domain.AuthEventType(q.Type),
+		Outcome: domain.AuthOutcome(q.Outcome),
+		Before:  q.Before,
+		Limit:   q.Limit,
+	}
+}
@@ -6,31 +6,20 @@ import (
 
 type LoginAuth struct {
 	Email    string `json:"email" validate:"required,email" example:"example@email.com"`
-	Password string `json:"password" validate:"required,gte=8" example:"01234567890"`
+	Password string `json:"password" validate:"required" example:"correct-Horse-battery"`
 }
 
-type RegisterAuth struct {
-	Email    string `json:"email" validate:"required,email" example:"example@email.com"`
-	Password string `json:"password" validate:"required,gte=8" example:"01234567890"`
-	Name     string `json:"name" validate:"required,gte=3"`
+type RefreshAuth struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
 }
 
-func (r RegisterAuth) RegisterToUser() domain.User {
-	return domain.User{
-		Email:    r.Email,
-		Name:     r.Name,
-		Password: r.Password,
-	}
-}
-
-type RegisterOauth2 struct {
-	ID       string `json:"id" validate:"required"`
+type RegisterAuth struct {
 	Email    string `json:"email" validate:"required,email" example:"example@email.com"`
-	Password string `json:"password" validate:"required,gte=8" example:"01234567890"`
+	Password string `json:"password" validate:"required" example:"correct-Horse-battery"`
 	Name     string `json:"name" validate:"required,gte=3"`
 }
 
-func (r RegisterOauth2) RegisterToUser() domain.User {
+func (r RegisterAuth) RegisterToUser() domain.User {
 	return domain.User{
 		Email:    r.Email,
 		Name:     r.Name,
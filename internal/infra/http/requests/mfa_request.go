@@ -0,0 +1,10 @@
+package requests
+
+type MFACode struct {
+	Code string `json:"code" validate:"required" example:"123456"`
+}
+
+type LoginMFA struct {
+	MFAToken string `json:"mfaToken" validate:"required"`
+	Code     string `json:"code" validate:"required" example:"123456"`
+}
@@ -0,0 +1,16 @@
+package requests
+
+type ForgotPassword struct {
+	Email string `json:"email" validate:"required,email" example:"example@email.com"`
+}
+
+// ResetPassword also comes as a form from the reset page.
+type ResetPassword struct {
+	Token    string `json:"token" form:"token" validate:"required"`
+	Password string `json:"password" form:"password" validate:"required" example:"correct-Horse-battery"`
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"currentPassword" validate:"required" example:"01234567890"`
+	Password        string `json:"password" validate:"required" example:"correct-Horse-battery"`
+}
@@ -1,6 +1,47 @@
 package requests
 
+import (
+	"time"
+	"trainee/internal/domain"
+)
+
 type PostRequest struct {
-	Title string `json:"title" example:"Lorem ipsum" validate:"required"`
-	Body  string `json:"body" example:"Lorem ipsum" validate:"required"`
+	Title string `json:"title" example:"Lorem ipsum" validate:"required,max=255"`
+	Body  string `json:"body" example:"Lorem ipsum" validate:"required,max=50000"`
+	// Tags replace the tags the post had, they are normalized to lower case words joined by dashes
+	Tags []string `json:"tags" example:"golang,web" validate:"omitempty,max=10,dive,required,max=50"`
+	// Status is published by default for new posts and kept as it is on update, scheduled takes PublishAt
+	Status    string     `json:"status" example:"draft" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt *time.Time `json:"publish_at" example:"2022-12-01T10:00:00Z"`
+}
+
+// PublishRequest publishes a post now, or schedules it when PublishAt is in the future.
+type PublishRequest struct {
+	PublishAt *time.Time `json:"publish_at" example:"2022-12-01T10:00:00Z"`
+}
+
+// PostQuery selects a page of the post feed, cursor is the next_cursor of the previous page.
+type PostQuery struct {
+	UserID int64     `query:"user_id" validate:"omitempty,min=1" example:"1"`
+	Status string    `query:"status" validate:"omitempty,oneof=draft scheduled published archived" example:"draft"`
+	Tags   []string  `query:"tag" validate:"omitempty,max=5,dive,required,max=50" example:"golang"`
+	From   time.Time `query:"from" example:"2022-11-01T00:00:00Z"`
+	To     time.Time `query:"to" validate:"omitempty,gtfield=From" example:"2022-12-01T00:00:00Z"`
+	Sort   string    `query:"sort" validate:"omitempty,oneof=created updated" example:"created"`
+	Order  string    `query:"order" validate:"omitempty,oneof=asc desc" example:"desc"`
+	Cursor string    `query:"cursor"`
+	Limit  int       `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+}
+
+func (q PostQuery) QueryToFilter() domain.PostFilter {
+	return domain.PostFilter{
+		UserID:    q.UserID,
+		Status:    domain.PostStatus(q.Status),
+		Tags:      q.Tags,
+		From:      q.From,
+		To:        q.To,
+		Sort:      domain.PostSort(q.Sort),
+		Ascending: q.Order == "asc",
+		Limit:     q.Limit,
+	}
 }
@@ -0,0 +1,7 @@
+package requests
+
+// PostDiffQuery picks the two revisions to compare, either can be the older one.
+type PostDiffQuery struct {
+	From int `query:"from" validate:"required,min=1" example:"1"`
+	To   int `query:"to" validate:"required,min=1" example:"2"`
+}
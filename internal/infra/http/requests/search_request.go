@@ -0,0 +1,20 @@
+package requests
+
+import "trainee/internal/domain"
+
+// SearchRequest is a web search style query, e.g. `"exact phrase" word -excluded` or `cats or dogs`.
+type SearchRequest struct {
+	Query  string `query:"q" validate:"required,max=200" example:"\"lorem ipsum\" -dolor"`
+	Type   string `query:"type" validate:"omitempty,oneof=post comment" example:"post"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Offset int    `query:"offset" validate:"omitempty,min=0,max=1000" example:"0"`
+}
+
+func (q SearchRequest) RequestToQuery() domain.SearchQuery {
+	return domain.SearchQuery{
+		Query:  q.Query,
+		Type:   domain.SearchResultType(q.Type),
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+}
@@ -0,0 +1,10 @@
+package requests
+
+type TagRequest struct {
+	Name string `json:"name" example:"golang" validate:"required,max=50"`
+}
+
+type MergeTagsRequest struct {
+	// Into is the tag the posts are moved to, the merged tag is deleted
+	Into int64 `json:"into" example:"1" validate:"required,min=1"`
+}
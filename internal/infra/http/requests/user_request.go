@@ -0,0 +1,10 @@
+package requests
+
+type RoleRequest struct {
+	Role string `json:"role" example:"moderator" validate:"required,oneof=user moderator admin"`
+}
+
+// DeleteAccount confirms the deletion with the current password, users without one leave it empty.
+type DeleteAccount struct {
+	Password string `json:"password" example:"01234567890"`
+}
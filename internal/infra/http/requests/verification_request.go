@@ -0,0 +1,5 @@
+package requests
+
+type ResendVerification struct {
+	Email string `json:"email" validate:"required,email" example:"example@email.com"`
+}
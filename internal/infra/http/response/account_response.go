@@ -0,0 +1,21 @@
+package response
+
+import "time"
+
+type IdentityResponse struct {
+	Provider  string    `json:"provider" example:"google"`
+	Email     string    `json:"email" example:"user@gmail.com"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AccountExportResponse is everything an account owns, each field is a file of the export archive.
+type AccountExportResponse struct {
+	User          UserResponse           `json:"user"`
+	Posts         []PostResponse         `json:"posts"`
+	PostRevisions []PostRevisionResponse `json:"post_revisions"`
+	Comments      []CommentResponse      `json:"comments"`
+	Identities    []IdentityResponse     `json:"identities"`
+	APITokens     []APITokenResponse     `json:"api_tokens"`
+	Sessions      []SessionResponse      `json:"sessions"`
+	AuthEvents    []AuthEventResponse    `json:"auth_events"`
+}
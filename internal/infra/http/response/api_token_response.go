@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+type APITokenResponse struct {
+	ID         int64      `json:"id" example:"1"`
+	Name       string     `json:"name" example:"ci"`
+	Prefix     string     `json:"prefix" example:"pat_Xk3d9a"`
+	Scopes     []string   `json:"scopes" example:"read"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPITokenResponse carries the token itself, it is shown only once.
+type CreatedAPITokenResponse struct {
+	Token string `json:"token" example:"pat_Xk3d9a..."`
+	APITokenResponse
+}
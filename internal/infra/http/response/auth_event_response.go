@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+type AuthEventResponse struct {
+	ID        int64     `json:"id" example:"1"`
+	UserID    int64     `json:"user_id,omitempty" example:"1"`
+	ActorID   int64     `json:"actor_id,omitempty" example:"2"`
+	Email     string    `json:"email,omitempty" example:"user@example.com"`
+	Type      string    `json:"type" example:"login"`
+	Outcome   string    `json:"outcome" example:"failure"`
+	Detail    string    `json:"detail,omitempty" example:"wrong password"`
+	IP        string    `json:"ip" example:"127.0.0.1"`
+	UserAgent string    `json:"user_agent" example:"Mozilla/5.0"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -13,3 +13,8 @@ func NewLoginResponse(token, refreshToken string, exp int64) *LoginResponse {
 		Exp:          exp,
 	}
 }
+
+type ImpersonationResponse struct {
+	AccessToken string `json:"accessToken"`
+	Exp         int64  `json:"exp"`
+}
@@ -0,0 +1,15 @@
+package response
+
+type MFARequiredResponse struct {
+	MFAToken string `json:"mfaToken"`
+	Exp      int64  `json:"exp"`
+}
+
+type MFAEnrollResponse struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	URI    string `json:"uri" example:"otpauth://totp/trainee:example@email.com?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP&issuer=trainee"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"abcde-fghij"`
+}
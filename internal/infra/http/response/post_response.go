@@ -1,9 +1,22 @@
 package response
 
+import "time"
+
 type PostResponse struct {
-	ID       int64             `json:"id" example:"1"`
-	UserID   int64             `json:"user_id" example:"1"`
-	Title    string            `json:"title" example:"Lorem ipsum"`
-	Body     string            `json:"body" example:"Lorem ipsum"`
-	Comments []CommentResponse `json:"comments"`
+	ID          int64             `json:"id" example:"1"`
+	UserID      int64             `json:"user_id" example:"1"`
+	Title       string            `json:"title" example:"Lorem ipsum"`
+	Body        string            `json:"body" example:"Lorem ipsum"`
+	Tags        []string          `json:"tags" example:"golang,web"`
+	Status      string            `json:"status" example:"published"`
+	CreatedDate time.Time         `json:"created_at"`
+	UpdatedDate time.Time         `json:"updated_at"`
+	PublishedAt *time.Time        `json:"published_at,omitempty"`
+	Comments    []CommentResponse `json:"comments"`
+}
+
+type PostPageResponse struct {
+	Posts      []PostResponse `json:"posts"`
+	NextCursor string         `json:"next_cursor" example:"eyJzIjoiY3JlYXRlZCIsImQiOiIyMDIyLTExLTAxVDEwOjAwOjAwWiIsImlkIjo0Mn0"`
+	Total      uint64         `json:"total" example:"120"`
 }
@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+type PostRevisionResponse struct {
+	PostID    int64     `json:"post_id" example:"1"`
+	Revision  int       `json:"revision" example:"2"`
+	EditorID  int64     `json:"editor_id" example:"1"`
+	Title     string    `json:"title" example:"Lorem ipsum"`
+	Body      string    `json:"body" example:"Lorem ipsum"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PostDiffResponse struct {
+	PostID int64  `json:"post_id" example:"1"`
+	From   int    `json:"from" example:"1"`
+	To     int    `json:"to" example:"2"`
+	Diff   string `json:"diff" example:"--- revision 1\n+++ revision 2\n@@ -1 +1 @@\n-Lorem\n+Lorem ipsum\n"`
+}
@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+type SearchResultResponse struct {
+	Type      string    `json:"type" example:"post"`
+	ID        int64     `json:"id" example:"1"`
+	PostID    int64     `json:"post_id" example:"1"`
+	UserID    int64     `json:"user_id" example:"1"`
+	Title     string    `json:"title" example:"Lorem ipsum"`
+	Snippet   string    `json:"snippet" example:"dolor sit &lt;b&gt; <mark>amet</mark>, consectetur"`
+	Rank      float64   `json:"rank" example:"0.1"`
+	CreatedAt time.Time `json:"created_at"`
+}
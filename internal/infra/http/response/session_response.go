@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+type SessionResponse struct {
+	ID         string    `json:"id" example:"6f1c0e5e-8a0e-4f43-9d0c-3e1f3f6b7f1a"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0"`
+	IP         string    `json:"ip" example:"127.0.0.1"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+	ActorID    int64     `json:"impersonated_by,omitempty" example:"1"`
+}
@@ -0,0 +1,7 @@
+package response
+
+type TagResponse struct {
+	ID        int64  `json:"id" example:"1"`
+	Name      string `json:"name" example:"golang"`
+	PostCount int64  `json:"post_count" example:"12"`
+}
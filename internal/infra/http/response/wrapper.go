@@ -9,6 +9,13 @@ type Error struct {
 	Error string `json:"error"`
 }
 
+// FieldError is an Error that also says what is wrong with each rejected field of the request.
+type FieldError struct {
+	Code   int                 `json:"code"`
+	Error  string              `json:"error"`
+	Fields map[string][]string `json:"fields"`
+}
+
 type Data struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
@@ -31,3 +38,11 @@ func ErrorResponse(c echo.Context, statusCode int, message string) error {
 		Error: message,
 	})
 }
+
+func FieldErrorResponse(c echo.Context, statusCode int, message string, fields map[string][]string) error {
+	return Response(c, statusCode, FieldError{
+		Code:   statusCode,
+		Error:  message,
+		Fields: fields,
+	})
+}
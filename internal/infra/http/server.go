@@ -2,14 +2,28 @@ package http
 
 import (
 	"github.com/labstack/echo/v4"
+	"net"
 )
 
 type Server struct {
 	Echo *echo.Echo
 }
 
-func NewServer() *Server {
-	return &Server{Echo: echo.New()}
+// NewServer takes the client ip from X-Forwarded-For only behind one of the trusted proxies, otherwise it is the
+// peer address. Echo's default would believe the headers of any client, letting it pick the ip that logins are
+// locked out by and audited with.
+func NewServer(trustedProxies []*net.IPNet) *Server {
+	e := echo.New()
+	if len(trustedProxies) == 0 {
+		e.IPExtractor = echo.ExtractIPDirect()
+	} else {
+		options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+		for _, proxy := range trustedProxies {
+			options = append(options, echo.TrustIPRange(proxy))
+		}
+		e.IPExtractor = echo.ExtractIPFromXFFHeader(options...)
+	}
+	return &Server{Echo: e}
 }
 
 func (s Server) Start() error {
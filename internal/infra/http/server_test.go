@@ -0,0 +1,32 @@
+package http
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewServer_RealIP(t *testing.T) {
+	_, proxy, _ := net.ParseCIDR("10.0.0.1/32")
+	tests := []struct {
+		name    string
+		proxies []*net.IPNet
+		remote  string
+		want    string
+	}{
+		{"forwarded header of a client is ignored", nil, "203.0.113.7:5000", "203.0.113.7"},
+		{"forwarded header of a trusted proxy is used", []*net.IPNet{proxy}, "10.0.0.1:5000", "198.51.100.1"},
+		{"forwarded header of another private address is ignored", []*net.IPNet{proxy}, "10.0.0.2:5000", "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewServer(tt.proxies).Echo
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set("X-Forwarded-For", "198.51.100.1")
+			req.Header.Set("X-Real-IP", "198.51.100.1")
+			assert.Equal(t, tt.want, e.NewContext(req, httptest.NewRecorder()).RealIP())
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// logMailer is meant for local development: mails are appended to a file, or logged when no file is set.
+type logMailer struct {
+	path string
+	mu   *sync.Mutex
+}
+
+func NewLogMailer(path string) Mailer {
+	return logMailer{
+		path: path,
+		mu:   &sync.Mutex{},
+	}
+}
+
+func (m logMailer) Send(msg Message) error {
+	mail := format("outbox", msg)
+	if m.path == "" {
+		log.Printf("mail outbox:\n%s\n", mail)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("log mailer send: %w", err)
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s\r\n\r\n", mail)
+	if err != nil {
+		return fmt.Errorf("log mailer send: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package mail
+
+import "trainee/config"
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+//go:generate mockery --dir . --name Mailer --output ./mock
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// NewMailer sends through SMTP when a host is configured, otherwise it writes mails to the outbox.
+func NewMailer(conf config.Mail) Mailer {
+	if conf.Host == "" {
+		return NewLogMailer(conf.Outbox)
+	}
+	return NewSMTPMailer(conf)
+}
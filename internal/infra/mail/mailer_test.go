@@ -0,0 +1,26 @@
+package mail
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogMailer_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.eml")
+	m := NewLogMailer(path)
+
+	err := m.Send(Message{To: "user@email.com", Subject: "Hello", Body: "line one\nline two"})
+	assert.NoError(t, err)
+	err = m.Send(Message{To: "other@email.com", Subject: "Again", Body: "body"})
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	outbox := string(b)
+	assert.True(t, strings.Contains(outbox, "To: user@email.com\r\nSubject: Hello\r\n"))
+	assert.True(t, strings.Contains(outbox, "line one\r\nline two"))
+	assert.True(t, strings.Contains(outbox, "To: other@email.com\r\nSubject: Again\r\n"))
+}
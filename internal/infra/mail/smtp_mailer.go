@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"trainee/config"
+)
+
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(conf config.Mail) Mailer {
+	m := smtpMailer{
+		addr: net.JoinHostPort(conf.Host, conf.Port),
+		from: conf.From,
+	}
+	if conf.User != "" {
+		m.auth = smtp.PlainAuth("", conf.User, conf.Password, conf.Host)
+	}
+	return m
+}
+
+func (m smtpMailer) Send(msg Message) error {
+	err := smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, format(m.from, msg))
+	if err != nil {
+		return fmt.Errorf("smtp mailer send: %w", err)
+	}
+	return nil
+}
+
+func format(from string, msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,53 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"trainee/internal/domain"
+)
+
+const githubUserInfoURL = "https://api.github.com/user"
+
+var githubScopes = []string{"read:user", "user:email"}
+
+type githubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// githubUserInfo takes the primary address from /user/emails, as the public email on /user carries no verification flag.
+func githubUserInfo(ctx context.Context, client *http.Client, userInfoURL string) (domain.Identity, error) {
+	var u githubUser
+	err := getJSON(ctx, client, userInfoURL, &u)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+	var emails []githubEmail
+	err = getJSON(ctx, client, userInfoURL+"/emails", &emails)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+
+	identity := domain.Identity{Name: u.Name}
+	if u.ID != 0 {
+		identity.Subject = strconv.FormatInt(u.ID, 10)
+	}
+	if identity.Name == "" {
+		identity.Name = u.Login
+	}
+	for _, e := range emails {
+		if e.Primary {
+			identity.Email = e.Email
+			identity.EmailVerified = e.Verified
+		}
+	}
+	return identity, nil
+}
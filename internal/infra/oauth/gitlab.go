@@ -0,0 +1,36 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"trainee/internal/domain"
+)
+
+const gitlabUserInfoURL = "https://gitlab.com/api/v4/user"
+
+var gitlabScopes = []string{"read_user"}
+
+type gitlabUser struct {
+	ID          int64   `json:"id"`
+	Email       string  `json:"email"`
+	Name        string  `json:"name"`
+	ConfirmedAt *string `json:"confirmed_at"`
+}
+
+func gitlabUserInfo(ctx context.Context, client *http.Client, userInfoURL string) (domain.Identity, error) {
+	var u gitlabUser
+	err := getJSON(ctx, client, userInfoURL, &u)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+	identity := domain.Identity{
+		Email:         u.Email,
+		EmailVerified: u.ConfirmedAt != nil,
+		Name:          u.Name,
+	}
+	if u.ID != 0 {
+		identity.Subject = strconv.FormatInt(u.ID, 10)
+	}
+	return identity, nil
+}
@@ -0,0 +1,35 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"trainee/internal/domain"
+)
+
+const googleUserInfoURL = "https://www.googleapis.com/oauth2/v2/userinfo"
+
+var googleScopes = []string{
+	"https://www.googleapis.com/auth/userinfo.profile",
+	"https://www.googleapis.com/auth/userinfo.email",
+}
+
+type googleUser struct {
+	ID            string `json:"id"`
+	Email         string `json:"email"`
+	VerifiedEmail bool   `json:"verified_email"`
+	Name          string `json:"name"`
+}
+
+func googleUserInfo(ctx context.Context, client *http.Client, userInfoURL string) (domain.Identity, error) {
+	var u googleUser
+	err := getJSON(ctx, client, userInfoURL, &u)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+	return domain.Identity{
+		Subject:       u.ID,
+		Email:         u.Email,
+		EmailVerified: u.VerifiedEmail,
+		Name:          u.Name,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"trainee/internal/domain"
+)
+
+var oidcScopes = []string{"openid", "email", "profile"}
+
+type oidcUser struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+type oidcDiscovery struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+func oidcUserInfo(ctx context.Context, client *http.Client, userInfoURL string) (domain.Identity, error) {
+	var u oidcUser
+	err := getJSON(ctx, client, userInfoURL, &u)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+	return domain.Identity{
+		Subject:       u.Subject,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		Name:          u.Name,
+	}, nil
+}
+
+func discover(ctx context.Context, issuer string) (oidcDiscovery, error) {
+	var d oidcDiscovery
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	err := getJSON(ctx, http.DefaultClient, url, &d)
+	return d, err
+}
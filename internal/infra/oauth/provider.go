@@ -0,0 +1,75 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/oauth2"
+	"net/http"
+	"trainee/internal/domain"
+)
+
+var ErrIncompleteIdentity = errors.New("provider returned no subject")
+
+//go:generate mockery --dir . --name Provider --output ./mock
+type Provider interface {
+	Name() string
+	AuthCodeURL(state string) string
+	Identity(ctx context.Context, code string) (domain.Identity, error)
+}
+
+// userInfoFunc maps the provider specific userinfo into an identity, using a client that already carries the token.
+type userInfoFunc func(ctx context.Context, client *http.Client, userInfoURL string) (domain.Identity, error)
+
+type provider struct {
+	name        string
+	config      oauth2.Config
+	userInfoURL string
+	userInfo    userInfoFunc
+}
+
+func (p provider) Name() string {
+	return p.name
+}
+
+func (p provider) AuthCodeURL(state string) string {
+	return p.config.AuthCodeURL(state)
+}
+
+func (p provider) Identity(ctx context.Context, code string) (domain.Identity, error) {
+	token, err := p.config.Exchange(ctx, code)
+	if err != nil {
+		return domain.Identity{}, fmt.Errorf("%s provider error, code exchange: %w", p.name, err)
+	}
+	identity, err := p.userInfo(ctx, p.config.Client(ctx, token), p.userInfoURL)
+	if err != nil {
+		return domain.Identity{}, fmt.Errorf("%s provider error, user info: %w", p.name, err)
+	}
+	if identity.Subject == "" {
+		return domain.Identity{}, fmt.Errorf("%s provider error: %w", p.name, ErrIncompleteIdentity)
+	}
+	// an address we don't have can't be vouched for
+	if identity.Email == "" {
+		identity.EmailVerified = false
+	}
+	identity.Provider = p.name
+	return identity, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
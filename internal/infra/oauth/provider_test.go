@@ -0,0 +1,189 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"trainee/config"
+	"trainee/internal/domain"
+)
+
+// stub stands in for a provider: it issues "token" for "code" and serves the given userinfo routes.
+func stub(t *testing.T, routes map[string]interface{}) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "token", "token_type": "Bearer"})
+	})
+	for path, body := range routes {
+		body := body
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(body)
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func stubConfig(srv *httptest.Server, userInfoPath string) config.OAuthProvider {
+	return config.OAuthProvider{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/callback",
+		AuthURL:      srv.URL + "/auth",
+		TokenURL:     srv.URL + "/token",
+		UserInfoURL:  srv.URL + userInfoPath,
+	}
+}
+
+func TestProvider_Identity(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		routes   map[string]interface{}
+		code     string
+		want     domain.Identity
+		wantErr  bool
+	}{
+		{
+			"google",
+			"google",
+			map[string]interface{}{
+				"/userinfo": map[string]interface{}{"id": "1234", "email": "user@gmail.com", "verified_email": true, "name": "Name"},
+			},
+			"code",
+			domain.Identity{Provider: "google", Subject: "1234", Email: "user@gmail.com", EmailVerified: true, Name: "Name"},
+			false,
+		},
+		{
+			"github takes the primary email",
+			"github",
+			map[string]interface{}{
+				"/userinfo": map[string]interface{}{"id": 42, "login": "octocat"},
+				"/userinfo/emails": []map[string]interface{}{
+					{"email": "other@example.com", "primary": false, "verified": true},
+					{"email": "octocat@example.com", "primary": true, "verified": false},
+				},
+			},
+			"code",
+			domain.Identity{Provider: "github", Subject: "42", Email: "octocat@example.com", EmailVerified: false, Name: "octocat"},
+			false,
+		},
+		{
+			"gitlab confirmed email",
+			"gitlab",
+			map[string]interface{}{
+				"/userinfo": map[string]interface{}{"id": 7, "email": "user@gitlab.com", "name": "Name", "confirmed_at": "2022-11-01T10:00:00Z"},
+			},
+			"code",
+			domain.Identity{Provider: "gitlab", Subject: "7", Email: "user@gitlab.com", EmailVerified: true, Name: "Name"},
+			false,
+		},
+		{
+			"generic oidc",
+			"keycloak",
+			map[string]interface{}{
+				"/userinfo": map[string]interface{}{"sub": "abc", "email": "user@example.com", "email_verified": true, "name": "Name"},
+			},
+			"code",
+			domain.Identity{Provider: "keycloak", Subject: "abc", Email: "user@example.com", EmailVerified: true, Name: "Name"},
+			false,
+		},
+		{
+			"no email is never verified",
+			"keycloak",
+			map[string]interface{}{
+				"/userinfo": map[string]interface{}{"sub": "abc", "email_verified": true},
+			},
+			"code",
+			domain.Identity{Provider: "keycloak", Subject: "abc"},
+			false,
+		},
+		{
+			"no subject",
+			"keycloak",
+			map[string]interface{}{
+				"/userinfo": map[string]interface{}{"email": "user@example.com"},
+			},
+			"code",
+			domain.Identity{},
+			true,
+		},
+		{
+			"bad code",
+			"google",
+			map[string]interface{}{},
+			"wrong",
+			domain.Identity{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := stub(t, tt.routes)
+			p, err := NewProvider(context.Background(), tt.provider, stubConfig(srv, "/userinfo"))
+			assert.NoError(t, err)
+
+			got, err := p.Identity(context.Background(), tt.code)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	p, err := NewProvider(context.Background(), "github", config.OAuthProvider{ClientID: "id", RedirectURL: "http://localhost:8080/callback"})
+	assert.NoError(t, err)
+
+	u, err := url.Parse(p.AuthCodeURL("state"))
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com", u.Host)
+	assert.Equal(t, "state", u.Query().Get("state"))
+	assert.Equal(t, "id", u.Query().Get("client_id"))
+	assert.Equal(t, "read:user user:email", u.Query().Get("scope"))
+}
+
+func TestNewProvider_Discovery(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"authorization_endpoint": srv.URL + "/auth",
+			"token_endpoint":         srv.URL + "/token",
+			"userinfo_endpoint":      srv.URL + "/userinfo",
+		})
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(context.Background(), "okta", config.OAuthProvider{ClientID: "id", Issuer: srv.URL + "/"})
+	assert.NoError(t, err)
+	assert.Equal(t, srv.URL+"/auth", p.(provider).config.Endpoint.AuthURL)
+	assert.Equal(t, srv.URL+"/token", p.(provider).config.Endpoint.TokenURL)
+	assert.Equal(t, srv.URL+"/userinfo", p.(provider).userInfoURL)
+}
+
+func TestNewProvider_MissingEndpoint(t *testing.T) {
+	_, err := NewProvider(context.Background(), "okta", config.OAuthProvider{ClientID: "id"})
+	assert.True(t, errors.Is(err, ErrMissingEndpoint))
+}
@@ -0,0 +1,96 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/gitlab"
+	"golang.org/x/oauth2/google"
+	"trainee/config"
+)
+
+var ErrMissingEndpoint = errors.New("auth, token and userinfo urls or an issuer are required")
+
+type defaults struct {
+	endpoint    oauth2.Endpoint
+	userInfoURL string
+	scopes      []string
+	userInfo    userInfoFunc
+}
+
+// known providers, any other name is treated as a generic OIDC provider
+var known = map[string]defaults{
+	"google": {google.Endpoint, googleUserInfoURL, googleScopes, googleUserInfo},
+	"github": {github.Endpoint, githubUserInfoURL, githubScopes, githubUserInfo},
+	"gitlab": {gitlab.Endpoint, gitlabUserInfoURL, gitlabScopes, gitlabUserInfo},
+}
+
+type Registry map[string]Provider
+
+func NewRegistry(ctx context.Context, providers map[string]config.OAuthProvider) (Registry, error) {
+	r := make(Registry, len(providers))
+	for name, conf := range providers {
+		p, err := NewProvider(ctx, name, conf)
+		if err != nil {
+			return nil, err
+		}
+		r[name] = p
+	}
+	return r, nil
+}
+
+func (r Registry) Get(name string) (Provider, bool) {
+	p, ok := r[name]
+	return p, ok
+}
+
+// NewProvider builds a provider from its config, configured urls override the provider defaults.
+func NewProvider(ctx context.Context, name string, conf config.OAuthProvider) (Provider, error) {
+	d, ok := known[name]
+	if !ok {
+		d = defaults{scopes: oidcScopes, userInfo: oidcUserInfo}
+		if conf.Issuer != "" {
+			discovery, err := discover(ctx, conf.Issuer)
+			if err != nil {
+				return nil, fmt.Errorf("%s provider error, discovery: %w", name, err)
+			}
+			d.endpoint = oauth2.Endpoint{
+				AuthURL:  discovery.AuthorizationEndpoint,
+				TokenURL: discovery.TokenEndpoint,
+			}
+			d.userInfoURL = discovery.UserInfoEndpoint
+		}
+	}
+
+	p := provider{
+		name: name,
+		config: oauth2.Config{
+			ClientID:     conf.ClientID,
+			ClientSecret: conf.ClientSecret,
+			Endpoint:     d.endpoint,
+			RedirectURL:  conf.RedirectURL,
+			Scopes:       d.scopes,
+		},
+		userInfoURL: d.userInfoURL,
+		userInfo:    d.userInfo,
+	}
+	if conf.AuthURL != "" {
+		p.config.Endpoint.AuthURL = conf.AuthURL
+	}
+	if conf.TokenURL != "" {
+		p.config.Endpoint.TokenURL = conf.TokenURL
+	}
+	if conf.UserInfoURL != "" {
+		p.userInfoURL = conf.UserInfoURL
+	}
+	if len(conf.Scopes) > 0 {
+		p.config.Scopes = conf.Scopes
+	}
+
+	if p.config.Endpoint.AuthURL == "" || p.config.Endpoint.TokenURL == "" || p.userInfoURL == "" {
+		return nil, fmt.Errorf("%s provider error: %w", name, ErrMissingEndpoint)
+	}
+	return p, nil
+}
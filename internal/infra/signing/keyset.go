@@ -0,0 +1,142 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no private key to sign with")
+	ErrUnknownKey   = errors.New("unknown signing key")
+)
+
+// Key verifies tokens with its kid, and signs them when the private part is known.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+// KeySet holds every key tokens may still be signed with. The newest key with a private part signs.
+type KeySet struct {
+	signing Key
+	keys    map[string]Key
+}
+
+// NewHMACKeySet keeps the shared secret signing of a deployment without key files, it publishes no keys.
+func NewHMACKeySet(secret string) *KeySet {
+	key := Key{
+		ID:      "hs256",
+		Method:  jwt.SigningMethodHS256,
+		private: []byte(secret),
+		public:  []byte(secret),
+	}
+	return &KeySet{
+		signing: key,
+		keys:    map[string]Key{key.ID: key},
+	}
+}
+
+// LoadKeySet reads every *.pem file of dir, the file name without extension is the kid.
+// Files are RSA or Ed25519 private keys, or public keys of retired keys that only verify.
+// Names sort by age, so the last file holding a private key signs new tokens.
+func LoadKeySet(dir string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("key set error load: %w", err)
+	}
+	sort.Strings(files)
+
+	set := &KeySet{keys: make(map[string]Key, len(files))}
+	for _, file := range files {
+		key, err := loadKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("key set error load %s: %w", file, err)
+		}
+		set.keys[key.ID] = key
+		if key.private != nil {
+			set.signing = key
+		}
+	}
+	if set.signing.private == nil {
+		return nil, fmt.Errorf("key set error load %s: %w", dir, ErrNoSigningKey)
+	}
+	return set, nil
+}
+
+func loadKey(file string) (Key, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return Key{}, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return Key{}, errors.New("no pem block")
+	}
+	key := Key{ID: strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("unsupported pem block %q", block.Type)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %T", parsed)
+	}
+	return key, nil
+}
+
+// Sign signs the claims with the current key and names it in the kid header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.Method, claims)
+	token.Header["kid"] = k.signing.ID
+	return token.SignedString(k.signing.private)
+}
+
+// Keyfunc finds the verification key by kid. The algorithm must be the key's own,
+// so a token can't make us check an RS256 public key as an HMAC secret.
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		// tokens issued before keys had ids
+		kid = k.signing.ID
+	}
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.public, nil
+}
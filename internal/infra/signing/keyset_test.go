@@ -0,0 +1,148 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writePEM(t *testing.T, dir, name, typ string, der []byte) {
+	b := pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name+".pem"), b, 0600))
+}
+
+func writePrivate(t *testing.T, dir, name string, key interface{}) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	writePEM(t, dir, name, "PRIVATE KEY", der)
+}
+
+func writePublic(t *testing.T, dir, name string, key interface{}) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	assert.NoError(t, err)
+	writePEM(t, dir, name, "PUBLIC KEY", der)
+}
+
+func claims() jwt.StandardClaims {
+	return jwt.StandardClaims{Subject: "1", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+}
+
+func verify(set *KeySet, token string) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(token, &jwt.StandardClaims{}, set.Keyfunc)
+}
+
+func TestLoadKeySet_SignsWithNewestKey(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	writePrivate(t, dir, "2022-01-01", rsaKey)
+	writePrivate(t, dir, "2022-06-01", edKey)
+
+	set, err := LoadKeySet(dir)
+	assert.NoError(t, err)
+
+	signed, err := set.Sign(claims())
+	assert.NoError(t, err)
+	token, err := verify(set, signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "2022-06-01", token.Header["kid"])
+	assert.Equal(t, "EdDSA", token.Header["alg"])
+
+	jwks := set.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, JWK{Kty: "RSA", Kid: "2022-01-01", Use: "sig", Alg: "RS256", N: jwks.Keys[0].N, E: "AQAB"}, jwks.Keys[0])
+	assert.Equal(t, "OKP", jwks.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[1].Crv)
+	assert.NotEmpty(t, jwks.Keys[1].X)
+}
+
+func TestLoadKeySet_RetiredKeyStillVerifies(t *testing.T) {
+	oldDir, dir := t.TempDir(), t.TempDir()
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	writePrivate(t, oldDir, "key-1", oldKey)
+	oldSet, err := LoadKeySet(oldDir)
+	assert.NoError(t, err)
+	oldToken, err := oldSet.Sign(claims())
+	assert.NoError(t, err)
+
+	// after rotation only the public part of the old key is kept
+	writePublic(t, dir, "key-1", &oldKey.PublicKey)
+	writePrivate(t, dir, "key-2", newKey)
+	set, err := LoadKeySet(dir)
+	assert.NoError(t, err)
+
+	token, err := verify(set, oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", token.Header["kid"])
+
+	newToken, err := set.Sign(claims())
+	assert.NoError(t, err)
+	_, err = verify(oldSet, newToken)
+	assert.True(t, errors.Is(err.(*jwt.ValidationError).Inner, ErrUnknownKey))
+}
+
+func TestLoadKeySet_Errors(t *testing.T) {
+	dir := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	writePublic(t, dir, "key-1", &key.PublicKey)
+
+	_, err = LoadKeySet(dir)
+	assert.True(t, errors.Is(err, ErrNoSigningKey))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0600))
+	_, err = LoadKeySet(dir)
+	assert.Error(t, err)
+}
+
+func TestKeySet_RejectsOtherAlgorithm(t *testing.T) {
+	dir := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	writePrivate(t, dir, "key-1", key)
+	set, err := LoadKeySet(dir)
+	assert.NoError(t, err)
+
+	// an HMAC token keyed with the published public key must not pass
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	forged.Header["kid"] = "key-1"
+	signed, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))
+	assert.NoError(t, err)
+
+	_, err = verify(set, signed)
+	assert.Error(t, err)
+}
+
+func TestNewHMACKeySet(t *testing.T) {
+	set := NewHMACKeySet("secret")
+
+	signed, err := set.Sign(claims())
+	assert.NoError(t, err)
+	_, err = verify(set, signed)
+	assert.NoError(t, err)
+
+	// tokens signed before the kid header existed
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims()).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	_, err = verify(set, legacy)
+	assert.NoError(t, err)
+
+	assert.Empty(t, set.JWKS().Keys)
+}
@@ -0,0 +1,242 @@
+package store
+
+import (
+	"strconv"
+	"sync"
+	"time"
+	"trainee/internal/domain"
+)
+
+// sweepInterval is how often writes also drop everything that has expired
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	value   string
+	expires time.Time
+}
+
+type memorySession struct {
+	session domain.Session
+	expires time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	lastSweep time.Time
+	values    map[string]memoryEntry
+	sessions  map[string]memorySession
+	users     map[int64]map[string]struct{}
+}
+
+// NewMemorySessionStore keeps everything in the process, for tests and a single instance without Redis.
+// now is the clock entries expire by, nil means time.Now.
+func NewMemorySessionStore(now func() time.Time) SessionStore {
+	if now == nil {
+		now = time.Now
+	}
+	return &memoryStore{
+		now:       now,
+		lastSweep: now(),
+		values:    make(map[string]memoryEntry),
+		sessions:  make(map[string]memorySession),
+		users:     make(map[int64]map[string]struct{}),
+	}
+}
+
+func (s *memoryStore) SaveSession(session domain.Session, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep()
+
+	ttl = sessionTTL(session, ttl, s.now())
+	if ttl <= 0 {
+		return ErrNotFound
+	}
+	s.sessions[session.ID] = memorySession{session: session, expires: s.now().Add(ttl)}
+	if s.users[session.UserID] == nil {
+		s.users[session.UserID] = make(map[string]struct{})
+	}
+	s.users[session.UserID][session.ID] = struct{}{}
+	return nil
+}
+
+func (s *memoryStore) GetSession(id string) (domain.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.session(id)
+	if !ok {
+		return domain.Session{}, ErrNotFound
+	}
+	return session.session, nil
+}
+
+// UpdateSession holds the store locked while update runs, update must not use the store.
+func (s *memoryStore) UpdateSession(id string, ttl time.Duration, update func(session *domain.Session) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.session(id)
+	if !ok {
+		return ErrNotFound
+	}
+	session := stored.session
+	err := update(&session)
+	if err != nil {
+		return err
+	}
+	ttl = sessionTTL(session, ttl, s.now())
+	if ttl <= 0 {
+		delete(s.sessions, id)
+		delete(s.users[session.UserID], id)
+		return ErrNotFound
+	}
+	s.sessions[id] = memorySession{session: session, expires: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *memoryStore) DeleteSession(id string, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+	delete(s.users[userID], id)
+	return nil
+}
+
+func (s *memoryStore) UserSessions(userID int64) ([]domain.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := make([]domain.Session, 0, len(s.users[userID]))
+	for id := range s.users[userID] {
+		session, ok := s.session(id)
+		if !ok {
+			delete(s.users[userID], id)
+			continue
+		}
+		sessions = append(sessions, session.session)
+	}
+	return sessions, nil
+}
+
+func (s *memoryStore) DeleteUserSessions(userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.users[userID] {
+		delete(s.sessions, id)
+	}
+	delete(s.users, userID)
+	return nil
+}
+
+func (s *memoryStore) Set(key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep()
+
+	s.values[key] = memoryEntry{value: value, expires: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *memoryStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.value(key)
+	if !ok {
+		return "", ErrNotFound
+	}
+	return entry.value, nil
+}
+
+func (s *memoryStore) Take(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.value(key)
+	if !ok {
+		return "", ErrNotFound
+	}
+	delete(s.values, key)
+	return entry.value, nil
+}
+
+func (s *memoryStore) Incr(key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep()
+
+	var n int64
+	if entry, ok := s.value(key); ok {
+		var err error
+		n, err = strconv.ParseInt(entry.value, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+	}
+	n++
+	s.values[key] = memoryEntry{value: strconv.FormatInt(n, 10), expires: s.now().Add(ttl)}
+	return n, nil
+}
+
+func (s *memoryStore) TTL(key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.value(key)
+	if !ok {
+		return 0, nil
+	}
+	return entry.expires.Sub(s.now()), nil
+}
+
+func (s *memoryStore) Delete(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.values, key)
+	}
+	return nil
+}
+
+// value and session return live entries only and drop the expired one they find, the lock must be held.
+func (s *memoryStore) value(key string) (memoryEntry, bool) {
+	entry, ok := s.values[key]
+	if ok && !s.now().Before(entry.expires) {
+		delete(s.values, key)
+		return memoryEntry{}, false
+	}
+	return entry, ok
+}
+
+func (s *memoryStore) session(id string) (memorySession, bool) {
+	session, ok := s.sessions[id]
+	if ok && !s.now().Before(session.expires) {
+		delete(s.sessions, id)
+		return memorySession{}, false
+	}
+	return session, ok
+}
+
+func (s *memoryStore) sweep() {
+	now := s.now()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, entry := range s.values {
+		if !now.Before(entry.expires) {
+			delete(s.values, key)
+		}
+	}
+	for id, session := range s.sessions {
+		if !now.Before(session.expires) {
+			delete(s.sessions, id)
+			delete(s.users[session.session.UserID], id)
+		}
+	}
+}
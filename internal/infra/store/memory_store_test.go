@@ -0,0 +1,163 @@
+package store
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+	"trainee/internal/domain"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestMemoryStore_Sessions(t *testing.T) {
+	c := &clock{now: time.Now()}
+	s := NewMemorySessionStore(c.Now)
+
+	assert.NoError(t, s.SaveSession(domain.Session{ID: "a", UserID: 1}, time.Minute))
+	assert.NoError(t, s.SaveSession(domain.Session{ID: "b", UserID: 1}, time.Hour))
+	assert.NoError(t, s.SaveSession(domain.Session{ID: "c", UserID: 2}, time.Hour))
+
+	session, err := s.GetSession("a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), session.UserID)
+
+	err = s.UpdateSession("b", time.Hour, func(session *domain.Session) error {
+		session.AccessID = "access"
+		return nil
+	})
+	assert.NoError(t, err)
+	failed := errors.New("failed")
+	err = s.UpdateSession("b", time.Hour, func(session *domain.Session) error {
+		session.AccessID = "lost"
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	session, _ = s.GetSession("b")
+	assert.Equal(t, "access", session.AccessID)
+
+	c.now = c.now.Add(2 * time.Minute)
+	_, err = s.GetSession("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, s.UpdateSession("a", time.Hour, func(*domain.Session) error { return nil }), ErrNotFound)
+	sessions, err := s.UserSessions(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Session{{ID: "b", UserID: 1, AccessID: "access"}}, sessions)
+
+	assert.NoError(t, s.DeleteUserSessions(1))
+	sessions, _ = s.UserSessions(1)
+	assert.Empty(t, sessions)
+	_, err = s.GetSession("c")
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.DeleteSession("c", 2))
+	_, err = s.GetSession("c")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryStore_SessionExpiresAt(t *testing.T) {
+	c := &clock{now: time.Now()}
+	s := NewMemorySessionStore(c.Now)
+
+	expiresAt := c.now.Add(15 * time.Minute)
+	assert.ErrorIs(t, s.SaveSession(domain.Session{ID: "old", UserID: 1, ExpiresAt: c.now}, time.Hour), ErrNotFound)
+	assert.NoError(t, s.SaveSession(domain.Session{ID: "a", UserID: 1, ExpiresAt: expiresAt}, 10*time.Minute))
+
+	// updates extend the ttl, but not past ExpiresAt
+	c.now = c.now.Add(9 * time.Minute)
+	assert.NoError(t, s.UpdateSession("a", 10*time.Minute, func(*domain.Session) error { return nil }))
+	c.now = c.now.Add(5 * time.Minute)
+	assert.NoError(t, s.UpdateSession("a", 10*time.Minute, func(*domain.Session) error { return nil }))
+	c.now = c.now.Add(2 * time.Minute)
+	_, err := s.GetSession("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// an update moving ExpiresAt into the past ends the session
+	assert.NoError(t, s.SaveSession(domain.Session{ID: "b", UserID: 1}, 10*time.Minute))
+	err = s.UpdateSession("b", 10*time.Minute, func(session *domain.Session) error {
+		session.ExpiresAt = c.now
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrNotFound)
+	sessions, err := s.UserSessions(1)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestMemoryStore_Values(t *testing.T) {
+	c := &clock{now: time.Now()}
+	s := NewMemorySessionStore(c.Now)
+
+	assert.NoError(t, s.Set("state", "1", time.Minute))
+	value, err := s.Get("state")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", value)
+	ttl, _ := s.TTL("state")
+	assert.Equal(t, time.Minute, ttl)
+
+	value, err = s.Take("state")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", value)
+	_, err = s.Take("state")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	n, _ := s.Incr("counter", time.Minute)
+	assert.Equal(t, int64(1), n)
+	c.now = c.now.Add(50 * time.Second)
+	n, _ = s.Incr("counter", time.Minute)
+	assert.Equal(t, int64(2), n)
+	// every increment restarts the expiry
+	c.now = c.now.Add(50 * time.Second)
+	n, _ = s.Incr("counter", time.Minute)
+	assert.Equal(t, int64(3), n)
+	c.now = c.now.Add(time.Minute)
+	n, _ = s.Incr("counter", time.Minute)
+	assert.Equal(t, int64(1), n)
+
+	assert.NoError(t, s.Delete("counter"))
+	ttl, err = s.TTL("counter")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), ttl)
+	_, err = s.Get("counter")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryStore_Concurrent(t *testing.T) {
+	s := NewMemorySessionStore(nil)
+	assert.NoError(t, s.Set("once", "1", time.Minute))
+	assert.NoError(t, s.SaveSession(domain.Session{ID: "a", UserID: 1}, time.Minute))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	taken := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = s.Incr("counter", time.Minute)
+			_ = s.UpdateSession("a", time.Minute, func(session *domain.Session) error {
+				session.LastSeenAt = session.LastSeenAt.Add(time.Second)
+				return nil
+			})
+			if _, err := s.Take("once"); err == nil {
+				mu.Lock()
+				taken++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, _ := s.Get("counter")
+	assert.Equal(t, "50", value)
+	session, _ := s.GetSession("a")
+	assert.Equal(t, time.Time{}.Add(50*time.Second), session.LastSeenAt)
+	assert.Equal(t, 1, taken)
+}
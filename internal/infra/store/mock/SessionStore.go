@@ -0,0 +1,249 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "trainee/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SessionStore is an autogenerated mock type for the SessionStore type
+type SessionStore struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: keys
+func (_m *SessionStore) Delete(keys ...string) error {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(...string) error); ok {
+		r0 = rf(keys...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSession provides a mock function with given fields: id, userID
+func (_m *SessionStore) DeleteSession(id string, userID int64) error {
+	ret := _m.Called(id, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64) error); ok {
+		r0 = rf(id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUserSessions provides a mock function with given fields: userID
+func (_m *SessionStore) DeleteUserSessions(userID int64) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: key
+func (_m *SessionStore) Get(key string) (string, error) {
+	ret := _m.Called(key)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSession provides a mock function with given fields: id
+func (_m *SessionStore) GetSession(id string) (domain.Session, error) {
+	ret := _m.Called(id)
+
+	var r0 domain.Session
+	if rf, ok := ret.Get(0).(func(string) domain.Session); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(domain.Session)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Incr provides a mock function with given fields: key, ttl
+func (_m *SessionStore) Incr(key string, ttl time.Duration) (int64, error) {
+	ret := _m.Called(key, ttl)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(string, time.Duration) int64); ok {
+		r0 = rf(key, ttl)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(key, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveSession provides a mock function with given fields: session, ttl
+func (_m *SessionStore) SaveSession(session domain.Session, ttl time.Duration) error {
+	ret := _m.Called(session, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.Session, time.Duration) error); ok {
+		r0 = rf(session, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Set provides a mock function with given fields: key, value, ttl
+func (_m *SessionStore) Set(key string, value string, ttl time.Duration) error {
+	ret := _m.Called(key, value, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Duration) error); ok {
+		r0 = rf(key, value, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TTL provides a mock function with given fields: key
+func (_m *SessionStore) TTL(key string) (time.Duration, error) {
+	ret := _m.Called(key)
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func(string) time.Duration); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Take provides a mock function with given fields: key
+func (_m *SessionStore) Take(key string) (string, error) {
+	ret := _m.Called(key)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSession provides a mock function with given fields: id, ttl, update
+func (_m *SessionStore) UpdateSession(id string, ttl time.Duration, update func(*domain.Session) error) error {
+	ret := _m.Called(id, ttl, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration, func(*domain.Session) error) error); ok {
+		r0 = rf(id, ttl, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserSessions provides a mock function with given fields: userID
+func (_m *SessionStore) UserSessions(userID int64) ([]domain.Session, error) {
+	ret := _m.Called(userID)
+
+	var r0 []domain.Session
+	if rf, ok := ret.Get(0).(func(int64) []domain.Session); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewSessionStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSessionStore creates a new instance of SessionStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSessionStore(t mockConstructorTestingTNewSessionStore) *SessionStore {
+	mock := &SessionStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,263 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v7"
+	"time"
+	"trainee/internal/domain"
+)
+
+// indexSession adds a session to the user's index and keeps the index at least as long as the session, in ms.
+// Sessions drop out of the index as they expire.
+const indexSession = `
+redis.call("SADD", KEYS[1], ARGV[1])
+if redis.call("PTTL", KEYS[1]) < tonumber(ARGV[2]) then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 1`
+
+type redisSession struct {
+	AccessID   string    `json:"access"`
+	RefreshID  string    `json:"refresh"`
+	ID         string    `json:"id"`
+	UserID     int64     `json:"user_id"`
+	ActorID    int64     `json:"actor_id,omitempty"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (s redisSession) toDomain() domain.Session {
+	return domain.Session{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		ActorID:    s.ActorID,
+		AccessID:   s.AccessID,
+		RefreshID:  s.RefreshID,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+}
+
+func sessionToRedis(s domain.Session) redisSession {
+	return redisSession{
+		AccessID:   s.AccessID,
+		RefreshID:  s.RefreshID,
+		ID:         s.ID,
+		UserID:     s.UserID,
+		ActorID:    s.ActorID,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+}
+
+type redisStore struct {
+	r *redis.Client
+}
+
+func NewRedisSessionStore(red *redis.Client) SessionStore {
+	return redisStore{
+		r: red,
+	}
+}
+
+func (s redisStore) SaveSession(session domain.Session, ttl time.Duration) error {
+	// redis keeps a key without a ttl forever, so an expired session must not reach it
+	ttl = sessionTTL(session, ttl, time.Now())
+	if ttl <= 0 {
+		return ErrNotFound
+	}
+	sessionJSON, err := json.Marshal(sessionToRedis(session))
+	if err != nil {
+		return fmt.Errorf("couldn't marshal session, %w", err)
+	}
+	_, err = s.r.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Set(sessionKey(session.ID), string(sessionJSON), ttl)
+		pipe.Eval(indexSession, []string{userSessionsKey(session.UserID)}, session.ID, ttl.Milliseconds())
+		return nil
+	})
+	return err
+}
+
+func (s redisStore) GetSession(id string) (domain.Session, error) {
+	return getSession(s.r, id)
+}
+
+type getter interface {
+	Get(key string) *redis.StringCmd
+}
+
+func getSession(r getter, id string) (domain.Session, error) {
+	sessionJSON, err := r.Get(sessionKey(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return domain.Session{}, ErrNotFound
+	} else if err != nil {
+		return domain.Session{}, err
+	}
+	var session redisSession
+	err = json.Unmarshal([]byte(sessionJSON), &session)
+	if err != nil {
+		return domain.Session{}, fmt.Errorf("couldn't unmarshal session, %w", err)
+	}
+	return session.toDomain(), nil
+}
+
+func (s redisStore) UpdateSession(id string, ttl time.Duration, update func(session *domain.Session) error) error {
+	key := sessionKey(id)
+	err := s.r.Watch(func(tx *redis.Tx) error {
+		session, err := getSession(tx, id)
+		if err != nil {
+			return err
+		}
+		err = update(&session)
+		if err != nil {
+			return err
+		}
+		ttl := sessionTTL(session, ttl, time.Now())
+		if ttl <= 0 {
+			_, err = tx.TxPipelined(func(pipe redis.Pipeliner) error {
+				pipe.Del(key)
+				pipe.SRem(userSessionsKey(session.UserID), id)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return ErrNotFound
+		}
+		sessionJSON, err := json.Marshal(sessionToRedis(session))
+		if err != nil {
+			return fmt.Errorf("couldn't marshal session, %w", err)
+		}
+		_, err = tx.TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.Set(key, string(sessionJSON), ttl)
+			pipe.Eval(indexSession, []string{userSessionsKey(session.UserID)}, session.ID, ttl.Milliseconds())
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s redisStore) DeleteSession(id string, userID int64) error {
+	_, err := s.r.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(sessionKey(id))
+		pipe.SRem(userSessionsKey(userID), id)
+		return nil
+	})
+	return err
+}
+
+func (s redisStore) UserSessions(userID int64) ([]domain.Session, error) {
+	ids, err := s.r.SMembers(userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]domain.Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := s.GetSession(id)
+		if errors.Is(err, ErrNotFound) {
+			// the session expired on its own, drop the dangling reference
+			s.r.SRem(userSessionsKey(userID), id)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (s redisStore) DeleteUserSessions(userID int64) error {
+	ids, err := s.r.SMembers(userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, sessionKey(id))
+	}
+	keys = append(keys, userSessionsKey(userID))
+	return s.r.Del(keys...).Err()
+}
+
+func (s redisStore) Set(key, value string, ttl time.Duration) error {
+	return s.r.Set(key, value, ttl).Err()
+}
+
+func (s redisStore) Get(key string) (string, error) {
+	value, err := s.r.Get(key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+	return value, err
+}
+
+func (s redisStore) Take(key string) (string, error) {
+	var get *redis.StringCmd
+	var del *redis.IntCmd
+	_, err := s.r.TxPipelined(func(pipe redis.Pipeliner) error {
+		get = pipe.Get(key)
+		del = pipe.Del(key)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", err
+	}
+	if del.Val() != 1 {
+		return "", ErrNotFound
+	}
+	return get.Val(), nil
+}
+
+func (s redisStore) Incr(key string, ttl time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := s.r.TxPipelined(func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(key)
+		pipe.Expire(key, ttl)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (s redisStore) TTL(key string) (time.Duration, error) {
+	ttl, err := s.r.PTTL(key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		// -2: no key, -1: no expiry, which the store never sets
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (s redisStore) Delete(keys ...string) error {
+	return s.r.Del(keys...).Err()
+}
+
+func sessionKey(sessionID string) string {
+	return fmt.Sprintf("session-%s", sessionID)
+}
+
+func userSessionsKey(userID int64) string {
+	return fmt.Sprintf("sessions-%d", userID)
+}
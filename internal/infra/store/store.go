@@ -0,0 +1,52 @@
+package store
+
+import (
+	"errors"
+	"time"
+	"trainee/internal/domain"
+)
+
+var (
+	ErrNotFound = errors.New("not found in store")
+	// ErrConflict means the session changed while it was being updated, the update was not applied.
+	ErrConflict = errors.New("concurrent update in store")
+)
+
+// SessionStore keeps login sessions and the short-lived values of the auth flow,
+// such as pending mfa logins, oauth states and failed login counters. Everything in it expires.
+//
+//go:generate mockery --dir . --name SessionStore --output ./mock
+type SessionStore interface {
+	// SaveSession keeps the session for ttl, but not past its ExpiresAt. A session already past it is not saved
+	// and gives ErrNotFound.
+	SaveSession(session domain.Session, ttl time.Duration) error
+	GetSession(id string) (domain.Session, error)
+	// UpdateSession applies update to the stored session and saves it with a new ttl, all or nothing. The ttl is
+	// cut short at the session's ExpiresAt like in SaveSession, a session past it is deleted and gives ErrNotFound.
+	// An error from update is returned as is and leaves the session unchanged.
+	UpdateSession(id string, ttl time.Duration, update func(session *domain.Session) error) error
+	DeleteSession(id string, userID int64) error
+	UserSessions(userID int64) ([]domain.Session, error)
+	DeleteUserSessions(userID int64) error
+
+	Set(key, value string, ttl time.Duration) error
+	Get(key string) (string, error)
+	// Take gets and deletes the value, of concurrent callers only one gets it.
+	Take(key string) (string, error)
+	// Incr increments the counter and expires it ttl after this increment.
+	Incr(key string, ttl time.Duration) (int64, error)
+	// TTL is how long the value has left, 0 when there is none.
+	TTL(key string) (time.Duration, error)
+	Delete(keys ...string) error
+}
+
+// sessionTTL is how long a session saved for ttl at now is kept, sessions without ExpiresAt only expire when idle.
+func sessionTTL(session domain.Session, ttl time.Duration, now time.Time) time.Duration {
+	if session.ExpiresAt.IsZero() {
+		return ttl
+	}
+	if left := session.ExpiresAt.Sub(now); left < ttl {
+		return left
+	}
+	return ttl
+}
@@ -1,65 +1,190 @@
 package middleware
 
 import (
-	"fmt"
-	"github.com/go-redis/redis/v7"
+	"errors"
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	MW "github.com/labstack/echo/v4/middleware"
+	"log"
 	"net/http"
-	"time"
+	"strings"
 	"trainee/internal/app"
+	"trainee/internal/domain"
 	"trainee/internal/infra/http/response"
+	"trainee/internal/infra/signing"
 )
 
 type AuthMiddleware interface {
-	JWT(secret string) echo.MiddlewareFunc
+	APIKey() echo.MiddlewareFunc
+	JWT() echo.MiddlewareFunc
 	ValidateJWT() echo.MiddlewareFunc
+	RequireRole(roles ...domain.Role) echo.MiddlewareFunc
+	RequirePermission(permission domain.Permission) echo.MiddlewareFunc
+	RequireVerifiedEmail() echo.MiddlewareFunc
+	DenyImpersonation() echo.MiddlewareFunc
 }
 
 type authMiddleware struct {
-	authService app.AuthService
-	r           *redis.Client
+	authService     app.AuthService
+	apiTokenService app.APITokenService
+	keys            *signing.KeySet
 }
 
-func NewMiddleware(as app.AuthService, red *redis.Client) AuthMiddleware {
+func NewMiddleware(as app.AuthService, ts app.APITokenService, keys *signing.KeySet) AuthMiddleware {
 	return authMiddleware{
-		authService: as,
-		r:           red,
+		authService:     as,
+		apiTokenService: ts,
+		keys:            keys,
 	}
 }
 
+// APIKey authenticates requests carrying a personal access token, in X-API-Key or as the Bearer token.
+// It must run before JWT, which then leaves the request alone. A read scope allows only safe methods.
+func (m authMiddleware) APIKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := apiKey(c.Request())
+			if key == "" {
+				return next(c)
+			}
+
+			user, token, err := m.apiTokenService.Authenticate(key)
+			if err != nil {
+				return response.MessageResponse(c, http.StatusUnauthorized, "Not authorized")
+			}
+			scope := domain.ScopeWrite
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				scope = domain.ScopeRead
+			}
+			err = m.apiTokenService.Authorize(token, scope, domain.Device{UserAgent: c.Request().UserAgent(), IP: c.RealIP()})
+			if err != nil {
+				return response.MessageResponse(c, http.StatusForbidden, "Forbidden")
+			}
+
+			// handlers read the caller from the jwt claims, so the token is presented the same way
+			c.Set("user", jwt.NewWithClaims(jwt.SigningMethodNone, &app.JwtTokenClaim{
+				Name: user.Name,
+				ID:   user.ID,
+				Role: user.Role,
+			}))
+			c.Set("currentUser", user)
+			c.Set("apiToken", token)
+
+			go func() {
+				if err := m.apiTokenService.Touch(token.ID); err != nil {
+					log.Print(err)
+				}
+			}()
+			return next(c)
+		}
+	}
+}
+
+func apiKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	auth := r.Header.Get(echo.HeaderAuthorization)
+	if strings.HasPrefix(auth, "Bearer "+app.APITokenPrefix) {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
+
+func viaAPIKey(c echo.Context) bool {
+	_, ok := c.Get("apiToken").(domain.APIToken)
+	return ok
+}
+
 func (m authMiddleware) ValidateJWT() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if viaAPIKey(c) {
+				return next(c)
+			}
 			token := c.Get("user").(*jwt.Token)
 			claims := token.Claims.(*app.JwtTokenClaim)
 
-			user, err := m.authService.ValidateJWT(claims.UID, claims.ID, false)
+			user, err := m.authService.ValidateJWT(claims.UID, claims.SID, claims.ID, claims.Role, false)
 			if err != nil {
 				return response.MessageResponse(c, http.StatusUnauthorized, "Not authorized")
 			}
 
+			// the session is extended before the request is served, so it can't outlive a failed extension
+			err = m.authService.TouchSession(claims.SID)
+			if errors.Is(err, app.ErrSessionNotFound) {
+				return response.MessageResponse(c, http.StatusUnauthorized, "Not authorized")
+			} else if err != nil {
+				log.Print(err)
+				return response.MessageResponse(c, http.StatusInternalServerError, "Could not extend session")
+			}
+
 			c.Set("currentUser", user)
+			return next(c)
+		}
+	}
+}
 
-			go func() {
-				m.r.Expire(fmt.Sprintf("token-%d", claims.ID), time.Minute*app.LogOF)
-			}()
+// RequireRole must run after ValidateJWT, it only lets through users whose token carries one of the roles.
+func (m authMiddleware) RequireRole(roles ...domain.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := c.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+			for _, role := range roles {
+				if claims.Role == role {
+					return next(c)
+				}
+			}
+			return response.MessageResponse(c, http.StatusForbidden, "Forbidden")
+		}
+	}
+}
+
+// RequirePermission is RequireRole for the roles granted the permission, routes declare what they need
+// rather than who may do it.
+func (m authMiddleware) RequirePermission(permission domain.Permission) echo.MiddlewareFunc {
+	return m.RequireRole(domain.RolesWith(permission)...)
+}
+
+// RequireVerifiedEmail must run after ValidateJWT, it uses the user loaded there.
+func (m authMiddleware) RequireVerifiedEmail() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Get("currentUser").(domain.User)
+			if !ok || !user.EmailVerified() {
+				return response.MessageResponse(c, http.StatusForbidden, "Email is not verified")
+			}
+			return next(c)
+		}
+	}
+}
+
+// DenyImpersonation must run after JWT, it keeps impersonation tokens away from actions only the user may take.
+func (m authMiddleware) DenyImpersonation() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := c.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim)
+			if claims.Act != nil {
+				return response.MessageResponse(c, http.StatusForbidden, "Not allowed while impersonating")
+			}
 			return next(c)
 		}
 	}
 }
 
-func (m authMiddleware) JWT(secret string) echo.MiddlewareFunc {
+// JWT verifies access tokens with the key named by their kid header.
+func (m authMiddleware) JWT() echo.MiddlewareFunc {
 	config := MW.JWTConfig{
+		Skipper: viaAPIKey,
 		ErrorHandler: func(err error) error {
 			return &echo.HTTPError{
 				Code:    http.StatusUnauthorized,
 				Message: "Not authorized",
 			}
 		},
-		SigningKey: []byte(secret),
-		Claims:     &app.JwtTokenClaim{},
+		KeyFunc: m.keys.Keyfunc,
+		Claims:  &app.JwtTokenClaim{},
 	}
 	return MW.JWTWithConfig(config)
 }
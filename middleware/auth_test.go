@@ -0,0 +1,283 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"trainee/internal/app"
+	"trainee/internal/app/mocks"
+	"trainee/internal/domain"
+	"trainee/internal/infra/signing"
+)
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	user := domain.User{ID: 2, Name: "Name", Role: domain.RoleUser}
+	readToken := domain.APIToken{ID: 7, UserID: 2, Scopes: []domain.Scope{domain.ScopeRead}, ExpiresDate: time.Now().Add(time.Hour)}
+	device := domain.Device{UserAgent: "cli/1.0", IP: "192.0.2.1"}
+
+	tests := []struct {
+		name       string
+		method     string
+		header     string
+		value      string
+		ts         func() app.APITokenService
+		wantStatus int
+		wantUserID int64
+	}{
+		{
+			"read token on get",
+			http.MethodGet,
+			"X-API-Key",
+			"pat_token",
+			func() app.APITokenService {
+				mock := mocks.NewAPITokenService(t)
+				mock.On("Authenticate", "pat_token").Return(user, readToken, nil).Times(1)
+				mock.On("Authorize", readToken, domain.ScopeRead, device).Return(nil).Times(1)
+				mock.On("Touch", int64(7)).Return(nil).Maybe()
+				return mock
+			},
+			http.StatusOK,
+			2,
+		},
+		{
+			"bearer personal token",
+			http.MethodGet,
+			echo.HeaderAuthorization,
+			"Bearer pat_token",
+			func() app.APITokenService {
+				mock := mocks.NewAPITokenService(t)
+				mock.On("Authenticate", "pat_token").Return(user, readToken, nil).Times(1)
+				mock.On("Authorize", readToken, domain.ScopeRead, device).Return(nil).Times(1)
+				mock.On("Touch", int64(7)).Return(nil).Maybe()
+				return mock
+			},
+			http.StatusOK,
+			2,
+		},
+		{
+			"read token on post",
+			http.MethodPost,
+			"X-API-Key",
+			"pat_token",
+			func() app.APITokenService {
+				mock := mocks.NewAPITokenService(t)
+				mock.On("Authenticate", "pat_token").Return(user, readToken, nil).Times(1)
+				mock.On("Authorize", readToken, domain.ScopeWrite, device).Return(app.ErrScopeNotAllowed).Times(1)
+				return mock
+			},
+			http.StatusForbidden,
+			0,
+		},
+		{
+			"invalid token",
+			http.MethodGet,
+			"X-API-Key",
+			"pat_wrong",
+			func() app.APITokenService {
+				mock := mocks.NewAPITokenService(t)
+				mock.On("Authenticate", "pat_wrong").Return(domain.User{}, domain.APIToken{}, app.ErrInvalidToken).Times(1)
+				return mock
+			},
+			http.StatusUnauthorized,
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiddleware(mocks.NewAuthService(t), tt.ts(), signing.NewHMACKeySet("secret"))
+			e := echo.New()
+			req := httptest.NewRequest(tt.method, "/api/v1/posts/post/1", nil)
+			req.Header.Set(tt.header, tt.value)
+			req.Header.Set("User-Agent", device.UserAgent)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var userID int64
+			handler := func(c echo.Context) error {
+				userID = c.Get("user").(*jwt.Token).Claims.(*app.JwtTokenClaim).ID
+				return c.NoContent(http.StatusOK)
+			}
+			// the jwt middlewares must let a request authenticated by api key through untouched
+			chain := m.APIKey()(m.JWT()(m.ValidateJWT()(handler)))
+
+			err := chain(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantUserID, userID)
+		})
+	}
+}
+
+func TestAuthMiddleware_APIKeyWithoutKey(t *testing.T) {
+	m := NewMiddleware(mocks.NewAuthService(t), mocks.NewAPITokenService(t), signing.NewHMACKeySet("secret"))
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/posts/post/1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	called := false
+	err := m.APIKey()(func(c echo.Context) error {
+		called = true
+		return nil
+	})(c)
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Nil(t, c.Get("user"))
+}
+
+func TestAuthMiddleware_DenyImpersonation(t *testing.T) {
+	tests := []struct {
+		name       string
+		act        *app.ActorClaim
+		wantStatus int
+	}{
+		{"own token", nil, http.StatusOK},
+		{"impersonation token", &app.ActorClaim{ID: 1, Name: "Admin"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiddleware(mocks.NewAuthService(t), mocks.NewAPITokenService(t), signing.NewHMACKeySet("secret"))
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/me/password", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, &app.JwtTokenClaim{ID: 2, Act: tt.act}))
+			handler := func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}
+			err := m.DenyImpersonation()(handler)(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_RequirePermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       domain.Role
+		permission domain.Permission
+		wantStatus int
+	}{
+		{"admin manages users", domain.RoleAdmin, domain.PermissionManageUsers, http.StatusOK},
+		{"moderator doesn't manage users", domain.RoleModerator, domain.PermissionManageUsers, http.StatusForbidden},
+		{"moderator deletes any comment", domain.RoleModerator, domain.PermissionDeleteAnyComment, http.StatusOK},
+		{"user doesn't manage tags", domain.RoleUser, domain.PermissionManageTags, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiddleware(mocks.NewAuthService(t), mocks.NewAPITokenService(t), signing.NewHMACKeySet("secret"))
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/1", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, &app.JwtTokenClaim{ID: 2, Role: tt.role}))
+			handler := func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}
+			err := m.RequirePermission(tt.permission)(handler)(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_RequireRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       domain.Role
+		roles      []domain.Role
+		wantStatus int
+	}{
+		{"listed role", domain.RoleModerator, []domain.Role{domain.RoleModerator, domain.RoleAdmin}, http.StatusOK},
+		{"unlisted role", domain.RoleUser, []domain.Role{domain.RoleModerator, domain.RoleAdmin}, http.StatusForbidden},
+		{"no roles", domain.RoleAdmin, nil, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiddleware(mocks.NewAuthService(t), mocks.NewAPITokenService(t), signing.NewHMACKeySet("secret"))
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/1", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, &app.JwtTokenClaim{ID: 2, Role: tt.role}))
+			handler := func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}
+			err := m.RequireRole(tt.roles...)(handler)(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_ValidateJWT(t *testing.T) {
+	user := domain.User{ID: 2, Name: "Name", Role: domain.RoleUser}
+	claims := &app.JwtTokenClaim{ID: 2, UID: "access-uid", SID: "session", Role: domain.RoleUser}
+	tests := []struct {
+		name       string
+		as         func() app.AuthService
+		wantStatus int
+	}{
+		{
+			"valid session",
+			func() app.AuthService {
+				mock := mocks.NewAuthService(t)
+				mock.On("ValidateJWT", "access-uid", "session", int64(2), domain.RoleUser, false).Return(user, nil).Times(1)
+				mock.On("TouchSession", "session").Return(nil).Times(1)
+				return mock
+			},
+			http.StatusOK,
+		},
+		{
+			"invalid token",
+			func() app.AuthService {
+				mock := mocks.NewAuthService(t)
+				mock.On("ValidateJWT", "access-uid", "session", int64(2), domain.RoleUser, false).Return(domain.User{}, app.ErrInvalidToken).Times(1)
+				return mock
+			},
+			http.StatusUnauthorized,
+		},
+		{
+			"session ended before the touch",
+			func() app.AuthService {
+				mock := mocks.NewAuthService(t)
+				mock.On("ValidateJWT", "access-uid", "session", int64(2), domain.RoleUser, false).Return(user, nil).Times(1)
+				mock.On("TouchSession", "session").Return(app.ErrSessionNotFound).Times(1)
+				return mock
+			},
+			http.StatusUnauthorized,
+		},
+		{
+			"touch failed",
+			func() app.AuthService {
+				mock := mocks.NewAuthService(t)
+				mock.On("ValidateJWT", "access-uid", "session", int64(2), domain.RoleUser, false).Return(user, nil).Times(1)
+				mock.On("TouchSession", "session").Return(errors.New("connection refused")).Times(1)
+				return mock
+			},
+			http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMiddleware(tt.as(), mocks.NewAPITokenService(t), signing.NewHMACKeySet("secret"))
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/sessions", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user", jwt.NewWithClaims(jwt.SigningMethodHS256, claims))
+			handler := func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}
+			err := m.ValidateJWT()(handler)(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
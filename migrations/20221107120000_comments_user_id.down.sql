@@ -0,0 +1,2 @@
+alter table if exists public.commentses
+drop column user_id;
@@ -0,0 +1,7 @@
+alter table if exists public.commentses
+add user_id integer not null default 0;
+
+update public.commentses c
+set user_id = u.id
+from public.users u
+where u.email = c.email;
@@ -0,0 +1,2 @@
+alter table if exists public.users
+add role varchar(20) not null default 'user';
@@ -0,0 +1,4 @@
+drop table if exists public.user_identities;
+
+alter table if exists public.users
+alter column password drop default;
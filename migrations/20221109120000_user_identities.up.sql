@@ -0,0 +1,13 @@
+alter table if exists public.users
+alter column password set default '';
+
+create table if not exists public.user_identities
+(
+    id           serial primary key,
+    user_id      integer      not null references public.users (id) on delete cascade,
+    provider     varchar(50)  not null,
+    subject      varchar(255) not null,
+    email        varchar(100) not null,
+    created_date timestamp,
+    unique (provider, subject)
+);
@@ -0,0 +1,10 @@
+create table if not exists public.user_tokens
+(
+    id           serial primary key,
+    user_id      integer     not null references public.users (id) on delete cascade,
+    purpose      varchar(50) not null,
+    token_hash   varchar(64) not null unique,
+    expires_date timestamp   not null,
+    used_date    timestamp,
+    created_date timestamp
+);
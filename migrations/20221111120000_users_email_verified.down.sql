@@ -0,0 +1,2 @@
+alter table if exists public.users
+drop column if exists email_verified_at;
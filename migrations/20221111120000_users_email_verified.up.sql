@@ -0,0 +1,7 @@
+alter table if exists public.users
+add column if not exists email_verified_at timestamp;
+
+-- accounts created before verification existed are trusted as they are
+update public.users
+set email_verified_at = created_date
+where email_verified_at is null;
@@ -0,0 +1,8 @@
+drop table if exists public.mfa_recovery_codes;
+
+alter table if exists public.users
+drop column if exists mfa_last_counter;
+alter table if exists public.users
+drop column if exists mfa_enabled_at;
+alter table if exists public.users
+drop column if exists mfa_secret;
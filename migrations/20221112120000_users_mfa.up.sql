@@ -0,0 +1,16 @@
+alter table if exists public.users
+add column if not exists mfa_secret text not null default '';
+alter table if exists public.users
+add column if not exists mfa_enabled_at timestamp;
+alter table if exists public.users
+add column if not exists mfa_last_counter bigint not null default 0;
+
+create table if not exists public.mfa_recovery_codes
+(
+    id           serial primary key,
+    user_id      integer     not null references public.users (id) on delete cascade,
+    code_hash    varchar(64) not null,
+    used_date    timestamp,
+    created_date timestamp,
+    unique (user_id, code_hash)
+);
@@ -0,0 +1,12 @@
+create table if not exists public.api_tokens
+(
+    id             serial primary key,
+    user_id        integer      not null references public.users (id) on delete cascade,
+    name           varchar(100) not null,
+    prefix         varchar(20)  not null,
+    token_hash     varchar(64)  not null unique,
+    scopes         varchar(100) not null,
+    expires_date   timestamp    not null,
+    last_used_date timestamp,
+    created_date   timestamp
+);
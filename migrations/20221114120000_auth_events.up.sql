@@ -0,0 +1,15 @@
+create table if not exists public.auth_events
+(
+    id           bigserial primary key,
+    user_id      integer references public.users (id) on delete cascade,
+    email        varchar(100) not null default '',
+    event        varchar(20)  not null,
+    outcome      varchar(10)  not null,
+    detail       varchar(100) not null default '',
+    ip           varchar(45)  not null default '',
+    user_agent   varchar(255) not null default '',
+    created_date timestamp    not null
+);
+
+create index if not exists auth_events_user_id_idx on public.auth_events (user_id, id);
+create index if not exists auth_events_email_idx on public.auth_events (email, id);
@@ -0,0 +1,2 @@
+alter table if exists public.auth_events
+drop column if exists actor_id;
@@ -0,0 +1,4 @@
+alter table if exists public.auth_events
+add column if not exists actor_id integer references public.users (id) on delete set null;
+
+create index if not exists auth_events_actor_id_idx on public.auth_events (actor_id, id);
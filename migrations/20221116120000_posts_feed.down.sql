@@ -0,0 +1,7 @@
+drop index if exists public.posts_user_created_idx;
+drop index if exists public.posts_feed_updated_idx;
+drop index if exists public.posts_feed_created_idx;
+
+alter table if exists public.posts
+alter column created_date drop not null,
+alter column updated_date drop not null;
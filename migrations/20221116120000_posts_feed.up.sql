@@ -0,0 +1,15 @@
+update public.posts
+set created_date = coalesce(updated_date, now())
+where created_date is null;
+
+update public.posts
+set updated_date = created_date
+where updated_date is null;
+
+alter table if exists public.posts
+alter column created_date set not null,
+alter column updated_date set not null;
+
+create index if not exists posts_feed_created_idx on public.posts (created_date, id) where deleted_date is null;
+create index if not exists posts_feed_updated_idx on public.posts (updated_date, id) where deleted_date is null;
+create index if not exists posts_user_created_idx on public.posts (user_id, created_date, id) where deleted_date is null;
@@ -0,0 +1,11 @@
+drop index if exists public.commentses_search_vector_idx;
+
+alter table if exists public.commentses
+drop column if exists search_vector,
+drop column if exists language;
+
+drop index if exists public.posts_search_vector_idx;
+
+alter table if exists public.posts
+drop column if exists search_vector,
+drop column if exists language;
@@ -0,0 +1,20 @@
+alter table if exists public.posts
+add column if not exists language regconfig not null default 'english';
+
+alter table if exists public.posts
+add column if not exists search_vector tsvector generated always as (
+    setweight(to_tsvector(language, coalesce(title, '')), 'A') ||
+    setweight(to_tsvector(language, coalesce(body, '')), 'B')
+) stored;
+
+create index if not exists posts_search_vector_idx on public.posts using gin (search_vector);
+
+alter table if exists public.commentses
+add column if not exists language regconfig not null default 'english';
+
+alter table if exists public.commentses
+add column if not exists search_vector tsvector generated always as (
+    to_tsvector(language, coalesce(body, ''))
+) stored;
+
+create index if not exists commentses_search_vector_idx on public.commentses using gin (search_vector);
@@ -0,0 +1,2 @@
+drop table if exists public.post_tags;
+drop table if exists public.tags;
@@ -0,0 +1,15 @@
+create table if not exists public.tags
+(
+    id           serial primary key,
+    name         varchar(50) not null unique,
+    created_date timestamp not null default now()
+);
+
+create table if not exists public.post_tags
+(
+    post_id integer not null references public.posts (id) on delete cascade,
+    tag_id  integer not null references public.tags (id) on delete cascade,
+    primary key (post_id, tag_id)
+);
+
+create index if not exists post_tags_tag_idx on public.post_tags (tag_id, post_id);
@@ -0,0 +1,5 @@
+drop index if exists public.posts_scheduled_idx;
+
+alter table if exists public.posts
+drop column if exists published_date,
+drop column if exists status;
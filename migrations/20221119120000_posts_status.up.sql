@@ -0,0 +1,10 @@
+alter table if exists public.posts
+add column if not exists status varchar(16) not null default 'published'
+    check (status in ('draft', 'scheduled', 'published', 'archived')),
+add column if not exists published_date timestamp;
+
+update public.posts
+set published_date = created_date
+where published_date is null;
+
+create index if not exists posts_scheduled_idx on public.posts (published_date) where status = 'scheduled' and deleted_date is null;
@@ -0,0 +1,15 @@
+create table if not exists public.post_revisions
+(
+    id           serial primary key,
+    post_id      integer not null references public.posts (id) on delete cascade,
+    revision     integer not null,
+    editor_id    integer not null,
+    title        varchar not null,
+    body         varchar not null,
+    created_date timestamp not null default now(),
+    unique (post_id, revision)
+);
+
+insert into public.post_revisions (post_id, revision, editor_id, title, body, created_date)
+select id, 1, user_id, title, body, updated_date
+from public.posts;